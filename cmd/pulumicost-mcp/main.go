@@ -15,7 +15,9 @@ import (
 	"github.com/rshade/pulumicost-mcp/internal/config"
 	"github.com/rshade/pulumicost-mcp/internal/logging"
 	"github.com/rshade/pulumicost-mcp/internal/metrics"
+	"github.com/rshade/pulumicost-mcp/internal/notify"
 	"github.com/rshade/pulumicost-mcp/internal/service"
+	"github.com/rshade/pulumicost-mcp/internal/toolhints"
 	"github.com/rshade/pulumicost-mcp/internal/tracing"
 	mcpcost "github.com/rshade/pulumicost-mcp/gen/mcp_cost"
 	mcpplugin "github.com/rshade/pulumicost-mcp/gen/mcp_plugin"
@@ -27,6 +29,11 @@ import (
 )
 
 func main() {
+	// Subcommands run without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidate(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	// Setup structured logger
 	logger := logging.New(logging.Config{
 		Level:  "info",
@@ -59,8 +66,6 @@ func main() {
 	pulumiAdapter := adapter.NewPulumiCostAdapter(cfg.PulumiCost.CorePath)
 	logger.Info("pulumicost adapter initialized", "core_path", cfg.PulumiCost.CorePath)
 
-	// Create services
-	costService := service.NewCostService(pulumiAdapter, logger)
 	// Plugin directory - default to ~/.pulumicost/plugins or config value
 	pluginDir := os.Getenv("PLUGIN_DIR")
 	if pluginDir == "" {
@@ -74,8 +79,58 @@ func main() {
 		}
 	}
 
-	pluginService := service.NewPluginService(pluginDir, logger)
-	analysisService := service.NewAnalysisService(nil, logger)
+	pluginTLS := make(map[string]adapter.TLSConfig, len(cfg.Plugins.TLS))
+	for name, tlsCfg := range cfg.Plugins.TLS {
+		pluginTLS[name] = adapter.TLSConfig{
+			CAFile:     tlsCfg.CAFile,
+			CertFile:   tlsCfg.CertFile,
+			KeyFile:    tlsCfg.KeyFile,
+			ServerName: tlsCfg.ServerName,
+		}
+	}
+
+	trustedKeys, err := adapter.ParseTrustedKeys(cfg.Plugins.Integrity.TrustedKeys)
+	if err != nil {
+		stdLogger.Fatalf("Invalid plugins.integrity configuration: %v", err)
+	}
+
+	pluginAdapter := adapter.NewPluginAdapterWithOptions(pluginDir, adapter.PluginAdapterOptions{
+		TLS: pluginTLS,
+		Integrity: adapter.IntegrityOptions{
+			Mode:        adapter.IntegrityMode(cfg.Plugins.Integrity.Mode),
+			TrustedKeys: trustedKeys,
+		},
+		SpecVersion:   cfg.PulumiCost.SpecVersion,
+		Configuration: cfg.Plugins.Configuration,
+		Launch: adapter.LaunchOptions{
+			Allow: cfg.Plugins.Validation.AllowLaunch,
+			Roots: cfg.Plugins.Validation.Roots,
+		},
+	}, logger)
+	defer pluginAdapter.Close()
+
+	// Create services
+	pluginRouter := adapter.NewPluginRouter(pluginAdapter, cfg.Plugins.Priority, logger)
+	costService := service.NewCostServiceWithRouter(pulumiAdapter, pluginRouter, cfg.Plugins.MaxConcurrent, logger)
+	var pluginInstaller *adapter.PluginInstaller
+	if cfg.Plugins.Management.AllowMutation {
+		pluginInstaller = adapter.NewPluginInstaller(pluginAdapter, cfg.Plugins.Management.RegistryIndex, logger)
+		logger.Info("plugin management enabled", "registry_index", cfg.Plugins.Management.RegistryIndex)
+	}
+	pluginService := service.NewPluginServiceWithInstaller(pluginAdapter, pluginInstaller, logger)
+	var budgets []service.BudgetDefinition
+	if cfg.Budgets.File != "" {
+		budgets, err = service.LoadBudgets(cfg.Budgets.File)
+		if err != nil {
+			stdLogger.Fatalf("Invalid budgets file: %v", err)
+		}
+		logger.Info("budgets loaded", "file", cfg.Budgets.File, "count", len(budgets))
+	}
+	analysisService := service.NewAnalysisServiceWithOptions(pulumiAdapter, pluginRouter, service.AnalysisOptions{
+		MaxConcurrent: cfg.Plugins.MaxConcurrent,
+		Budgets:       budgets,
+		MetricsDir:    cfg.UnitCost.MetricsDir,
+	}, logger)
 	logger.Info("services initialized")
 
 	// Create MCP adapters
@@ -95,6 +150,50 @@ func main() {
 	// Add metrics endpoint
 	mux.Handle("GET", "/metrics", metrics.Handler().ServeHTTP)
 
+	// Server-initiated MCP notifications (SSE)
+	notifications := notify.NewHub(logger)
+	mux.Handle("GET", "/rpc/notifications", notifications.ServeHTTP)
+
+	// Tailor tool descriptions to the capabilities of installed plugins
+	toolHints := toolhints.NewCatalog(pluginAdapter.DiscoverPlugins, logger)
+	if err := toolHints.Refresh(context.Background()); err != nil {
+		logger.Warn("failed to build tool hints", "error", err)
+	}
+
+	// Watch the plugin directory so clients refresh tools when plugins change
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	if cfg.Plugins.WatchInterval > 0 {
+		watcher := adapter.NewPluginWatcher(pluginAdapter, cfg.Plugins.WatchInterval, func(changes []adapter.PluginChange) {
+			logger.Info("plugin set changed", "changes", len(changes))
+			if err := toolHints.Refresh(watchCtx); err != nil {
+				logger.Warn("failed to refresh tool hints", "error", err)
+			}
+			if err := notifications.Notify(notify.MethodToolsListChanged, nil); err != nil {
+				logger.Warn("failed to send tools list changed notification", "error", err)
+			}
+		})
+		go watcher.Run(watchCtx)
+	}
+
+	// Push budget and anomaly alerts to SSE clients and webhooks
+	if cfg.Notifications.Enabled {
+		sinks := []notify.Sink{notifications}
+		for _, webhook := range cfg.Notifications.Webhooks {
+			sinks = append(sinks, notify.NewWebhookSink(webhook))
+		}
+		dispatcher := notify.NewDispatcher(sinks, cfg.Notifications.Cooldown, logger)
+		if cfg.Notifications.CoreEvents {
+			mux.Handle("POST", "/rpc/alerts", notify.RequireToken(cfg.Notifications.CoreEventsToken, dispatcher.ServeHTTP))
+		}
+		sentinel := service.NewSentinel(analysisService, dispatcher, service.SentinelOptions{
+			Interval:           cfg.Notifications.Interval,
+			AnomalyStacks:      cfg.Notifications.AnomalyStacks,
+			AnomalySensitivity: cfg.Notifications.AnomalySensitivity,
+		}, logger)
+		go sentinel.Run(watchCtx)
+	}
+
 	// Mount JSON-RPC servers for MCP services
 	costServer := costsvr.New(mcpCostEndpoints, mux, goahttp.RequestDecoder, goahttp.ResponseEncoder, nil)
 	costsvr.Mount(mux, costServer)
@@ -110,7 +209,7 @@ func main() {
 	// Create HTTP server
 	httpServer := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
-		Handler:      mux,
+		Handler:      toolHints.Middleware(mux),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
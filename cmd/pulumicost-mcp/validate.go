@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rshade/pulumicost-mcp/internal/adapter"
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"github.com/rshade/pulumicost-mcp/internal/report"
+)
+
+// Exit codes of the validate subcommand
+const (
+	exitValidationPassed = 0
+	exitValidationFailed = 1
+	exitValidationError  = 2
+)
+
+// runValidate implements "pulumicost-mcp validate": it launches the plugin at
+// the given path, runs the conformance suites and writes the rendered report.
+// It exits non-zero when validation fails so CI jobs can gate on it.
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: pulumicost-mcp validate [flags] <plugin-dir-or-binary>")
+		flags.PrintDefaults()
+	}
+	levelName := flags.String("level", adapter.ConformanceStandard.String(), "conformance level: BASIC, STANDARD or FULL")
+	formatName := flags.String("format", string(report.FormatMarkdown), "report format: junit, json or markdown")
+	outputPath := flags.String("output", "", "write the report to this file instead of stdout")
+	fixturesPath := flags.String("fixtures", "", "directory of cost fixtures to check projected costs against")
+	if err := flags.Parse(args); err != nil {
+		return exitValidationError
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitValidationError
+	}
+
+	level, err := adapter.ParseConformanceLevel(*levelName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitValidationError
+	}
+	format, err := report.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitValidationError
+	}
+
+	var fixtures []adapter.CostFixture
+	if *fixturesPath != "" {
+		if fixtures, err = adapter.LoadCostFixtures(*fixturesPath); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitValidationError
+		}
+	}
+
+	// Interrupting the run still stops the launched plugin
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := logging.New(logging.Config{Level: "warn", Format: "text", Output: stderr})
+	result, err := adapter.NewSpecAdapter(logger).ValidatePath(ctx, flags.Arg(0), level, fixtures)
+	if err != nil {
+		fmt.Fprintf(stderr, "validate plugin: %v\n", err)
+		return exitValidationError
+	}
+
+	rendered, err := report.Render(result, format)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitValidationError
+	}
+	if *outputPath != "" {
+		err = os.WriteFile(*outputPath, rendered, 0644)
+	} else {
+		_, err = stdout.Write(rendered)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "write report: %v\n", err)
+		return exitValidationError
+	}
+
+	if !result.Passed {
+		return exitValidationFailed
+	}
+	return exitValidationPassed
+}
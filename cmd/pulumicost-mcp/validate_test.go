@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunValidate_UsageErrors(t *testing.T) {
+	tests := map[string][]string{
+		"no path":        {},
+		"unknown level":  {"--level=gold", "/tmp/plugin"},
+		"unknown format": {"--format=pdf", "/tmp/plugin"},
+		"missing path":   {filepath.Join(t.TempDir(), "missing")},
+	}
+
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, exitValidationError, runValidate(args, &stdout, &stderr))
+			assert.Empty(t, stdout.String())
+			assert.NotEmpty(t, stderr.String())
+		})
+	}
+}
+
+func TestRunValidate_FailingPlugin(t *testing.T) {
+	pluginDir := filepath.Join(t.TempDir(), "broken")
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "plugin.json"),
+		[]byte(`{"name": "broken", "version": "1.0.0", "providers": "aws"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "bin", "broken"), []byte("#!/bin/sh\nexit 1\n"), 0755))
+
+	output := filepath.Join(t.TempDir(), "report.xml")
+	var stdout, stderr bytes.Buffer
+	code := runValidate([]string{"--level=basic", "--format=junit", "--output=" + output, pluginDir}, &stdout, &stderr)
+
+	assert.Equal(t, exitValidationFailed, code)
+	assert.Empty(t, stdout.String(), "the report goes to --output")
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<testcase name="startup" classname="broken.basic"`)
+	assert.Contains(t, string(data), "<failure")
+}
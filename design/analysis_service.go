@@ -26,17 +26,31 @@ var _ = Service("analysis", func() {
 			})
 			Attribute("recommendation_types", ArrayOf(String), "Filter by recommendation types", func() {
 				Elem(func() {
-					Enum("RIGHTSIZING", "RESERVED_INSTANCES", "SPOT_INSTANCES", "STORAGE_OPTIMIZATION")
+					Enum("RIGHTSIZING", "RESERVED_INSTANCES", "SPOT_INSTANCES", "STORAGE_OPTIMIZATION", "CLEANUP", "SCHEDULING", "OTHER")
 				})
 			})
 			Attribute("minimum_savings", Float64, "Minimum monthly savings threshold", func() {
 				Minimum(0)
 			})
+			Attribute("max_risk_level", String, "Highest risk level to include", func() {
+				Enum("LOW", "MEDIUM", "HIGH")
+			})
+			Attribute("max_implementation_effort", String, "Highest implementation effort to include", func() {
+				Enum("LOW", "MEDIUM", "HIGH")
+			})
+			Attribute("sort_by", String, "Order by highest savings, or by lowest risk or effort and then highest savings", func() {
+				Enum("SAVINGS", "RISK", "EFFORT")
+				Default("SAVINGS")
+			})
 			Required("stack_name")
 		})
 		Result(func() {
 			Description("Cost optimization recommendations")
 			Attribute("recommendations", ArrayOf(Recommendation), "List of recommendations")
+			Attribute("partial", Boolean, "True when some providers' resources or recommendations could not be retrieved", func() {
+				Default(false)
+			})
+			Attribute("unpriced_providers", ArrayOf(String), "Providers whose resources or recommendations could not be retrieved; their resources may be missing recommendations")
 			Required("recommendations")
 		})
 		Error("invalid_input", ValidationError, "Invalid stack name or parameters")
@@ -53,6 +67,109 @@ var _ = Service("analysis", func() {
 	JSONRPC(func() {})
 	})
 
+	// Find Idle Resources
+	Method("find_idle_resources", func() {
+		Description("Find resources that incur cost while reporting near-zero usage")
+		Payload(func() {
+			Attribute("stack_name", String, "Pulumi stack name", func() {
+				MinLength(1)
+			})
+			Attribute("time_range", TimeRange, "Time period to analyze")
+			Attribute("usage_threshold", Float64, "Average usage per period at or below which a resource is idle", func() {
+				Minimum(0)
+				Default(0.01)
+			})
+			Attribute("minimum_cost", Float64, "Ignore resources that cost less than this over the time range", func() {
+				Minimum(0)
+				Default(0)
+			})
+			Required("stack_name", "time_range")
+		})
+		Result(func() {
+			Description("Idle resources")
+			Attribute("recommendations", ArrayOf(Recommendation), "CLEANUP recommendations with usage evidence")
+			Attribute("total_idle_cost", Float64, "Monthly cost of the idle resources")
+			Attribute("unmeasured_urns", ArrayOf(String), "Resources with cost but no usage data")
+			Attribute("partial", Boolean, "True when some providers could not be priced", func() {
+				Default(false)
+			})
+			Attribute("unpriced_providers", ArrayOf(String), "Providers whose actual costs could not be retrieved; their resources were not checked")
+			Required("recommendations", "total_idle_cost", "unmeasured_urns")
+		})
+		Error("invalid_input", ValidationError, "Invalid stack name or time range")
+		Error("internal_error", InternalError, "Internal server error")
+
+		HTTP(func() {
+			POST("/analysis/find_idle_resources")
+			Response(StatusOK)
+			Response("invalid_input", StatusBadRequest)
+			Response("internal_error", StatusInternalServerError)
+		})
+
+	mcp.Tool("find_idle_resources", "Find idle and orphaned resources that cost money but are barely used")
+	JSONRPC(func() {})
+	})
+
+	// Analyze Commitment Coverage
+	Method("analyze_commitment_coverage", func() {
+		Description("Report how much steady-state compute and database spend is covered by reservations and savings plans")
+		Payload(func() {
+			Attribute("stack_name", String, "Pulumi stack name", func() {
+				MinLength(1)
+			})
+			Attribute("time_range", TimeRange, "Time period of actual costs to analyze")
+			Required("stack_name", "time_range")
+		})
+		Result(CommitmentCoverage)
+		Error("invalid_input", ValidationError, "Invalid stack name or time range")
+		Error("internal_error", InternalError, "Internal server error")
+
+		HTTP(func() {
+			POST("/analysis/analyze_commitment_coverage")
+			Response(StatusOK)
+			Response("invalid_input", StatusBadRequest)
+			Response("internal_error", StatusInternalServerError)
+		})
+
+	mcp.Tool("analyze_commitment_coverage", "Show reserved instance and savings plan coverage with break-even terms")
+	JSONRPC(func() {})
+	})
+
+	// Compute Unit Cost
+	Method("compute_unit_cost", func() {
+		Description("Divide actual cost by a business metric, such as cost per 1k requests or per tenant")
+		Payload(func() {
+			Attribute("scope", BudgetScope, "Stacks whose actual cost is divided; at least one stack is required, and tags and providers only narrow those stacks")
+			Attribute("metric_name", String, "Name of the metric", func() {
+				Example("requests")
+			})
+			Attribute("metric_points", ArrayOf(MetricPoint), "Metric series; alternative to metric_file")
+			Attribute("metric_file", String, "Path of a CSV file with timestamp,value rows inside the configured metrics directory; alternative to metric_points")
+			Attribute("per", Float64, "Metric units each unit cost is for", func() {
+				ExclusiveMinimum(0)
+				Default(1)
+			})
+			Attribute("granularity", String, "Window the cost and metric are summed over", func() {
+				Enum("daily", "weekly", "monthly")
+				Default("daily")
+			})
+			Required("scope")
+		})
+		Result(UnitCost)
+		Error("invalid_input", ValidationError, "Invalid scope or metric series")
+		Error("internal_error", InternalError, "Internal server error")
+
+		HTTP(func() {
+			POST("/analysis/compute_unit_cost")
+			Response(StatusOK)
+			Response("invalid_input", StatusBadRequest)
+			Response("internal_error", StatusInternalServerError)
+		})
+
+	mcp.Tool("compute_unit_cost", "Compute cost per business metric unit over time")
+	JSONRPC(func() {})
+	})
+
 	// Detect Anomalies
 	Method("detect_anomalies", func() {
 		Description("Detect unusual spending patterns and cost anomalies")
@@ -60,8 +177,8 @@ var _ = Service("analysis", func() {
 			Attribute("stack_name", String, "Pulumi stack name", func() {
 				MinLength(1)
 			})
-			Attribute("time_range", TimeRange, "Time period to analyze")
-			Attribute("sensitivity", String, "Detection sensitivity", func() {
+			Attribute("time_range", TimeRange, "Time period to analyze; the 30 days before it form the baseline")
+			Attribute("sensitivity", String, "Detection sensitivity: LOW flags 3, MEDIUM 2 and HIGH 1 standard deviations from the baseline", func() {
 				Enum("LOW", "MEDIUM", "HIGH")
 				Default("MEDIUM")
 			})
@@ -70,6 +187,10 @@ var _ = Service("analysis", func() {
 		Result(func() {
 			Description("Detected cost anomalies")
 			Attribute("anomalies", ArrayOf(Anomaly), "List of detected anomalies")
+			Attribute("partial", Boolean, "True when some providers could not be priced", func() {
+				Default(false)
+			})
+			Attribute("unpriced_providers", ArrayOf(String), "Providers whose actual costs could not be retrieved; their spending was not analyzed")
 			Required("anomalies")
 		})
 		Error("invalid_input", ValidationError, "Invalid stack name or time range")
@@ -88,7 +209,7 @@ var _ = Service("analysis", func() {
 
 	// Forecast Costs
 	Method("forecast", func() {
-		Description("Generate cost forecast based on historical trends")
+		Description("Generate cost forecast from pulumicost-core or a forecasting plugin")
 		Payload(func() {
 			Attribute("stack_name", String, "Pulumi stack name", func() {
 				MinLength(1)
@@ -103,12 +224,14 @@ var _ = Service("analysis", func() {
 		})
 		Result(Forecast)
 		Error("invalid_input", ValidationError, "Invalid stack name or forecast period")
+		Error("unsupported", UnsupportedError, "Neither pulumicost-core nor any plugin can forecast")
 		Error("internal_error", InternalError, "Internal server error")
 
 		HTTP(func() {
 			POST("/analysis/forecast")
 			Response(StatusOK)
 			Response("invalid_input", StatusBadRequest)
+			Response("unsupported", StatusNotImplemented)
 			Response("internal_error", StatusInternalServerError)
 		})
 
@@ -151,4 +274,51 @@ var _ = Service("analysis", func() {
 	mcp.Tool("track_budget", "Monitor spending against budget with alerts")
 	JSONRPC(func() {})
 	})
+
+	// List Budgets
+	Method("list_budgets", func() {
+		Description("List the budgets defined in the server's budgets file")
+		Payload(func() {
+			Attribute("stack_name", String, "Only list budgets covering this stack")
+		})
+		Result(func() {
+			Description("Defined budgets")
+			Attribute("budgets", ArrayOf(BudgetDefinition), "Budget definitions")
+			Required("budgets")
+		})
+		Error("internal_error", InternalError, "Internal server error")
+
+		HTTP(func() {
+			POST("/analysis/list_budgets")
+			Response(StatusOK)
+			Response("internal_error", StatusInternalServerError)
+		})
+
+	mcp.Tool("list_budgets", "List team and project budgets defined on the server")
+	JSONRPC(func() {})
+	})
+
+	// Check All Budgets
+	Method("check_all_budgets", func() {
+		Description("Evaluate every defined budget against period-to-date actual spending")
+		Payload(func() {
+			Attribute("stack_name", String, "Only check budgets covering this stack")
+		})
+		Result(func() {
+			Description("Status of every defined budget")
+			Attribute("checks", ArrayOf(BudgetCheck), "One check per budget, most used first")
+			Attribute("status_counts", MapOf(String, Int), "Number of budgets per status, plus ERROR for those that could not be evaluated")
+			Required("checks", "status_counts")
+		})
+		Error("internal_error", InternalError, "Internal server error")
+
+		HTTP(func() {
+			POST("/analysis/check_all_budgets")
+			Response(StatusOK)
+			Response("internal_error", StatusInternalServerError)
+		})
+
+	mcp.Tool("check_all_budgets", "Check every defined budget in one call")
+	JSONRPC(func() {})
+	})
 })
@@ -48,3 +48,17 @@ var ValidationError = Type("ValidationError", func() {
 	Attribute("value", String, "Invalid value")
 	Required("message")
 })
+
+// ForbiddenError represents an operation disabled by server configuration
+var ForbiddenError = Type("ForbiddenError", func() {
+	Description("Operation not permitted")
+	Attribute("message", String, "Error message")
+	Required("message")
+})
+
+// UnsupportedError represents a request no configured cost source can serve
+var UnsupportedError = Type("UnsupportedError", func() {
+	Description("Operation not supported by any configured cost source")
+	Attribute("message", String, "Error message")
+	Required("message")
+})
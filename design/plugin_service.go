@@ -60,6 +60,7 @@ var _ = Service("plugin", func() {
 			Attribute("health_status", HealthStatus, "Current health state")
 			Attribute("grpc_address", String, "gRPC endpoint")
 			Attribute("configuration", MapOf(String, Any), "Plugin configuration")
+			Attribute("integrity", PluginIntegrity, "Checksum and signature verification result")
 			Required("name", "version", "capabilities")
 		})
 		Error("invalid_input", ValidationError, "Invalid plugin name")
@@ -85,20 +86,25 @@ var _ = Service("plugin", func() {
 			Attribute("plugin_path", String, "Path to plugin binary or directory", func() {
 				MinLength(1)
 			})
-			Attribute("conformance_level", String, "Conformance level to test", func() {
-				Enum("BASIC", "STANDARD", "FULL")
+			Attribute("conformance_level", String, "Conformance level to test: BASIC, STANDARD or FULL, case-insensitive. Each level includes the tests of lower levels.", func() {
 				Default("STANDARD")
 			})
+			Attribute("output_format", String, "Also render the report as JUnit XML, a versioned JSON document or a Markdown summary", func() {
+				Enum("junit", "json", "markdown")
+			})
+			Attribute("fixtures_path", String, "Directory of cost fixtures to check the plugin's projected costs against, in addition to the baseline and the plugin's own fixtures")
 			Required("plugin_path")
 		})
 		Result(PluginValidationReport)
-		Error("invalid_input", ValidationError, "Invalid plugin path or conformance level")
+		Error("invalid_input", ValidationError, "Invalid plugin path, conformance level, output format or fixtures")
+		Error("forbidden", ForbiddenError, "Launching the plugin at the path is not allowed")
 		Error("internal_error", InternalError, "Internal server error")
 
 		HTTP(func() {
 			POST("/plugin/validate")
 			Response(StatusOK)
 			Response("invalid_input", StatusBadRequest)
+			Response("forbidden", StatusForbidden)
 			Response("internal_error", StatusInternalServerError)
 		})
 
@@ -131,4 +137,94 @@ var _ = Service("plugin", func() {
 	mcp.Tool("check_plugin_health", "Verify plugin connectivity and response time")
 	JSONRPC(func() {})
 	})
+
+	// Install Plugin
+	Method("install", func() {
+		Description("Install a plugin from a local archive or the plugin registry index")
+		Payload(func() {
+			Attribute("archive_path", String, "Local .tar.gz, .tgz or .zip plugin archive")
+			Attribute("sha256", String, "Expected archive digest for archive_path")
+			Attribute("plugin_name", String, "Registry plugin to install when archive_path is not set")
+			Attribute("version", String, "Registry version to install; defaults to the latest")
+		})
+		Result(PluginOperationResult)
+		Error("invalid_input", ValidationError, "Invalid archive, plugin.json or failed validation")
+		Error("not_found", NotFoundError, "Plugin or version not in the registry")
+		Error("forbidden", ForbiddenError, "Plugin management is disabled")
+		Error("internal_error", InternalError, "Internal server error")
+
+		HTTP(func() {
+			POST("/plugin/install")
+			Response(StatusOK)
+			Response("invalid_input", StatusBadRequest)
+			Response("not_found", StatusNotFound)
+			Response("forbidden", StatusForbidden)
+			Response("internal_error", StatusInternalServerError)
+		})
+
+	mcp.Tool("install_plugin", "Install a cost source plugin from an archive or the registry")
+	JSONRPC(func() {})
+	})
+
+	// Upgrade Plugin
+	Method("upgrade", func() {
+		Description("Upgrade an installed plugin, keeping the current version for rollback")
+		Payload(func() {
+			Attribute("plugin_name", String, "Installed plugin identifier", func() {
+				MinLength(1)
+			})
+			Attribute("archive_path", String, "Local plugin archive; defaults to the registry")
+			Attribute("sha256", String, "Expected archive digest for archive_path")
+			Attribute("version", String, "Registry version to install; defaults to the latest")
+			Attribute("rollback", Boolean, "Restore the version kept by the last upgrade or removal instead", func() {
+				Default(false)
+			})
+			Required("plugin_name")
+		})
+		Result(PluginOperationResult)
+		Error("invalid_input", ValidationError, "Invalid archive, plugin.json or failed validation")
+		Error("not_found", NotFoundError, "Plugin, registry version or rollback copy not found")
+		Error("forbidden", ForbiddenError, "Plugin management is disabled")
+		Error("internal_error", InternalError, "Internal server error")
+
+		HTTP(func() {
+			POST("/plugin/upgrade")
+			Response(StatusOK)
+			Response("invalid_input", StatusBadRequest)
+			Response("not_found", StatusNotFound)
+			Response("forbidden", StatusForbidden)
+			Response("internal_error", StatusInternalServerError)
+		})
+
+	mcp.Tool("upgrade_plugin", "Upgrade or roll back an installed cost source plugin")
+	JSONRPC(func() {})
+	})
+
+	// Remove Plugin
+	Method("remove", func() {
+		Description("Remove an installed plugin, keeping it for rollback")
+		Payload(func() {
+			Attribute("plugin_name", String, "Installed plugin identifier", func() {
+				MinLength(1)
+			})
+			Required("plugin_name")
+		})
+		Result(PluginOperationResult)
+		Error("invalid_input", ValidationError, "Invalid plugin name")
+		Error("not_found", NotFoundError, "Plugin not found")
+		Error("forbidden", ForbiddenError, "Plugin management is disabled")
+		Error("internal_error", InternalError, "Internal server error")
+
+		HTTP(func() {
+			POST("/plugin/remove")
+			Response(StatusOK)
+			Response("invalid_input", StatusBadRequest)
+			Response("not_found", StatusNotFound)
+			Response("forbidden", StatusForbidden)
+			Response("internal_error", StatusInternalServerError)
+		})
+
+	mcp.Tool("remove_plugin", "Remove an installed cost source plugin")
+	JSONRPC(func() {})
+	})
 })
@@ -80,32 +80,6 @@ var ActualCostDataPoint = Type("ActualCostDataPoint", func() {
 	Required("timestamp", "cost", "currency")
 })
 
-// OptimizationRecommendation represents a cost optimization suggestion
-var OptimizationRecommendation = Type("OptimizationRecommendation", func() {
-	Description("Cost optimization recommendation")
-	Attribute("id", String, "Recommendation ID")
-	Attribute("title", String, "Recommendation title")
-	Attribute("description", String, "Detailed description")
-	Attribute("category", String, "Category", func() {
-		Enum("rightsizing", "reserved_instance", "spot_instance", "storage_optimization", "cleanup", "scheduling", "other")
-	})
-	Attribute("impact", String, "Impact level", func() {
-		Enum("low", "medium", "high")
-	})
-	Attribute("estimated_monthly_savings", Float64, "Estimated monthly savings")
-	Attribute("currency", String, "Currency code")
-	Attribute("resources", ArrayOf(String), "Affected resource URNs")
-	Attribute("risk_level", String, "Risk level", func() {
-		Enum("low", "medium", "high")
-		Default("low")
-	})
-	Attribute("implementation_effort", String, "Implementation effort", func() {
-		Enum("low", "medium", "high")
-	})
-	Attribute("action_items", ArrayOf(String), "Steps to implement")
-	Required("id", "title", "description", "category", "impact", "estimated_monthly_savings", "currency")
-})
-
 // PluginInfo represents information about a cost source plugin
 var PluginInfo = Type("PluginInfo", func() {
 	Description("Cost source plugin information")
@@ -128,7 +102,7 @@ var ValidationResult = Type("ValidationResult", func() {
 	Description("Plugin validation results")
 	Attribute("passed", Boolean, "Whether validation passed")
 	Attribute("level", String, "Conformance level tested", func() {
-		Enum("basic", "standard", "advanced")
+		Enum("BASIC", "STANDARD", "FULL")
 	})
 	Attribute("total_tests", Int, "Total number of tests")
 	Attribute("passed_tests", Int, "Number of passed tests")
@@ -177,6 +151,18 @@ var CostMetadata = Type("CostMetadata", func() {
 	})
 })
 
+// ProviderFailure describes a provider whose plugin could not price its resources
+var ProviderFailure = Type("ProviderFailure", func() {
+	Description("Provider that could not be priced during a multi-plugin request")
+	Attribute("provider", String, "Cloud provider", func() {
+		Example("gcp")
+	})
+	Attribute("plugin", String, "Last plugin attempted for the provider")
+	Attribute("error", String, "Why the provider could not be priced")
+	Attribute("unpriced_urns", ArrayOf(String), "URNs of resources left without a cost")
+	Required("provider", "error")
+})
+
 // CostResult represents the response from cost analysis
 var CostResult = Type("CostResult", func() {
 	Description("Cost analysis result with breakdown")
@@ -195,6 +181,10 @@ var CostResult = Type("CostResult", func() {
 		Format(FormatDateTime)
 	})
 	Attribute("metadata", CostMetadata, "Additional context")
+	Attribute("partial", Boolean, "True when some providers could not be priced", func() {
+		Default(false)
+	})
+	Attribute("failed_providers", ArrayOf(ProviderFailure), "Providers whose plugins failed; their resources are excluded from totals")
 	Required("total_monthly", "currency", "resources")
 })
 
@@ -211,6 +201,12 @@ var PluginCapabilities = Type("PluginCapabilities", func() {
 	Attribute("supports_actual", Boolean, "Can retrieve actual historical costs", func() {
 		Default(false)
 	})
+	Attribute("supports_optimization", Boolean, "Can produce optimization recommendations", func() {
+		Default(false)
+	})
+	Attribute("supports_forecast", Boolean, "Can forecast future costs", func() {
+		Default(false)
+	})
 	Attribute("supports_providers", ArrayOf(String), "Supported cloud providers")
 	Required("supports_projected", "supports_actual", "supports_providers")
 })
@@ -232,6 +228,28 @@ var HealthStatus = Type("HealthStatus", func() {
 	Required("status")
 })
 
+// PluginIntegrity represents the outcome of plugin checksum and signature verification
+var PluginIntegrity = Type("PluginIntegrity", func() {
+	Description("Integrity verification result for a plugin")
+	Attribute("status", String, "Verification outcome", func() {
+		Enum("verified", "unsigned", "failed", "not_checked")
+	})
+	Attribute("key_id", String, "Trusted key that signed the plugin")
+	Attribute("reason", String, "Why the plugin is not verified")
+	Required("status")
+})
+
+// PluginCompatibility represents whether a plugin matches the server's pulumicost-spec version
+var PluginCompatibility = Type("PluginCompatibility", func() {
+	Description("Compatibility of a plugin with the server's pulumicost-spec version")
+	Attribute("status", String, "Compatibility verdict; incompatible plugins receive no traffic", func() {
+		Enum("compatible", "deprecated", "incompatible")
+	})
+	Attribute("reason", String, "Why the verdict was reached")
+	Attribute("spec_range", String, "pulumicost-spec version range declared by the plugin")
+	Required("status", "reason")
+})
+
 // Plugin represents a cost source plugin with metadata
 var Plugin = Type("Plugin", func() {
 	Description("Cost source plugin information")
@@ -240,6 +258,7 @@ var Plugin = Type("Plugin", func() {
 	Attribute("description", String, "Plugin purpose")
 	Attribute("capabilities", PluginCapabilities, "Supported features")
 	Attribute("health_status", HealthStatus, "Current health state")
+	Attribute("compatibility", PluginCompatibility, "pulumicost-spec compatibility verdict")
 	Required("name", "version", "capabilities")
 })
 
@@ -252,6 +271,8 @@ var ValidationTest = Type("ValidationTest", func() {
 	Attribute("duration_ms", Int64, "Test execution time in milliseconds", func() {
 		Minimum(0)
 	})
+	Attribute("expected_cost", Float64, "Expected monthly cost of a cost accuracy test")
+	Attribute("actual_cost", Float64, "Monthly cost the plugin projected in a cost accuracy test")
 	Required("name", "passed")
 })
 
@@ -268,9 +289,25 @@ var PluginValidationReport = Type("PluginValidationReport", func() {
 	Attribute("timestamp", String, "ISO 8601 timestamp of validation", func() {
 		Format(FormatDateTime)
 	})
+	Attribute("rendered", String, "Report rendered in the requested output_format")
 	Required("plugin_name", "conformance_level", "passed", "test_results")
 })
 
+// PluginOperationResult represents the outcome of installing, upgrading or removing a plugin
+var PluginOperationResult = Type("PluginOperationResult", func() {
+	Description("Result of a plugin management operation")
+	Attribute("plugin_name", String, "Plugin identifier")
+	Attribute("action", String, "Operation performed", func() {
+		Enum("installed", "upgraded", "rolled_back", "removed")
+	})
+	Attribute("version", String, "Version now active, empty after removal")
+	Attribute("previous_version", String, "Version that was replaced or removed")
+	Attribute("rollback_path", String, "Where the previous version is kept for rollback")
+	Attribute("validation", PluginValidationReport, "Checks run before the plugin was activated")
+	Attribute("integrity", PluginIntegrity, "Checksum and signature verification result")
+	Required("plugin_name", "action")
+})
+
 // ====================
 // Analysis Types
 // ====================
@@ -279,11 +316,12 @@ var PluginValidationReport = Type("PluginValidationReport", func() {
 var Recommendation = Type("Recommendation", func() {
 	Description("AI-powered cost optimization recommendation")
 	Attribute("id", String, "Unique recommendation ID")
-	Attribute("type", String, "Recommendation type", func() {
-		Enum("RIGHTSIZING", "RESERVED_INSTANCES", "SPOT_INSTANCES", "STORAGE_OPTIMIZATION")
+	Attribute("type", String, "Recommendation category", func() {
+		Enum("RIGHTSIZING", "RESERVED_INSTANCES", "SPOT_INSTANCES", "STORAGE_OPTIMIZATION", "CLEANUP", "SCHEDULING", "OTHER")
 	})
-	Attribute("resource_urn", String, "Affected resource URN")
-	Attribute("current_cost", Float64, "Current monthly cost", func() {
+	Attribute("resource_urn", String, "Primary affected resource URN")
+	Attribute("resource_urns", ArrayOf(String), "All affected resource URNs in the stack")
+	Attribute("current_cost", Float64, "Current monthly cost of the affected resources", func() {
 		Minimum(0)
 	})
 	Attribute("projected_savings", Float64, "Estimated monthly savings", func() {
@@ -293,9 +331,46 @@ var Recommendation = Type("Recommendation", func() {
 		Enum("LOW", "MEDIUM", "HIGH")
 		Default("MEDIUM")
 	})
+	Attribute("risk_level", String, "Risk of disruption when applied", func() {
+		Enum("LOW", "MEDIUM", "HIGH")
+	})
+	Attribute("implementation_effort", String, "Effort needed to apply", func() {
+		Enum("LOW", "MEDIUM", "HIGH")
+	})
 	Attribute("description", String, "Human-readable explanation")
 	Attribute("action_steps", ArrayOf(String), "Implementation guidance")
-	Required("id", "type", "resource_urn", "current_cost", "projected_savings", "confidence", "description")
+	Attribute("source", String, "Plugin that produced the recommendation, or pulumicost-core")
+	Attribute("evidence", UsageEvidence, "Actual cost and usage behind an idle resource recommendation")
+	Attribute("break_even", BreakEven, "Break-even terms of a RESERVED_INSTANCES recommendation, as reported by its source")
+	Required("id", "type", "resource_urn", "resource_urns", "current_cost", "projected_savings", "confidence",
+		"risk_level", "implementation_effort", "description", "source")
+})
+
+// BreakEven describes when a reservation or savings plan pays for itself
+var BreakEven = Type("BreakEven", func() {
+	Description("Break-even terms of a commitment")
+	Attribute("term", String, "Commitment term", func() {
+		Example("1y")
+	})
+	Attribute("upfront_cost", Float64, "Upfront payment", func() {
+		Minimum(0)
+	})
+	Attribute("break_even_months", Float64, "Months until the commitment costs less than on-demand", func() {
+		Minimum(0)
+	})
+	Required("break_even_months")
+})
+
+// UsageEvidence is the actual cost and usage that shows a resource is idle
+var UsageEvidence = Type("UsageEvidence", func() {
+	Description("Actual cost and usage of a resource over a time range")
+	Attribute("cost", Float64, "Cost over the time range")
+	Attribute("usage_amount", Float64, "Total usage over the time range")
+	Attribute("usage_unit", String, "Usage unit reported by the cost source")
+	Attribute("data_points", Int, "Periods that reported usage")
+	Attribute("idle_data_points", Int, "Periods with usage at or below the threshold")
+	Attribute("time_range", TimeRange, "Time range analyzed")
+	Required("cost", "usage_amount", "usage_unit", "data_points", "idle_data_points", "time_range")
 })
 
 // Anomaly represents a detected cost irregularity
@@ -346,7 +421,33 @@ var Forecast = Type("Forecast", func() {
 		Default(0.95)
 	})
 	Attribute("methodology", String, "Forecasting approach used")
-	Required("stack_name", "forecast_period", "data_points", "confidence_level", "methodology")
+	Attribute("granularity", String, "Spacing of the data points", func() {
+		Enum("daily", "weekly", "monthly")
+	})
+	Attribute("history_window", TimeRange, "Historical costs the forecast is based on")
+	Attribute("source", String, "Plugin that produced the forecast, or pulumicost-core")
+	Required("stack_name", "forecast_period", "data_points", "confidence_level", "methodology", "granularity", "source")
+})
+
+// BudgetAlert represents a budget threshold that has been crossed
+var BudgetAlert = Type("BudgetAlert", func() {
+	Description("Alert raised when spending crosses a budget threshold")
+	Attribute("threshold", Float64, "Threshold percentage that was crossed", func() {
+		Minimum(0)
+		Maximum(100)
+	})
+	Attribute("current_spend", Float64, "Spending when the alert was raised", func() {
+		Minimum(0)
+	})
+	Attribute("percentage_used", Float64, "Percentage of the budget spent")
+	Attribute("severity", String, "Alert severity", func() {
+		Enum("INFO", "MEDIUM", "HIGH", "CRITICAL")
+	})
+	Attribute("message", String, "Human-readable alert")
+	Attribute("timestamp", String, "ISO 8601 time the alert was raised", func() {
+		Format(FormatDateTime)
+	})
+	Required("threshold", "current_spend", "percentage_used", "severity", "message", "timestamp")
 })
 
 // Budget represents budget definition and tracking
@@ -355,17 +456,145 @@ var Budget = Type("Budget", func() {
 	Attribute("budget_amount", Float64, "Budget amount", func() {
 		Minimum(0)
 	})
+	Attribute("period", String, "Budget period", func() {
+		Enum("DAILY", "WEEKLY", "MONTHLY", "YEARLY")
+	})
+	Attribute("period_window", TimeRange, "Current budget period in UTC")
+	Attribute("currency", String, "Currency of the amounts")
 	Attribute("current_spending", Float64, "Actual spending so far", func() {
 		Minimum(0)
 	})
 	Attribute("remaining", Float64, "Budget remaining")
-	Attribute("burn_rate", Float64, "Daily spending rate")
-	Attribute("projected_end_date", String, "ISO 8601 when budget exhausts", func() {
+	Attribute("burn_rate", Float64, "Average daily spending this period")
+	Attribute("projected_spending", Float64, "Spending expected by the end of the period at the burn rate")
+	Attribute("projected_end_date", String, "ISO 8601 when budget exhausts, if within the period", func() {
 		Format(FormatDateTime)
 	})
 	Attribute("status", String, "Budget status", func() {
-		Enum("OK", "WARNING", "EXCEEDED")
+		Enum("OK", "WARNING", "CRITICAL", "EXCEEDED")
+	})
+	Attribute("alerts", ArrayOf(BudgetAlert), "Threshold alerts")
+	Attribute("partial", Boolean, "True when some providers could not be priced", func() {
+		Default(false)
+	})
+	Attribute("unpriced_providers", ArrayOf(String), "Providers whose actual costs could not be retrieved; their spending is missing")
+	Required("budget_amount", "period", "period_window", "current_spending", "remaining", "status")
+})
+
+// BudgetScope selects the spending a defined budget or a unit cost covers
+var BudgetScope = Type("BudgetScope", func() {
+	Description("Stacks, resource tags and providers whose spending counts")
+	Attribute("stacks", ArrayOf(String), "Pulumi stacks whose spending counts")
+	Attribute("tags", MapOf(String, String), "Only resources carrying all of these tags count")
+	Attribute("providers", ArrayOf(String), "Only resources of these cloud providers count")
+	Required("stacks")
+})
+
+// BudgetDefinition is a budget from the budgets file
+var BudgetDefinition = Type("BudgetDefinition", func() {
+	Description("Budget defined in the server's budgets file")
+	Attribute("name", String, "Unique budget name")
+	Attribute("description", String, "What the budget is for")
+	Attribute("amount", Float64, "Budget amount", func() {
+		Minimum(0)
+	})
+	Attribute("currency", String, "Currency of the amount")
+	Attribute("period", String, "Budget period", func() {
+		Enum("DAILY", "WEEKLY", "MONTHLY", "YEARLY")
+	})
+	Attribute("alert_thresholds", ArrayOf(Float64), "Alert threshold percentages")
+	Attribute("scope", BudgetScope, "Spending the budget covers")
+	Required("name", "amount", "currency", "period", "scope")
+})
+
+// BudgetCheck is the evaluation of one defined budget
+var BudgetCheck = Type("BudgetCheck", func() {
+	Description("Status of a defined budget, or why it could not be evaluated")
+	Attribute("definition", BudgetDefinition, "Budget that was evaluated")
+	Attribute("budget", Budget, "Budget status, if it could be evaluated")
+	Attribute("error", String, "Why the budget could not be evaluated")
+	Required("definition")
+})
+
+// CommitmentGroup is the commitment coverage of one provider's service
+var CommitmentGroup = Type("CommitmentGroup", func() {
+	Description("Commitment coverage of compute or database spend for one provider and service")
+	Attribute("provider", String, "Cloud provider")
+	Attribute("service", String, "Provider service, such as ec2 or rds")
+	Attribute("category", String, "Kind of spend", func() {
+		Enum("COMPUTE", "DATABASE")
+	})
+	Attribute("total_spend", Float64, "Spend over the time range, excluding spot capacity")
+	Attribute("committed_spend", Float64, "Spend billed under reservations, savings plans or committed use")
+	Attribute("on_demand_spend", Float64, "Spend billed on demand")
+	Attribute("steady_state_spend", Float64, "On-demand spend that recurred every day of the time range, the part a commitment could cover")
+	Attribute("coverage_percentage", Float64, "Committed spend as a percentage of total spend")
+	Attribute("resource_urns", ArrayOf(String), "Resources in the group")
+	Attribute("recommendations", ArrayOf(Recommendation), "RESERVED_INSTANCES recommendations for the group's resources, with break-even terms")
+	Required("provider", "service", "category", "total_spend", "committed_spend", "on_demand_spend",
+		"steady_state_spend", "coverage_percentage", "resource_urns", "recommendations")
+})
+
+// CommitmentCoverage reports how much steady-state spend commitments cover
+var CommitmentCoverage = Type("CommitmentCoverage", func() {
+	Description("Commitment coverage of a stack's compute and database spend")
+	Attribute("stack_name", String, "Pulumi stack name")
+	Attribute("time_range", TimeRange, "Time range analyzed")
+	Attribute("currency", String, "Currency code (ISO 4217)")
+	Attribute("groups", ArrayOf(CommitmentGroup), "Coverage per provider and service, largest steady-state spend first")
+	Attribute("total_spend", Float64, "Compute and database spend, excluding spot capacity")
+	Attribute("committed_spend", Float64, "Spend covered by commitments")
+	Attribute("on_demand_spend", Float64, "Spend billed on demand")
+	Attribute("steady_state_spend", Float64, "On-demand spend a commitment could cover")
+	Attribute("coverage_percentage", Float64, "Committed spend as a percentage of total spend")
+	Attribute("unknown_billing_urns", ArrayOf(String), "Resources whose cost source reported no billing mode; counted as on-demand")
+	Attribute("partial", Boolean, "True when some providers could not be priced", func() {
+		Default(false)
+	})
+	Attribute("unpriced_providers", ArrayOf(String), "Providers whose actual costs could not be retrieved; their spend is missing")
+	Required("stack_name", "time_range", "currency", "groups", "total_spend", "committed_spend",
+		"on_demand_spend", "steady_state_spend", "coverage_percentage", "unknown_billing_urns")
+})
+
+// MetricPoint is one value of a business metric
+var MetricPoint = Type("MetricPoint", func() {
+	Description("Business metric value at a point in time")
+	Attribute("timestamp", String, "ISO 8601 timestamp or date", func() {
+		Example("2024-03-01T00:00:00Z")
+	})
+	Attribute("value", Float64, "Metric value, such as requests served or active tenants", func() {
+		Minimum(0)
+	})
+	Required("timestamp", "value")
+})
+
+// UnitCostPoint is the cost per metric unit of one window
+var UnitCostPoint = Type("UnitCostPoint", func() {
+	Description("Actual cost and metric of one window")
+	Attribute("window", TimeRange, "Window in UTC")
+	Attribute("cost", Float64, "Actual cost in the window")
+	Attribute("metric_value", Float64, "Sum of the metric values in the window")
+	Attribute("unit_cost", Float64, "Cost per unit, omitted when the metric is zero")
+	Required("window", "cost", "metric_value")
+})
+
+// UnitCost relates actual cost to a business metric
+var UnitCost = Type("UnitCost", func() {
+	Description("Actual cost per business metric unit")
+	Attribute("metric_name", String, "Name of the metric")
+	Attribute("per", Float64, "Metric units each unit cost is for, such as 1000 for cost per 1k requests")
+	Attribute("granularity", String, "Window size", func() {
+		Enum("daily", "weekly", "monthly")
+	})
+	Attribute("currency", String, "Currency code (ISO 4217)")
+	Attribute("time_range", TimeRange, "Windows covered, from the first start to the last end")
+	Attribute("points", ArrayOf(UnitCostPoint), "One point per window with metric data, oldest first")
+	Attribute("total_cost", Float64, "Actual cost over all windows")
+	Attribute("total_metric", Float64, "Metric over all windows")
+	Attribute("unit_cost", Float64, "Total cost per unit, omitted when the total metric is zero")
+	Attribute("partial", Boolean, "True when some providers in the scope could not be priced", func() {
+		Default(false)
 	})
-	Attribute("alerts", ArrayOf(Any), "Threshold alerts") // Array of alert objects
-	Required("budget_amount", "current_spending", "remaining", "status")
+	Attribute("unpriced_providers", ArrayOf(String), "Providers whose actual costs could not be retrieved; their cost is missing")
+	Required("metric_name", "per", "granularity", "currency", "time_range", "points", "total_cost", "total_metric")
 })
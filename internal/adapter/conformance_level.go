@@ -0,0 +1,60 @@
+package adapter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConformanceLevel is a pulumicost-spec conformance level. Levels are
+// cumulative: each one includes the tests of every level below it.
+type ConformanceLevel int
+
+const (
+	// ConformanceBasic checks that the plugin starts, is healthy and describes itself
+	ConformanceBasic ConformanceLevel = iota + 1
+	// ConformanceStandard adds cost responses, error codes, resource type accuracy and cost accuracy against fixtures
+	ConformanceStandard
+	// ConformanceFull adds concurrency, latency budgets and resource limits
+	ConformanceFull
+)
+
+// conformanceLevelNames are the canonical level names, as used by the API
+var conformanceLevelNames = map[ConformanceLevel]string{
+	ConformanceBasic:    "BASIC",
+	ConformanceStandard: "STANDARD",
+	ConformanceFull:     "FULL",
+}
+
+// String returns the canonical level name
+func (l ConformanceLevel) String() string {
+	if name, ok := conformanceLevelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("ConformanceLevel(%d)", int(l))
+}
+
+// Includes reports whether validating at l runs the tests of other
+func (l ConformanceLevel) Includes(other ConformanceLevel) bool {
+	return l >= other
+}
+
+// InvalidConformanceLevelError reports a conformance level that is not one of the canonical names
+type InvalidConformanceLevelError struct {
+	Level string
+}
+
+func (e *InvalidConformanceLevelError) Error() string {
+	return fmt.Sprintf("invalid conformance level %q: must be one of BASIC, STANDARD or FULL", e.Level)
+}
+
+// ParseConformanceLevel parses a level name, ignoring case and surrounding
+// whitespace. Unknown names return an *InvalidConformanceLevelError.
+func ParseConformanceLevel(s string) (ConformanceLevel, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(s))
+	for level, name := range conformanceLevelNames {
+		if name == normalized {
+			return level, nil
+		}
+	}
+	return 0, &InvalidConformanceLevelError{Level: s}
+}
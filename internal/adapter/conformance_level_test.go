@@ -0,0 +1,46 @@
+package adapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseConformanceLevel verifies level names are parsed case-insensitively
+func TestParseConformanceLevel(t *testing.T) {
+	tests := []struct {
+		input string
+		want  ConformanceLevel
+	}{
+		{"BASIC", ConformanceBasic},
+		{"basic", ConformanceBasic},
+		{"Standard", ConformanceStandard},
+		{" full ", ConformanceFull},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			level, err := ParseConformanceLevel(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, level)
+		})
+	}
+
+	for _, input := range []string{"", "advanced", "FULLER"} {
+		_, err := ParseConformanceLevel(input)
+		var invalid *InvalidConformanceLevelError
+		require.ErrorAs(t, err, &invalid, input)
+		assert.Equal(t, input, invalid.Level)
+	}
+}
+
+// TestConformanceLevel_Includes verifies levels are cumulative
+func TestConformanceLevel_Includes(t *testing.T) {
+	assert.True(t, ConformanceFull.Includes(ConformanceBasic))
+	assert.True(t, ConformanceFull.Includes(ConformanceStandard))
+	assert.True(t, ConformanceStandard.Includes(ConformanceStandard))
+	assert.False(t, ConformanceStandard.Includes(ConformanceFull))
+	assert.False(t, ConformanceBasic.Includes(ConformanceStandard))
+	assert.Equal(t, "STANDARD", ConformanceStandard.String())
+}
@@ -0,0 +1,308 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/rshade/pulumicost-mcp/gen/plugin"
+)
+
+// Cost accuracy defaults
+const (
+	// defaultTolerancePercent is the accuracy requirement of SC-006
+	defaultTolerancePercent = 5.0
+	defaultFixtureCurrency  = "USD"
+	// pluginFixturesDir is where a plugin ships its own fixtures, relative to its directory
+	pluginFixturesDir = "fixtures"
+)
+
+// baselineFixtures are the cost fixtures shipped with the server
+//
+//go:embed fixtures/*.json
+var baselineFixtures embed.FS
+
+// errSkipped marks a conformance test that does not apply to the plugin; it is left out of the report
+var errSkipped = errors.New("skipped")
+
+// CostFixture pairs a resource with its expected monthly cost. A projected
+// cost within the tolerance of CostPerMonth passes: the larger of
+// TolerancePercent of the expected cost and ToleranceAbsolute.
+type CostFixture struct {
+	Name              string          `json:"name"`
+	Description       string          `json:"description,omitempty"`
+	Resource          FixtureResource `json:"resource"`
+	CostPerMonth      float64         `json:"cost_per_month"`
+	Currency          string          `json:"currency,omitempty"`           // defaults to USD
+	TolerancePercent  *float64        `json:"tolerance_percent,omitempty"`  // defaults to 5
+	ToleranceAbsolute float64         `json:"tolerance_absolute,omitempty"` // in Currency
+
+	// required fixtures fail when the plugin does not support their resource;
+	// baseline fixtures are skipped instead
+	required bool
+}
+
+// FixtureResource describes the resource a fixture prices
+type FixtureResource struct {
+	Provider     string            `json:"provider"`
+	ResourceType string            `json:"resource_type"`
+	SKU          string            `json:"sku,omitempty"`
+	Region       string            `json:"region,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+}
+
+func (r FixtureResource) descriptor() *resourceDescriptor {
+	return &resourceDescriptor{
+		Provider:     r.Provider,
+		ResourceType: r.ResourceType,
+		SKU:          r.SKU,
+		Region:       r.Region,
+		Tags:         r.Tags,
+	}
+}
+
+// LoadCostFixtures reads the *.json files of a directory. Each file holds a
+// fixture or an array of fixtures. Fixtures loaded this way are required:
+// a plugin that does not support their resource fails them.
+func LoadCostFixtures(dir string) ([]CostFixture, error) {
+	if info, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("%w: cost fixtures: %w", ErrInvalidInput, err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%w: cost fixtures %s is not a directory", ErrInvalidInput, dir)
+	}
+
+	fixtures, err := loadFixtures(os.DirFS(dir))
+	if err != nil {
+		return nil, fmt.Errorf("%w: cost fixtures %s: %w", ErrInvalidInput, dir, err)
+	}
+	for i := range fixtures {
+		fixtures[i].required = true
+	}
+	return fixtures, nil
+}
+
+// loadBaselineFixtures returns the fixtures shipped with the server
+func loadBaselineFixtures() ([]CostFixture, error) {
+	sub, err := fs.Sub(baselineFixtures, "fixtures")
+	if err != nil {
+		return nil, err
+	}
+	return loadFixtures(sub)
+}
+
+func loadFixtures(fsys fs.FS) ([]CostFixture, error) {
+	paths, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var fixtures []CostFixture
+	seen := make(map[string]string)
+	for _, path := range paths {
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return nil, err
+		}
+
+		var fileFixtures []CostFixture
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+			err = json.Unmarshal(data, &fileFixtures)
+		} else {
+			var fixture CostFixture
+			err = json.Unmarshal(data, &fixture)
+			fileFixtures = []CostFixture{fixture}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+
+		for _, fixture := range fileFixtures {
+			if err := fixture.validate(); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			if other, dup := seen[fixture.Name]; dup {
+				return nil, fmt.Errorf("%s: fixture %q is also defined in %s", path, fixture.Name, other)
+			}
+			seen[fixture.Name] = path
+			fixtures = append(fixtures, fixture)
+		}
+	}
+	return fixtures, nil
+}
+
+func (f *CostFixture) validate() error {
+	switch {
+	case f.Name == "":
+		return fmt.Errorf("fixture name is required")
+	case f.Resource.Provider == "" || f.Resource.ResourceType == "":
+		return fmt.Errorf("fixture %q: resource provider and resource_type are required", f.Name)
+	case f.CostPerMonth < 0 || math.IsNaN(f.CostPerMonth) || math.IsInf(f.CostPerMonth, 0):
+		return fmt.Errorf("fixture %q: cost_per_month must be a non-negative number", f.Name)
+	case f.TolerancePercent != nil && *f.TolerancePercent < 0, f.ToleranceAbsolute < 0:
+		return fmt.Errorf("fixture %q: tolerances must not be negative", f.Name)
+	}
+	return nil
+}
+
+// tolerance returns the allowed absolute deviation from CostPerMonth
+func (f *CostFixture) tolerance() float64 {
+	percent := defaultTolerancePercent
+	if f.TolerancePercent != nil {
+		percent = *f.TolerancePercent
+	}
+	return math.Max(f.CostPerMonth*percent/100, f.ToleranceAbsolute)
+}
+
+func (f *CostFixture) currency() string {
+	if f.Currency == "" {
+		return defaultFixtureCurrency
+	}
+	return f.Currency
+}
+
+// mergeFixtures combines fixture sets; a later fixture replaces an earlier one of the same name
+func mergeFixtures(sets ...[]CostFixture) []CostFixture {
+	var merged []CostFixture
+	index := make(map[string]int)
+	for _, set := range sets {
+		for _, fixture := range set {
+			if i, ok := index[fixture.Name]; ok {
+				merged[i] = fixture
+				continue
+			}
+			index[fixture.Name] = len(merged)
+			merged = append(merged, fixture)
+		}
+	}
+	return merged
+}
+
+// costFixtures collects the fixtures a plugin is tested against: the
+// baseline for its providers, the fixtures shipped in its directory and any
+// supplied by the caller, with later sources replacing fixtures of the same name
+func (a *SpecAdapter) costFixtures(pluginDir string, extra []CostFixture) ([]CostFixture, error) {
+	baseline, err := loadBaselineFixtures()
+	if err != nil {
+		return nil, fmt.Errorf("load baseline cost fixtures: %w", err)
+	}
+
+	var shipped []CostFixture
+	if pluginDir != "" {
+		dir := filepath.Join(pluginDir, pluginFixturesDir)
+		if _, err := os.Stat(dir); err == nil {
+			if shipped, err = LoadCostFixtures(dir); err != nil {
+				return nil, fmt.Errorf("load plugin cost fixtures: %w", err)
+			}
+		}
+	}
+
+	return mergeFixtures(baseline, shipped, extra), nil
+}
+
+// accuracyTests returns one test per fixture of a provider the plugin
+// declares, or a single failing test if the fixtures could not be loaded
+func (a *SpecAdapter) accuracyTests(target *conformanceTarget, fixtures []CostFixture, loadErr error) []conformanceTest {
+	if loadErr != nil {
+		return []conformanceTest{{name: "standard/accuracy", run: func(context.Context, *conformanceTarget) error { return loadErr }}}
+	}
+	if !target.plugin.Capabilities.SupportsProjected {
+		return nil
+	}
+
+	var tests []conformanceTest
+	for _, fixture := range fixtures {
+		if !fixture.required && !containsString(target.plugin.Capabilities.SupportsProviders, fixture.Resource.Provider) {
+			continue
+		}
+		fixture := fixture
+		costs := &costComparison{expected: fixture.CostPerMonth}
+		tests = append(tests, conformanceTest{
+			name: "standard/accuracy/" + fixture.Name,
+			run: func(ctx context.Context, t *conformanceTarget) error {
+				return testCostAccuracy(ctx, t, &fixture, costs)
+			},
+			costs: costs,
+		})
+	}
+	return tests
+}
+
+// costComparison is the expected and actual monthly cost of an accuracy test
+type costComparison struct {
+	expected float64
+	actual   *float64 // nil if the plugin did not price the resource
+}
+
+// testCostAccuracy requires the projected monthly cost of a fixture resource
+// to be within its tolerance, recording the cost in costs. Baseline fixtures
+// the plugin does not support are skipped.
+func testCostAccuracy(ctx context.Context, t *conformanceTarget, fixture *CostFixture, costs *costComparison) error {
+	resource := fixture.Resource.descriptor()
+
+	callCtx, cancel := context.WithTimeout(ctx, conformanceCallTimeout)
+	defer cancel()
+
+	supports, err := t.client.Supports(callCtx, resource)
+	if err != nil {
+		return fmt.Errorf("Supports %s: %w", resource.ResourceType, err)
+	}
+	if !supports.Supported {
+		if !fixture.required {
+			return errSkipped
+		}
+		return fmt.Errorf("plugin does not support %s", resource.ResourceType)
+	}
+
+	resp, err := t.client.GetProjectedCost(callCtx, resource)
+	if err != nil {
+		return fmt.Errorf("GetProjectedCost %s: %w", resource.ResourceType, err)
+	}
+
+	actual := resp.CostPerMonth
+	costs.actual = &actual
+	if resp.Currency != fixture.currency() {
+		return fmt.Errorf("expected %.4f %s, got %.4f %s", fixture.CostPerMonth, fixture.currency(), actual, resp.Currency)
+	}
+	if deviation := actual - fixture.CostPerMonth; math.Abs(deviation) > fixture.tolerance()+1e-9 {
+		return fmt.Errorf("expected %.4f %s ±%.4f, got %.4f %s (%s)",
+			fixture.CostPerMonth, fixture.currency(), fixture.tolerance(), actual, resp.Currency,
+			deviationPercent(deviation, fixture.CostPerMonth))
+	}
+	return nil
+}
+
+func deviationPercent(deviation, expected float64) string {
+	if expected == 0 {
+		return "expected no cost"
+	}
+	return fmt.Sprintf("%+.1f%%", deviation/expected*100)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// RunAccuracyTests prices each fixture with the plugin and compares the
+// projected monthly cost against the expected cost. Fixtures of resources
+// the plugin does not support fail, except baseline fixtures.
+func (a *SpecAdapter) RunAccuracyTests(ctx context.Context, p *plugin.Plugin, fixtures []CostFixture) ([]TestResult, error) {
+	target, err := a.connect(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return a.runTests(ctx, target, a.accuracyTests(target, fixtures, nil)), nil
+}
@@ -0,0 +1,153 @@
+package adapter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rshade/pulumicost-mcp/gen/plugin"
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadCostFixtures verifies fixture files may hold one fixture or an array
+func TestLoadCostFixtures(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "ec2.json", `{"name": "ec2", "resource": {"provider": "aws", "resource_type": "aws:ec2/instance:Instance", "sku": "t3.micro"},
+		"cost_per_month": 7.592}`)
+	writeFixture(t, dir, "more.json", `[
+		{"name": "rds", "resource": {"provider": "aws", "resource_type": "aws:rds/instance:Instance"}, "cost_per_month": 12.41,
+		 "currency": "EUR", "tolerance_percent": 0, "tolerance_absolute": 0.5}
+	]`)
+	writeFixture(t, dir, "README.md", "not a fixture")
+
+	fixtures, err := LoadCostFixtures(dir)
+	require.NoError(t, err)
+	require.Len(t, fixtures, 2)
+
+	assert.Equal(t, "ec2", fixtures[0].Name)
+	assert.Equal(t, "t3.micro", fixtures[0].Resource.SKU)
+	assert.Equal(t, "USD", fixtures[0].currency())
+	assert.InDelta(t, 0.3796, fixtures[0].tolerance(), 1e-9, "defaults to 5%")
+	assert.True(t, fixtures[0].required)
+
+	assert.Equal(t, "EUR", fixtures[1].currency())
+	assert.InDelta(t, 0.5, fixtures[1].tolerance(), 1e-9)
+}
+
+// TestLoadCostFixtures_Invalid verifies malformed fixtures are rejected as invalid input
+func TestLoadCostFixtures_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		wantErr string
+	}{
+		"malformed":     {`{"name": `, "parse fixture.json"},
+		"no name":       {`{"resource": {"provider": "aws", "resource_type": "x"}}`, "fixture name is required"},
+		"no resource":   {`{"name": "x"}`, "resource provider and resource_type are required"},
+		"negative cost": {`{"name": "x", "resource": {"provider": "aws", "resource_type": "x"}, "cost_per_month": -1}`, "cost_per_month must be a non-negative number"},
+		"duplicate": {`[{"name": "x", "resource": {"provider": "aws", "resource_type": "x"}},
+			{"name": "x", "resource": {"provider": "aws", "resource_type": "y"}}]`, `fixture "x" is also defined`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFixture(t, dir, "fixture.json", tt.content)
+
+			_, err := LoadCostFixtures(dir)
+			require.ErrorIs(t, err, ErrInvalidInput)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	_, err := LoadCostFixtures(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+// TestBaselineFixtures verifies the fixtures shipped with the server are valid
+func TestBaselineFixtures(t *testing.T) {
+	fixtures, err := loadBaselineFixtures()
+	require.NoError(t, err)
+	require.NotEmpty(t, fixtures)
+	for _, fixture := range fixtures {
+		assert.Equal(t, "aws", fixture.Resource.Provider, fixture.Name)
+		assert.False(t, fixture.required, "baseline fixtures are skipped for unsupported resources")
+	}
+}
+
+// TestValidatePlugin_CostAccuracy verifies per-fixture results from plugin-shipped and supplied fixtures
+func TestValidatePlugin_CostAccuracy(t *testing.T) {
+	pluginDir := t.TempDir()
+	startFakeCostSource(t, pluginDir, &fakeCostSource{name: "aws-fake"})
+
+	// The plugin's own fixture replaces the baseline fixture of the same name
+	shipped := filepath.Join(pluginDir, "aws-fake", "fixtures")
+	writeFixture(t, shipped, "m5.json", `{"name": "aws-ec2-m5-large",
+		"resource": {"provider": "aws", "resource_type": "aws:ec2/instance:Instance", "sku": "m5.large"},
+		"cost_per_month": 75, "tolerance_absolute": 5}`)
+
+	supplied := t.TempDir()
+	writeFixture(t, supplied, "fixtures.json", `[
+		{"name": "overpriced", "resource": {"provider": "aws", "resource_type": "aws:ec2/instance:Instance", "sku": "t3.micro"},
+		 "cost_per_month": 10},
+		{"name": "lambda", "resource": {"provider": "aws", "resource_type": "aws:lambda/function:Function"}, "cost_per_month": 1}
+	]`)
+	fixtures, err := LoadCostFixtures(supplied)
+	require.NoError(t, err)
+
+	specAdapter := NewSpecAdapterWithPlugins(NewPluginAdapter(pluginDir, logging.Default()), logging.Default())
+	report, err := specAdapter.ValidatePlugin(context.Background(), &plugin.Plugin{Name: "aws-fake"}, ConformanceStandard, fixtures)
+	require.NoError(t, err)
+	assert.False(t, report.Passed)
+
+	results := make(map[string]*plugin.ValidationTest)
+	for _, test := range report.TestResults {
+		results[test.Name] = test
+	}
+
+	m5 := results["standard/accuracy/aws-ec2-m5-large"]
+	require.NotNil(t, m5)
+	assert.True(t, m5.Passed, "70.08 is within 5 of 75")
+	assert.Equal(t, 75.0, *m5.ExpectedCost)
+	assert.InDelta(t, 70.08, *m5.ActualCost, 1e-9)
+
+	overpriced := results["standard/accuracy/overpriced"]
+	require.NotNil(t, overpriced)
+	assert.False(t, overpriced.Passed)
+	assert.Equal(t, 10.0, *overpriced.ExpectedCost)
+	assert.InDelta(t, 7.592, *overpriced.ActualCost, 1e-9)
+	assert.Equal(t, "expected 10.0000 USD ±0.5000, got 7.5920 USD (-24.1%)", *overpriced.ErrorMessage)
+
+	lambda := results["standard/accuracy/lambda"]
+	require.NotNil(t, lambda)
+	assert.False(t, lambda.Passed, "supplied fixtures must be supported")
+	assert.Nil(t, lambda.ActualCost)
+	assert.Contains(t, *lambda.ErrorMessage, "plugin does not support aws:lambda/function:Function")
+
+	assert.NotContains(t, results, "standard/accuracy/aws-rds-db-t3-micro", "unsupported baseline fixtures are skipped")
+	assert.Nil(t, results["standard/projected_cost"].ExpectedCost, "only accuracy tests carry costs")
+}
+
+// TestValidatePlugin_InvalidShippedFixtures verifies broken plugin fixtures fail the accuracy suite
+func TestValidatePlugin_InvalidShippedFixtures(t *testing.T) {
+	pluginDir := t.TempDir()
+	startFakeCostSource(t, pluginDir, &fakeCostSource{name: "aws-fake"})
+	writeFixture(t, filepath.Join(pluginDir, "aws-fake", "fixtures"), "broken.json", `{"name": "broken"}`)
+
+	specAdapter := NewSpecAdapterWithPlugins(NewPluginAdapter(pluginDir, logging.Default()), logging.Default())
+	report, err := specAdapter.ValidatePlugin(context.Background(), &plugin.Plugin{Name: "aws-fake"}, ConformanceStandard, nil)
+	require.NoError(t, err)
+	assert.False(t, report.Passed)
+
+	last := report.TestResults[len(report.TestResults)-1]
+	assert.Equal(t, "standard/accuracy", last.Name)
+	assert.Contains(t, *last.ErrorMessage, "load plugin cost fixtures")
+}
+
+func writeFixture(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
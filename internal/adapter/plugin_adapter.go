@@ -3,27 +3,33 @@ package adapter
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/rshade/pulumicost-mcp/gen/plugin"
 	"github.com/rshade/pulumicost-mcp/internal/logging"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
+// ErrPluginNotFound is returned when no installed plugin has the requested name
+var ErrPluginNotFound = errors.New("plugin not found")
+
 // PluginAdapter handles plugin discovery and gRPC communication
 type PluginAdapter struct {
 	pluginDir       string
+	options         PluginAdapterOptions
 	logger          *logging.Logger
-	connections     map[string]*grpc.ClientConn
-	connMutex       sync.RWMutex
+	connections     map[string]*pluginConnection
+	connMutex       sync.RWMutex // guards the connections map only, never held while dialing
 	circuitBreakers map[string]*circuitBreaker
 	cbMutex         sync.RWMutex
+	integrity       integrityCache
 }
 
 // circuitBreaker tracks plugin failures and prevents cascade failures
@@ -44,22 +50,95 @@ type pluginMetadata struct {
 	Description string `json:"description"`
 	Providers   string `json:"providers"`
 	GRPCAddress string `json:"grpc_address"`
+	Binary      string `json:"binary,omitempty"` // executable relative to the plugin directory; defaults to bin/<name> or <name>
+	SpecVersion string `json:"spec_version"` // pulumicost-spec version range, e.g. ">=0.1.0 <0.3.0"
+	TLS         *TLSConfig `json:"tls,omitempty"`
+	Checksums   map[string]string `json:"checksums,omitempty"`
+	Signature   *pluginSignature  `json:"signature,omitempty"`
+	ConfigSchema  *configSchema  `json:"config_schema,omitempty"`
+	Configuration map[string]any `json:"configuration,omitempty"`
 	Capabilities struct {
 		SupportsProjectedCost bool `json:"supports_projected_cost"`
 		SupportsActualCost    bool `json:"supports_actual_cost"`
 		SupportsOptimization  bool `json:"supports_optimization"`
+		SupportsForecast      bool `json:"supports_forecast"`
 	} `json:"capabilities"`
+
+	// raw is the plugin.json document as read, which signatures cover
+	raw []byte
+	// tlsFingerprint identifies the effective TLS settings and the contents
+	// of their files when the plugin directory was scanned
+	tlsFingerprint string
+}
+
+// binaryCandidates returns the paths the plugin executable may have relative
+// to the plugin directory: the "binary" field, or bin/<name> and <name>
+func (m *pluginMetadata) binaryCandidates() []string {
+	if m.Binary != "" {
+		return []string{m.Binary}
+	}
+	return []string{filepath.Join("bin", m.Name), m.Name}
+}
+
+// providers returns the comma-separated providers list as a slice
+func (m *pluginMetadata) providers() []string {
+	var providers []string
+	for _, provider := range strings.Split(m.Providers, ",") {
+		if provider = strings.TrimSpace(provider); provider != "" {
+			providers = append(providers, provider)
+		}
+	}
+	return providers
+}
+
+// capabilities converts the plugin.json capability declaration to the API type
+func (m *pluginMetadata) capabilities() *plugin.PluginCapabilities {
+	return &plugin.PluginCapabilities{
+		SupportsProjected:    m.Capabilities.SupportsProjectedCost,
+		SupportsActual:       m.Capabilities.SupportsActualCost,
+		SupportsOptimization: m.Capabilities.SupportsOptimization,
+		SupportsForecast:     m.Capabilities.SupportsForecast,
+		SupportsProviders:    m.providers(),
+	}
+}
+
+// declaresCapability reports whether c declares at least one kind of cost support
+func declaresCapability(c *plugin.PluginCapabilities) bool {
+	return c.SupportsProjected || c.SupportsActual || c.SupportsOptimization || c.SupportsForecast
+}
+
+// loadPluginMetadata reads and parses a plugin.json file
+func loadPluginMetadata(path string) (*pluginMetadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read plugin metadata: %w", err)
+	}
+
+	var meta pluginMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("parse plugin metadata: %w", err)
+	}
+	meta.raw = data
+
+	return &meta, nil
 }
 
 // NewPluginAdapter creates a new plugin adapter
 func NewPluginAdapter(pluginDir string, logger *logging.Logger) *PluginAdapter {
+	return NewPluginAdapterWithOptions(pluginDir, PluginAdapterOptions{}, logger)
+}
+
+// NewPluginAdapterWithOptions creates a plugin adapter with optional settings
+func NewPluginAdapterWithOptions(pluginDir string, options PluginAdapterOptions, logger *logging.Logger) *PluginAdapter {
 	if logger == nil {
 		logger = logging.Default()
 	}
+	options.Configuration = normalizeConfiguration(options.Configuration)
 	return &PluginAdapter{
 		pluginDir:       pluginDir,
+		options:         options,
 		logger:          logger,
-		connections:     make(map[string]*grpc.ClientConn),
+		connections:     make(map[string]*pluginConnection),
 		circuitBreakers: make(map[string]*circuitBreaker),
 	}
 }
@@ -82,7 +161,7 @@ func (a *PluginAdapter) DiscoverPlugins(ctx context.Context) ([]*plugin.Plugin,
 	var plugins []*plugin.Plugin
 
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		if !entry.IsDir() || isReservedDir(entry.Name()) {
 			continue
 		}
 
@@ -96,91 +175,174 @@ func (a *PluginAdapter) DiscoverPlugins(ctx context.Context) ([]*plugin.Plugin,
 		}
 
 		// Load metadata
-		data, err := os.ReadFile(metadataPath)
+		meta, err := loadPluginMetadata(metadataPath)
 		if err != nil {
-			a.logger.Warn("failed to read plugin metadata", "plugin", entry.Name(), "error", err)
+			a.logger.Warn("failed to load plugin metadata", "plugin", entry.Name(), "error", err)
 			continue
 		}
 
-		var meta pluginMetadata
-		if err := json.Unmarshal(data, &meta); err != nil {
-			a.logger.Warn("failed to parse plugin metadata", "plugin", entry.Name(), "error", err)
+		// Reject plugins whose TLS settings cannot be used rather than dialing them in plaintext
+		if _, err := a.transportCredentials(meta, pluginPath); err != nil {
+			a.logger.Error("rejecting plugin with misconfigured TLS", "plugin", entry.Name(), "error", err)
 			continue
 		}
 
+		if err := a.admitPlugin(meta, pluginPath); err != nil {
+			a.logger.Error("rejecting unverified plugin", "plugin", entry.Name(), "error", err)
+			continue
+		}
+
+		compat := a.checkCompatibility(meta)
+
 		// Convert to plugin type
 		p := &plugin.Plugin{
-			Name:    meta.Name,
-			Version: meta.Version,
+			Name:          meta.Name,
+			Version:       meta.Version,
+			Capabilities:  meta.capabilities(),
+			Compatibility: compat.toAPI(),
 		}
 
 		if meta.Description != "" {
 			p.Description = &meta.Description
 		}
 
-		// Add to list
+		if _, err := a.resolveConfiguration(meta); err != nil {
+			a.logger.Warn("plugin configuration is invalid", "plugin", meta.Name, "error", err)
+			p.HealthStatus = unhealthyStatus(err)
+		}
+
+		// Add to list; incompatible plugins stay listed so the verdict is visible
 		plugins = append(plugins, p)
 
-		a.logger.Info("discovered plugin", "name", meta.Name, "version", meta.Version)
+		if compat.Status == CompatibilityIncompatible {
+			a.logger.Warn("discovered incompatible plugin", "name", meta.Name, "version", meta.Version, "reason", compat.Reason)
+		} else {
+			a.logger.Info("discovered plugin", "name", meta.Name, "version", meta.Version, "compatibility", string(compat.Status))
+		}
 	}
 
 	return plugins, nil
 }
 
-// EstablishConnection establishes gRPC connection to a plugin (T058)
+// PluginDetails describes a single installed plugin
+type PluginDetails struct {
+	Plugin      *plugin.Plugin
+	GRPCAddress string
+	Integrity   IntegrityResult
+	// Configuration is the merged plugin configuration with secrets redacted
+	Configuration map[string]any
+	// ConfigError is set when the configuration does not match the plugin's schema
+	ConfigError error
+}
+
+// DescribePlugin loads an installed plugin's metadata and integrity status.
+// Unlike DiscoverPlugins it also reports plugins that integrity enforcement
+// would reject, so operators can see why a plugin is not in use.
+func (a *PluginAdapter) DescribePlugin(ctx context.Context, name string) (*PluginDetails, error) {
+	if !validPluginName(name) {
+		return nil, fmt.Errorf("%w: %s", ErrPluginNotFound, name)
+	}
+	pluginPath := filepath.Join(a.pluginDir, name)
+
+	meta, err := loadPluginMetadata(filepath.Join(pluginPath, "plugin.json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrPluginNotFound, name)
+		}
+		return nil, err
+	}
+
+	p := &plugin.Plugin{
+		Name:          meta.Name,
+		Version:       meta.Version,
+		Capabilities:  meta.capabilities(),
+		Compatibility: a.checkCompatibility(meta).toAPI(),
+	}
+	if meta.Description != "" {
+		p.Description = &meta.Description
+	}
+
+	config, configErr := a.resolveConfiguration(meta)
+	if configErr != nil {
+		p.HealthStatus = unhealthyStatus(configErr)
+	}
+
+	return &PluginDetails{
+		Plugin:        p,
+		GRPCAddress:   meta.GRPCAddress,
+		Integrity:     a.verifyIntegrity(meta, pluginPath),
+		Configuration: redactConfiguration(meta.ConfigSchema, config),
+		ConfigError:   configErr,
+	}, nil
+}
+
+// EstablishConnection establishes gRPC connection to a plugin (T058).
+// The client is created without blocking; connectivity is verified lazily by
+// the first RPC, such as HealthCheck. Only the plugin's own connection state is
+// locked, so a slow plugin never stalls operations on other plugins.
 func (a *PluginAdapter) EstablishConnection(ctx context.Context, p *plugin.Plugin) error {
-	a.connMutex.Lock()
-	defer a.connMutex.Unlock()
+	pc := a.connectionState(p.Name)
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
 
 	// Check if already connected
-	if _, exists := a.connections[p.Name]; exists {
+	if pc.conn != nil {
 		return nil
 	}
 
 	// Check circuit breaker
-	if a.isCircuitOpenLocked(p.Name) {
+	if a.IsCircuitOpen(p.Name) {
 		return fmt.Errorf("circuit breaker open for plugin %s", p.Name)
 	}
 
 	// Load metadata to get gRPC address
-	metadataPath := filepath.Join(a.pluginDir, p.Name, "plugin.json")
-	data, err := os.ReadFile(metadataPath)
+	pluginPath := filepath.Join(a.pluginDir, p.Name)
+	meta, err := loadPluginMetadata(filepath.Join(pluginPath, "plugin.json"))
 	if err != nil {
 		a.recordFailure(p.Name)
-		return fmt.Errorf("read plugin metadata: %w", err)
+		return err
 	}
 
-	var meta pluginMetadata
-	if err := json.Unmarshal(data, &meta); err != nil {
-		a.recordFailure(p.Name)
-		return fmt.Errorf("parse plugin metadata: %w", err)
+	if err := a.admitPlugin(meta, pluginPath); err != nil {
+		return err
 	}
 
-	// Establish gRPC connection
-	dialCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
+	creds, err := a.transportCredentials(meta, pluginPath)
+	if err != nil {
+		return err
+	}
+
+	target, err := dialTarget(meta.GRPCAddress, pluginPath)
+	if err != nil {
+		return fmt.Errorf("plugin %s: %w", p.Name, err)
+	}
 
-	conn, err := grpc.DialContext(dialCtx, meta.GRPCAddress,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-	)
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
 	if err != nil {
 		a.recordFailure(p.Name)
-		return fmt.Errorf("dial plugin %s at %s: %w", p.Name, meta.GRPCAddress, err)
+		return fmt.Errorf("create client for plugin %s at %s: %w", p.Name, target, err)
+	}
+
+	// Start connecting in the background so the first RPC is not delayed
+	conn.Connect()
+
+	// The plugin may have been removed while the client was being created
+	if !a.registerConnection(p.Name, pc) {
+		_ = conn.Close()
+		return fmt.Errorf("plugin %s was removed while connecting", p.Name)
 	}
 
-	a.connections[p.Name] = conn
-	a.logger.Info("established connection to plugin", "name", p.Name, "address", meta.GRPCAddress)
+	pc.conn = conn
+	pc.target = target
+	a.logger.Info("established connection to plugin", "name", p.Name, "address", target)
 
 	return nil
 }
 
 // GetPluginCapabilities queries plugin capabilities via gRPC (T059)
 func (a *PluginAdapter) GetPluginCapabilities(ctx context.Context, p *plugin.Plugin) (*plugin.PluginCapabilities, error) {
-	a.connMutex.RLock()
-	conn, exists := a.connections[p.Name]
-	a.connMutex.RUnlock()
-
+	conn, exists := a.connection(p.Name)
 	if !exists {
 		return nil, fmt.Errorf("no connection to plugin %s", p.Name)
 	}
@@ -192,21 +354,12 @@ func (a *PluginAdapter) GetPluginCapabilities(ctx context.Context, p *plugin.Plu
 
 	// For now, return capabilities from metadata
 	// In full implementation, this would query the plugin via gRPC
-	metadataPath := filepath.Join(a.pluginDir, p.Name, "plugin.json")
-	data, err := os.ReadFile(metadataPath)
+	meta, err := loadPluginMetadata(filepath.Join(a.pluginDir, p.Name, "plugin.json"))
 	if err != nil {
-		return nil, fmt.Errorf("read plugin metadata: %w", err)
+		return nil, err
 	}
 
-	var meta pluginMetadata
-	if err := json.Unmarshal(data, &meta); err != nil {
-		return nil, fmt.Errorf("parse plugin metadata: %w", err)
-	}
-
-	capabilities := &plugin.PluginCapabilities{
-		SupportsProjected: meta.Capabilities.SupportsProjectedCost,
-		SupportsActual:    meta.Capabilities.SupportsActualCost,
-	}
+	capabilities := meta.capabilities()
 
 	// Use connection to verify it's alive
 	_ = conn
@@ -221,20 +374,19 @@ func (a *PluginAdapter) HealthCheck(ctx context.Context, p *plugin.Plugin) (stat
 		return "unhealthy", 0, fmt.Errorf("circuit breaker open")
 	}
 
-	a.connMutex.RLock()
-	conn, exists := a.connections[p.Name]
-	a.connMutex.RUnlock()
+	// A plugin with invalid configuration is unhealthy regardless of its gRPC status
+	if err := a.configurationError(p.Name); err != nil {
+		return "unhealthy", 0, err
+	}
 
+	conn, exists := a.connection(p.Name)
 	if !exists {
 		// Try to establish connection first
 		if err := a.EstablishConnection(ctx, p); err != nil {
 			return "unhealthy", 0, err
 		}
 
-		a.connMutex.RLock()
-		conn, exists = a.connections[p.Name]
-		a.connMutex.RUnlock()
-
+		conn, exists = a.connection(p.Name)
 		if !exists {
 			return "unhealthy", 0, fmt.Errorf("failed to establish connection")
 		}
@@ -340,17 +492,36 @@ func (a *PluginAdapter) resetFailures(pluginName string) {
 	}
 }
 
+// closeConnection closes and forgets the connection to a single plugin
+func (a *PluginAdapter) closeConnection(pluginName string) {
+	a.connMutex.Lock()
+	pc, exists := a.connections[pluginName]
+	delete(a.connections, pluginName)
+	a.connMutex.Unlock()
+
+	if exists {
+		pc.close(pluginName, a.logger)
+	}
+}
+
+// resetCircuitBreaker discards the circuit breaker state for a plugin
+func (a *PluginAdapter) resetCircuitBreaker(pluginName string) {
+	a.cbMutex.Lock()
+	defer a.cbMutex.Unlock()
+
+	delete(a.circuitBreakers, pluginName)
+}
+
 // Close closes all plugin connections
 func (a *PluginAdapter) Close() error {
 	a.connMutex.Lock()
-	defer a.connMutex.Unlock()
+	connections := a.connections
+	a.connections = make(map[string]*pluginConnection)
+	a.connMutex.Unlock()
 
-	for name, conn := range a.connections {
-		if err := conn.Close(); err != nil {
-			a.logger.Warn("failed to close plugin connection", "plugin", name, "error", err)
-		}
+	for name, pc := range connections {
+		pc.close(name, a.logger)
 	}
 
-	a.connections = make(map[string]*grpc.ClientConn)
 	return nil
 }
@@ -37,6 +37,11 @@ func TestDiscoverPlugins(t *testing.T) {
 	for _, p := range plugins {
 		pluginNames[p.Name] = true
 		assert.NotEmpty(t, p.Version, "plugin should have version")
+		require.NotNil(t, p.Capabilities, "plugin should have capabilities")
+		assert.True(t, p.Capabilities.SupportsProjected)
+		assert.True(t, p.Capabilities.SupportsActual)
+		assert.False(t, p.Capabilities.SupportsOptimization)
+		assert.NotEmpty(t, p.Capabilities.SupportsProviders)
 	}
 
 	assert.True(t, pluginNames["infracost"], "should find infracost plugin")
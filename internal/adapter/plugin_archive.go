@@ -0,0 +1,223 @@
+package adapter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// maxArchiveBytes caps the total size of files extracted from a plugin archive
+	maxArchiveBytes = 512 << 20
+	// maxArchiveFiles caps the number of entries in a plugin archive
+	maxArchiveFiles = 10000
+)
+
+// archiveSHA256 returns the hex sha256 digest of an archive file
+func archiveSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open archive: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("hash archive: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// verifyArchiveDigest checks an archive against an expected "sha256:<hex>" or bare hex digest
+func verifyArchiveDigest(path, expected string) error {
+	expected = strings.TrimPrefix(strings.TrimSpace(expected), "sha256:")
+	if expected == "" {
+		return nil
+	}
+
+	actual, err := archiveSHA256(path)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("%w: archive %s does not match expected sha256", ErrInvalidInput, filepath.Base(path))
+	}
+	return nil
+}
+
+// extractArchive unpacks a .tar.gz, .tgz or .zip plugin archive into dest.
+// Links, absolute paths and entries escaping dest are rejected.
+func extractArchive(archivePath, dest string) error {
+	name := strings.ToLower(archivePath)
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return extractTarGz(archivePath, dest)
+	case strings.HasSuffix(name, ".zip"):
+		return extractZip(archivePath, dest)
+	default:
+		return fmt.Errorf("%w: unsupported archive format %s (expected .tar.gz, .tgz or .zip)", ErrInvalidInput, filepath.Base(archivePath))
+	}
+}
+
+func extractTarGz(archivePath, dest string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("open archive: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("%w: read gzip archive: %w", ErrInvalidInput, err)
+	}
+	defer gz.Close()
+
+	w := &archiveWriter{dest: dest}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: read tar archive: %w", ErrInvalidInput, err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = w.mkdir(header.Name)
+		case tar.TypeReg:
+			err = w.writeFile(header.Name, header.FileInfo().Mode(), tr)
+		default:
+			err = fmt.Errorf("%w: archive entry %s is not a regular file or directory", ErrInvalidInput, header.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func extractZip(archivePath, dest string) error {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("%w: read zip archive: %w", ErrInvalidInput, err)
+	}
+	defer reader.Close()
+
+	w := &archiveWriter{dest: dest}
+	for _, f := range reader.File {
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			err = w.mkdir(f.Name)
+		case mode.IsRegular():
+			err = w.extractZipFile(f)
+		default:
+			err = fmt.Errorf("%w: archive entry %s is not a regular file or directory", ErrInvalidInput, f.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveWriter writes archive entries below dest while enforcing size limits
+type archiveWriter struct {
+	dest    string
+	written int64
+	files   int
+}
+
+// path resolves an archive entry name inside dest
+func (w *archiveWriter) path(name string) (string, error) {
+	w.files++
+	if w.files > maxArchiveFiles {
+		return "", fmt.Errorf("%w: archive has more than %d entries", ErrInvalidInput, maxArchiveFiles)
+	}
+
+	cleaned := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: archive entry %s escapes the plugin directory", ErrInvalidInput, name)
+	}
+	return filepath.Join(w.dest, cleaned), nil
+}
+
+func (w *archiveWriter) mkdir(name string) error {
+	path, err := w.path(name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(path, 0755)
+}
+
+func (w *archiveWriter) extractZipFile(f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: open archive entry %s: %w", ErrInvalidInput, f.Name, err)
+	}
+	defer rc.Close()
+	return w.writeFile(f.Name, f.Mode(), rc)
+}
+
+// writeFile copies an entry to disk, keeping only the executable bit from the archive
+func (w *archiveWriter) writeFile(name string, mode fs.FileMode, r io.Reader) error {
+	path, err := w.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	perm := fs.FileMode(0644)
+	if mode&0111 != 0 {
+		perm = 0755
+	}
+
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	remaining := maxArchiveBytes - w.written
+	n, err := io.Copy(out, io.LimitReader(r, remaining+1))
+	w.written += n
+	if err != nil {
+		return fmt.Errorf("%w: extract %s: %w", ErrInvalidInput, name, err)
+	}
+	if n > remaining {
+		return fmt.Errorf("%w: archive expands to more than %d bytes", ErrInvalidInput, int64(maxArchiveBytes))
+	}
+	return out.Close()
+}
+
+// archiveRoot finds the directory holding plugin.json: either the extraction
+// directory itself or its single top-level subdirectory
+func archiveRoot(dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, "plugin.json")); err == nil {
+		return dir, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		nested := filepath.Join(dir, entries[0].Name())
+		if _, err := os.Stat(filepath.Join(nested, "plugin.json")); err == nil {
+			return nested, nil
+		}
+	}
+	return "", fmt.Errorf("%w: archive does not contain plugin.json", ErrInvalidInput)
+}
@@ -0,0 +1,93 @@
+package adapter
+
+import (
+	"fmt"
+
+	"github.com/rshade/pulumicost-mcp/gen/plugin"
+)
+
+// CompatibilityStatus is the verdict of comparing a plugin with the server's pulumicost-spec version
+type CompatibilityStatus string
+
+const (
+	// CompatibilityCompatible means the plugin declares a range containing the spec version
+	CompatibilityCompatible CompatibilityStatus = "compatible"
+	// CompatibilityDeprecated means the plugin is usable but predates version
+	// declarations: it has no spec range or a non-semver version
+	CompatibilityDeprecated CompatibilityStatus = "deprecated"
+	// CompatibilityIncompatible means the plugin must not receive traffic
+	CompatibilityIncompatible CompatibilityStatus = "incompatible"
+)
+
+// Compatibility describes whether a plugin can be used with the server's spec version
+type Compatibility struct {
+	Status CompatibilityStatus
+	Reason string
+	// SpecRange is the pulumicost-spec range declared in plugin.json
+	SpecRange string
+}
+
+// checkCompatibility compares a plugin's declared spec range and version
+// with the configured pulumicost-spec version
+func (a *PluginAdapter) checkCompatibility(meta *pluginMetadata) Compatibility {
+	result := Compatibility{SpecRange: meta.SpecVersion}
+
+	if a.options.SpecVersion == "" {
+		result.Status = CompatibilityCompatible
+		result.Reason = "no pulumicost-spec version configured"
+		return result
+	}
+
+	spec, err := ParseVersion(a.options.SpecVersion)
+	if err != nil {
+		result.Status = CompatibilityIncompatible
+		result.Reason = fmt.Sprintf("server pulumicost-spec version is invalid: %v", err)
+		return result
+	}
+
+	if meta.SpecVersion == "" {
+		result.Status = CompatibilityDeprecated
+		result.Reason = "plugin.json does not declare a spec_version range"
+		return result
+	}
+
+	specRange, err := ParseVersionRange(meta.SpecVersion)
+	if err != nil {
+		result.Status = CompatibilityIncompatible
+		result.Reason = fmt.Sprintf("plugin.json spec_version: %v", err)
+		return result
+	}
+
+	if !specRange.Contains(spec) {
+		result.Status = CompatibilityIncompatible
+		result.Reason = fmt.Sprintf("plugin supports pulumicost-spec %s but the server uses %s", specRange, spec)
+		return result
+	}
+
+	if _, err := ParseVersion(meta.Version); err != nil {
+		result.Status = CompatibilityDeprecated
+		result.Reason = fmt.Sprintf("plugin version %q is not a semantic version", meta.Version)
+		return result
+	}
+
+	result.Status = CompatibilityCompatible
+	result.Reason = fmt.Sprintf("pulumicost-spec %s satisfies %s", spec, specRange)
+	return result
+}
+
+// toAPI converts the verdict to the API type
+func (c Compatibility) toAPI() *plugin.PluginCompatibility {
+	result := &plugin.PluginCompatibility{
+		Status: string(c.Status),
+		Reason: c.Reason,
+	}
+	if c.SpecRange != "" {
+		result.SpecRange = &c.SpecRange
+	}
+	return result
+}
+
+// isIncompatible reports whether discovery marked a plugin as unusable
+func isIncompatible(p *plugin.Plugin) bool {
+	return p.Compatibility != nil && p.Compatibility.Status == string(CompatibilityIncompatible)
+}
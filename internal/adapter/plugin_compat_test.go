@@ -0,0 +1,65 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiscoverPlugins_Compatibility verifies plugins are classified against the spec version
+func TestDiscoverPlugins_Compatibility(t *testing.T) {
+	tmpDir := t.TempDir()
+	writePluginJSON(t, tmpDir, "current", `{"name": "current", "version": "1.0.0", "spec_version": ">=0.1.0 <0.3.0"}`)
+	writePluginJSON(t, tmpDir, "legacy", `{"name": "legacy", "version": "1.0.0"}`)
+	writePluginJSON(t, tmpDir, "free-form", `{"name": "free-form", "version": "latest", "spec_version": "^0.2.0"}`)
+	writePluginJSON(t, tmpDir, "too-old", `{"name": "too-old", "version": "1.0.0", "spec_version": "^0.1.0"}`)
+	writePluginJSON(t, tmpDir, "bad-range", `{"name": "bad-range", "version": "1.0.0", "spec_version": "newest"}`)
+
+	adapter := NewPluginAdapterWithOptions(tmpDir, PluginAdapterOptions{SpecVersion: "0.2.1"}, logging.Default())
+	plugins, err := adapter.DiscoverPlugins(context.Background())
+	require.NoError(t, err)
+
+	verdicts := make(map[string]string)
+	reasons := make(map[string]string)
+	for _, p := range plugins {
+		require.NotNil(t, p.Compatibility)
+		verdicts[p.Name] = p.Compatibility.Status
+		reasons[p.Name] = p.Compatibility.Reason
+	}
+
+	assert.Equal(t, map[string]string{
+		"current":   "compatible",
+		"legacy":    "deprecated",
+		"free-form": "deprecated",
+		"too-old":   "incompatible",
+		"bad-range": "incompatible",
+	}, verdicts, "incompatible plugins are still listed")
+	assert.Contains(t, reasons["too-old"], "plugin supports pulumicost-spec ^0.1.0 but the server uses 0.2.1")
+	assert.Contains(t, reasons["legacy"], "does not declare a spec_version range")
+	assert.Contains(t, reasons["free-form"], `"latest" is not a semantic version`)
+}
+
+// TestPluginRouter_SkipsIncompatible verifies incompatible plugins receive no traffic
+func TestPluginRouter_SkipsIncompatible(t *testing.T) {
+	tmpDir := t.TempDir()
+	writePluginJSON(t, tmpDir, "aws-new", `{"name": "aws-new", "version": "2.0.0", "providers": "aws",
+		"spec_version": "^1.0.0", "capabilities": {"supports_actual_cost": true}}`)
+	writePluginJSON(t, tmpDir, "aws-old", `{"name": "aws-old", "version": "1.0.0", "providers": "aws,gcp",
+		"spec_version": "^0.1.0", "capabilities": {"supports_actual_cost": true}}`)
+
+	plugins := NewPluginAdapterWithOptions(tmpDir, PluginAdapterOptions{SpecVersion: "1.1.0"}, logging.Default())
+	router := NewPluginRouter(plugins, []string{"aws-old"}, nil)
+	ctx := context.Background()
+
+	candidates, err := router.Candidates(ctx, "aws", CostKindActual)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "aws-new", candidates[0].Name)
+
+	providers, err := router.Providers(ctx, CostKindActual)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aws"}, providers)
+}
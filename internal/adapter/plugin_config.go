@@ -0,0 +1,307 @@
+package adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/gen/plugin"
+)
+
+// redactedValue replaces secret configuration values in tool output
+const redactedValue = "***"
+
+// secretKeyPattern catches secret-looking keys that a schema forgot to mark
+var secretKeyPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|private_?key|credential)`)
+
+// configSchema is the subset of JSON Schema that plugin.json "config_schema"
+// may use to describe a plugin's configuration. Properties marked "secret" or
+// "writeOnly", or with format "password", are redacted in tool output.
+type configSchema struct {
+	Type                 string                   `json:"type,omitempty"`
+	Description          string                   `json:"description,omitempty"`
+	Properties           map[string]*configSchema `json:"properties,omitempty"`
+	Required             []string                 `json:"required,omitempty"`
+	AdditionalProperties *bool                    `json:"additionalProperties,omitempty"`
+	Items                *configSchema            `json:"items,omitempty"`
+	Enum                 []any                    `json:"enum,omitempty"`
+	Default              any                      `json:"default,omitempty"`
+	Minimum              *float64                 `json:"minimum,omitempty"`
+	Maximum              *float64                 `json:"maximum,omitempty"`
+	MinLength            *int                     `json:"minLength,omitempty"`
+	MaxLength            *int                     `json:"maxLength,omitempty"`
+	Pattern              string                   `json:"pattern,omitempty"`
+	Format               string                   `json:"format,omitempty"`
+	WriteOnly            bool                     `json:"writeOnly,omitempty"`
+	Secret               bool                     `json:"secret,omitempty"`
+}
+
+// isSecret reports whether values described by the schema must be redacted
+func (s *configSchema) isSecret() bool {
+	return s != nil && (s.Secret || s.WriteOnly || s.Format == "password")
+}
+
+// property returns the schema for an object property, or nil
+func (s *configSchema) property(name string) *configSchema {
+	if s == nil {
+		return nil
+	}
+	return s.Properties[name]
+}
+
+// items returns the schema for array elements, or nil
+func (s *configSchema) items() *configSchema {
+	if s == nil {
+		return nil
+	}
+	return s.Items
+}
+
+// validate checks value against the schema and returns one message per
+// violation, each prefixed with the path of the offending field. Values are
+// never echoed so secrets cannot leak through error messages.
+func (s *configSchema) validate(value any, path string) []string {
+	if s.Type != "" && !matchesType(s.Type, value) {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", path, s.Type, jsonType(value))}
+	}
+
+	var errs []string
+	if len(s.Enum) > 0 && !containsValue(s.Enum, value) {
+		allowed, _ := json.Marshal(s.Enum)
+		errs = append(errs, fmt.Sprintf("%s: must be one of %s", path, allowed))
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := len([]rune(v))
+		if s.MinLength != nil && length < *s.MinLength {
+			errs = append(errs, fmt.Sprintf("%s: must be at least %d characters", path, *s.MinLength))
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			errs = append(errs, fmt.Sprintf("%s: must be at most %d characters", path, *s.MaxLength))
+		}
+		if s.Pattern != "" {
+			re, err := regexp.Compile(s.Pattern)
+			switch {
+			case err != nil:
+				errs = append(errs, fmt.Sprintf("%s: schema pattern is invalid: %v", path, err))
+			case !re.MatchString(v):
+				errs = append(errs, fmt.Sprintf("%s: must match pattern %s", path, s.Pattern))
+			}
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			errs = append(errs, fmt.Sprintf("%s: must be >= %v", path, *s.Minimum))
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			errs = append(errs, fmt.Sprintf("%s: must be <= %v", path, *s.Maximum))
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				errs = append(errs, fmt.Sprintf("%s.%s: is required", path, name))
+			}
+		}
+		for _, name := range sortedKeys(v) {
+			if prop := s.property(name); prop != nil {
+				errs = append(errs, prop.validate(v[name], path+"."+name)...)
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				errs = append(errs, fmt.Sprintf("%s.%s: is not a known setting", path, name))
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				errs = append(errs, s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+
+	return errs
+}
+
+// applyDefaults fills in schema defaults for settings that are not set
+func (s *configSchema) applyDefaults(config map[string]any) {
+	if s == nil {
+		return
+	}
+	for name, prop := range s.Properties {
+		value, set := config[name]
+		if !set && prop.Default != nil {
+			config[name] = prop.Default
+			continue
+		}
+		if nested, ok := value.(map[string]any); ok {
+			prop.applyDefaults(nested)
+		}
+	}
+}
+
+// resolveConfiguration merges schema defaults, plugin.json "configuration"
+// and server-side overrides, then validates the result against the schema.
+// The merged configuration is returned even when it is invalid.
+func (a *PluginAdapter) resolveConfiguration(meta *pluginMetadata) (map[string]any, error) {
+	config := mergeConfiguration(map[string]any{}, meta.Configuration)
+	config = mergeConfiguration(config, a.options.Configuration[meta.Name])
+	meta.ConfigSchema.applyDefaults(config)
+
+	if meta.ConfigSchema == nil {
+		return config, nil
+	}
+	if errs := meta.ConfigSchema.validate(config, "configuration"); len(errs) > 0 {
+		return config, fmt.Errorf("invalid configuration: %s", strings.Join(errs, "; "))
+	}
+	return config, nil
+}
+
+// configurationError reports whether an installed plugin's configuration is invalid
+func (a *PluginAdapter) configurationError(pluginName string) error {
+	meta, err := loadPluginMetadata(filepath.Join(a.pluginDir, pluginName, "plugin.json"))
+	if err != nil {
+		// Missing metadata is reported by the connection path
+		return nil
+	}
+	_, err = a.resolveConfiguration(meta)
+	return err
+}
+
+// unhealthyStatus reports a plugin as unhealthy because of err
+func unhealthyStatus(err error) *plugin.HealthStatus {
+	lastCheck := time.Now().Format(time.RFC3339)
+	message := err.Error()
+	return &plugin.HealthStatus{
+		Status:       "unhealthy",
+		LastCheck:    &lastCheck,
+		ErrorMessage: &message,
+	}
+}
+
+// mergeConfiguration deep-merges overrides into base, returning base
+func mergeConfiguration(base, overrides map[string]any) map[string]any {
+	for key, value := range overrides {
+		if nested, ok := value.(map[string]any); ok {
+			if existing, ok := base[key].(map[string]any); ok {
+				base[key] = mergeConfiguration(existing, nested)
+				continue
+			}
+			base[key] = mergeConfiguration(map[string]any{}, nested)
+			continue
+		}
+		base[key] = value
+	}
+	return base
+}
+
+// redactConfiguration returns a copy of config with secret values replaced
+func redactConfiguration(schema *configSchema, config map[string]any) map[string]any {
+	redacted := make(map[string]any, len(config))
+	for key, value := range config {
+		prop := schema.property(key)
+		if prop.isSecret() || secretKeyPattern.MatchString(key) {
+			redacted[key] = redactedValue
+			continue
+		}
+		redacted[key] = redactValue(prop, value)
+	}
+	return redacted
+}
+
+// redactValue redacts the secrets nested in objects and array elements of value
+func redactValue(schema *configSchema, value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return redactConfiguration(schema, v)
+	case []any:
+		items := schema.items()
+		redacted := make([]any, len(v))
+		for i, item := range v {
+			if items.isSecret() {
+				redacted[i] = redactedValue
+			} else {
+				redacted[i] = redactValue(items, item)
+			}
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// normalizeConfiguration round-trips server overrides through JSON so YAML
+// integers and nested maps compare like values read from plugin.json
+func normalizeConfiguration(overrides map[string]map[string]any) map[string]map[string]any {
+	if len(overrides) == 0 {
+		return overrides
+	}
+	normalized := make(map[string]map[string]any, len(overrides))
+	for name, config := range overrides {
+		data, err := json.Marshal(config)
+		if err != nil {
+			normalized[name] = config
+			continue
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			normalized[name] = config
+			continue
+		}
+		normalized[name] = decoded
+	}
+	return normalized
+}
+
+func matchesType(schemaType string, value any) bool {
+	switch schemaType {
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonType(value) == schemaType
+	}
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func containsValue(values []any, value any) bool {
+	for _, candidate := range values {
+		if fmt.Sprint(candidate) == fmt.Sprint(value) && jsonType(candidate) == jsonType(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
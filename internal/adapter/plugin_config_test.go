@@ -0,0 +1,223 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rshade/pulumicost-mcp/gen/plugin"
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigSchema = `{
+	"type": "object",
+	"required": ["region", "api_key"],
+	"additionalProperties": false,
+	"properties": {
+		"region": {"type": "string", "enum": ["us-east-1", "us-west-2"]},
+		"api_key": {"type": "string", "secret": true, "minLength": 8},
+		"poll_interval": {"type": "string", "pattern": "^[0-9]+[smh]$", "default": "1h"},
+		"batch_size": {"type": "integer", "minimum": 1, "maximum": 500},
+		"auth": {
+			"type": "object",
+			"properties": {
+				"client_id": {"type": "string"},
+				"client_key": {"type": "string", "format": "password"}
+			}
+		}
+	}
+}`
+
+// TestConfigSchema_Validate verifies violations are reported with field paths and without values
+func TestConfigSchema_Validate(t *testing.T) {
+	var schema configSchema
+	require.NoError(t, json.Unmarshal([]byte(testConfigSchema), &schema))
+
+	tests := []struct {
+		name   string
+		config string
+		want   []string
+	}{
+		{
+			name:   "valid",
+			config: `{"region": "us-east-1", "api_key": "abcdefgh", "batch_size": 10}`,
+		},
+		{
+			name:   "missing required",
+			config: `{"region": "us-east-1"}`,
+			want:   []string{"configuration.api_key: is required"},
+		},
+		{
+			name:   "wrong types",
+			config: `{"region": "us-east-1", "api_key": "abcdefgh", "batch_size": 2.5, "auth": "token"}`,
+			want: []string{
+				"configuration.auth: expected object, got string",
+				"configuration.batch_size: expected integer, got number",
+			},
+		},
+		{
+			name:   "constraints",
+			config: `{"region": "eu-west-1", "api_key": "short", "batch_size": 1000, "poll_interval": "hourly"}`,
+			want: []string{
+				"configuration.api_key: must be at least 8 characters",
+				"configuration.batch_size: must be <= 500",
+				"configuration.poll_interval: must match pattern ^[0-9]+[smh]$",
+				`configuration.region: must be one of ["us-east-1","us-west-2"]`,
+			},
+		},
+		{
+			name:   "unknown setting",
+			config: `{"region": "us-east-1", "api_key": "abcdefgh", "regoin": "us-west-2"}`,
+			want:   []string{"configuration.regoin: is not a known setting"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config map[string]any
+			require.NoError(t, json.Unmarshal([]byte(tt.config), &config))
+
+			errs := schema.validate(config, "configuration")
+			assert.Equal(t, tt.want, errs)
+			for _, msg := range errs {
+				assert.NotContains(t, msg, "short", "values must not be echoed")
+			}
+		})
+	}
+}
+
+// TestResolveConfiguration_MergeOrder verifies server overrides win over plugin.json, which wins over defaults
+func TestResolveConfiguration_MergeOrder(t *testing.T) {
+	pluginDir := t.TempDir()
+	writeConfiguredPlugin(t, pluginDir, "aws-cur", `{"region": "us-east-1", "api_key": "from-plugin-json", "auth": {"client_id": "abc"}}`)
+
+	adapter := NewPluginAdapterWithOptions(pluginDir, PluginAdapterOptions{
+		Configuration: map[string]map[string]any{
+			"aws-cur": {"api_key": "from-server-config", "auth": map[string]any{"client_key": "xyz"}},
+		},
+	}, logging.Default())
+
+	meta, err := loadPluginMetadata(filepath.Join(pluginDir, "aws-cur", "plugin.json"))
+	require.NoError(t, err)
+
+	config, err := adapter.resolveConfiguration(meta)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"region":        "us-east-1",
+		"api_key":       "from-server-config",
+		"poll_interval": "1h",
+		"auth":          map[string]any{"client_id": "abc", "client_key": "xyz"},
+	}, config)
+}
+
+// TestRedactConfiguration verifies schema-marked and secret-looking values are redacted
+func TestRedactConfiguration(t *testing.T) {
+	var schema configSchema
+	require.NoError(t, json.Unmarshal([]byte(testConfigSchema), &schema))
+
+	redacted := redactConfiguration(&schema, map[string]any{
+		"region":       "us-east-1",
+		"api_key":      "abcdefgh",
+		"auth":         map[string]any{"client_id": "abc", "client_key": "xyz"},
+		"db_password":  "hunter2",
+		"access_token": "t0k3n",
+	})
+
+	assert.Equal(t, map[string]any{
+		"region":       "us-east-1",
+		"api_key":      "***",
+		"auth":         map[string]any{"client_id": "abc", "client_key": "***"},
+		"db_password":  "***",
+		"access_token": "***",
+	}, redacted)
+
+	assert.Equal(t, map[string]any{"api_key": "***"}, redactConfiguration(nil, map[string]any{"api_key": "abc"}))
+}
+
+// TestRedactConfiguration_Arrays verifies secrets inside array elements are redacted
+func TestRedactConfiguration_Arrays(t *testing.T) {
+	var schema configSchema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"accounts": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"id": {"type": "string"},
+						"pin": {"type": "string", "secret": true}
+					}
+				}
+			},
+			"keys": {"type": "array", "items": {"type": "string", "writeOnly": true}},
+			"regions": {"type": "array", "items": {"type": "string"}}
+		}
+	}`), &schema))
+
+	redacted := redactConfiguration(&schema, map[string]any{
+		"accounts": []any{
+			map[string]any{"id": "prod", "pin": "1234"},
+			map[string]any{"id": "dev", "pin": "0000", "client_secret": "xyz"},
+		},
+		"keys":    []any{"k1", "k2"},
+		"regions": []any{"us-east-1"},
+	})
+
+	assert.Equal(t, map[string]any{
+		"accounts": []any{
+			map[string]any{"id": "prod", "pin": "***"},
+			map[string]any{"id": "dev", "pin": "***", "client_secret": "***"},
+		},
+		"keys":    []any{"***", "***"},
+		"regions": []any{"us-east-1"},
+	}, redacted)
+}
+
+// TestDiscoverPlugins_InvalidConfiguration verifies invalid configuration marks a plugin unhealthy
+func TestDiscoverPlugins_InvalidConfiguration(t *testing.T) {
+	pluginDir := t.TempDir()
+	writeConfiguredPlugin(t, pluginDir, "aws-cur", `{"region": "ap-south-1"}`)
+
+	adapter := NewPluginAdapter(pluginDir, logging.Default())
+	ctx := context.Background()
+
+	plugins, err := adapter.DiscoverPlugins(ctx)
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	require.NotNil(t, plugins[0].HealthStatus)
+	assert.Equal(t, "unhealthy", plugins[0].HealthStatus.Status)
+	require.NotNil(t, plugins[0].HealthStatus.ErrorMessage)
+	assert.Equal(t,
+		`invalid configuration: configuration.api_key: is required; configuration.region: must be one of ["us-east-1","us-west-2"]`,
+		*plugins[0].HealthStatus.ErrorMessage)
+
+	status, _, err := adapter.HealthCheck(ctx, &plugin.Plugin{Name: "aws-cur"})
+	assert.Equal(t, "unhealthy", status)
+	assert.ErrorContains(t, err, "configuration.api_key: is required")
+
+	details, err := adapter.DescribePlugin(ctx, "aws-cur")
+	require.NoError(t, err)
+	assert.Equal(t, "ap-south-1", details.Configuration["region"])
+	assert.ErrorContains(t, details.ConfigError, "configuration.region")
+}
+
+// writeConfiguredPlugin writes a plugin.json using testConfigSchema and the given configuration
+func writeConfiguredPlugin(t *testing.T, pluginDir, name, configuration string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, name), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, name, "plugin.json"), []byte(`{
+		"name": "`+name+`",
+		"version": "1.0.0",
+		"providers": "aws",
+		"grpc_address": "localhost:50051",
+		"capabilities": {"supports_actual_cost": true},
+		"config_schema": `+testConfigSchema+`,
+		"configuration": `+configuration+`
+	}`), 0644))
+}
@@ -0,0 +1,92 @@
+package adapter
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"google.golang.org/grpc"
+)
+
+// pluginConnection holds the gRPC client for one plugin. Its mutex serializes
+// connection setup and teardown for that plugin only.
+type pluginConnection struct {
+	mu     sync.Mutex
+	conn   *grpc.ClientConn
+	target string
+}
+
+// close releases the plugin's gRPC client, if any
+func (pc *pluginConnection) close(pluginName string, logger *logging.Logger) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if pc.conn == nil {
+		return
+	}
+	if err := pc.conn.Close(); err != nil {
+		logger.Warn("failed to close plugin connection", "plugin", pluginName, "error", err)
+	}
+	pc.conn = nil
+}
+
+// connectionState returns the connection state for a plugin, creating it if needed
+func (a *PluginAdapter) connectionState(pluginName string) *pluginConnection {
+	a.connMutex.Lock()
+	defer a.connMutex.Unlock()
+
+	pc, exists := a.connections[pluginName]
+	if !exists {
+		pc = &pluginConnection{}
+		a.connections[pluginName] = pc
+	}
+	return pc
+}
+
+// registerConnection reports whether pc is still the tracked state for a plugin
+func (a *PluginAdapter) registerConnection(pluginName string, pc *pluginConnection) bool {
+	a.connMutex.RLock()
+	defer a.connMutex.RUnlock()
+
+	return a.connections[pluginName] == pc
+}
+
+// connection returns the established gRPC client for a plugin
+func (a *PluginAdapter) connection(pluginName string) (*grpc.ClientConn, bool) {
+	a.connMutex.RLock()
+	pc, exists := a.connections[pluginName]
+	a.connMutex.RUnlock()
+
+	if !exists {
+		return nil, false
+	}
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	return pc.conn, pc.conn != nil
+}
+
+// dialTarget converts a plugin.json grpc_address into a gRPC target.
+// "unix://" addresses may be relative, in which case the socket is looked up
+// in the plugin's directory; host:port addresses are passed through.
+func dialTarget(address, pluginPath string) (string, error) {
+	if address == "" {
+		return "", fmt.Errorf("grpc_address is not set")
+	}
+
+	socket, isUnix := strings.CutPrefix(address, "unix://")
+	if !isUnix {
+		return address, nil
+	}
+
+	if socket == "" {
+		return "", fmt.Errorf("grpc_address %q has no socket path", address)
+	}
+	if !filepath.IsAbs(socket) {
+		socket = filepath.Join(pluginPath, socket)
+	}
+	return "unix://" + socket, nil
+}
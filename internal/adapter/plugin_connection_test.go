@@ -0,0 +1,106 @@
+package adapter
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/gen/plugin"
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// TestDialTarget verifies grpc_address values are converted to gRPC targets
+func TestDialTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    string
+		wantErr bool
+	}{
+		{name: "tcp", address: "localhost:50051", want: "localhost:50051"},
+		{name: "absolute unix socket", address: "unix:///run/plugin.sock", want: "unix:///run/plugin.sock"},
+		{name: "relative unix socket", address: "unix://plugin.sock", want: "unix:///plugins/aws/plugin.sock"},
+		{name: "empty", address: "", wantErr: true},
+		{name: "unix without path", address: "unix://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dialTarget(tt.address, "/plugins/aws")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestEstablishConnection_UnixSocket verifies plugins can be reached over a unix domain socket
+func TestEstablishConnection_UnixSocket(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping gRPC integration test in short mode")
+	}
+
+	tmpDir := t.TempDir()
+	writePluginJSON(t, tmpDir, "local", `{"name": "local", "version": "1.0.0", "grpc_address": "unix://plugin.sock"}`)
+
+	listener, err := net.Listen("unix", filepath.Join(tmpDir, "local", "plugin.sock"))
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	adapter := NewPluginAdapter(tmpDir, logging.Default())
+	defer adapter.Close()
+
+	status, _, err := adapter.HealthCheck(context.Background(), &plugin.Plugin{Name: "local"})
+	require.NoError(t, err)
+	assert.Equal(t, "healthy", status)
+}
+
+// TestEstablishConnection_NonBlocking verifies a stuck plugin does not stall other plugins
+func TestEstablishConnection_NonBlocking(t *testing.T) {
+	tmpDir := t.TempDir()
+	// 192.0.2.0/24 is reserved for documentation and never answers
+	writePluginJSON(t, tmpDir, "unreachable", `{"name": "unreachable", "version": "1.0.0", "grpc_address": "192.0.2.1:50051"}`)
+	writePluginJSON(t, tmpDir, "other", `{"name": "other", "version": "1.0.0", "grpc_address": "localhost:50051"}`)
+
+	adapter := NewPluginAdapter(tmpDir, logging.Default())
+	defer adapter.Close()
+
+	ctx := context.Background()
+
+	start := time.Now()
+	require.NoError(t, adapter.EstablishConnection(ctx, &plugin.Plugin{Name: "unreachable"}))
+	assert.Less(t, time.Since(start), time.Second, "client creation should not wait for the plugin")
+
+	// Hold the first plugin's connection state as a slow dial would
+	stuck := adapter.connectionState("unreachable")
+	stuck.mu.Lock()
+	defer stuck.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- adapter.EstablishConnection(ctx, &plugin.Plugin{Name: "other"})
+	}()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("connecting one plugin was blocked by another")
+	}
+
+	_, connected := adapter.connection("other")
+	assert.True(t, connected)
+}
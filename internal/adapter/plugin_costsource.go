@@ -0,0 +1,488 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// costSourceService is the fully qualified pulumicost-spec plugin service
+const costSourceService = "/pulumicost.v1.CostSourceService/"
+
+// costSourceClient calls the pulumicost-spec CostSourceService on a plugin.
+// Messages are encoded by hand so the server does not depend on generated
+// spec stubs; only the fields the server reads are modelled.
+type costSourceClient struct {
+	conn grpc.ClientConnInterface
+}
+
+func newCostSourceClient(conn grpc.ClientConnInterface) *costSourceClient {
+	return &costSourceClient{conn: conn}
+}
+
+// Name returns the plugin's self-reported name
+func (c *costSourceClient) Name(ctx context.Context) (string, error) {
+	resp := &nameResponse{}
+	if err := c.invoke(ctx, "Name", &emptyMessage{}, resp); err != nil {
+		return "", err
+	}
+	return resp.Name, nil
+}
+
+// Supports reports whether the plugin can price a resource
+func (c *costSourceClient) Supports(ctx context.Context, resource *resourceDescriptor) (*supportsResponse, error) {
+	resp := &supportsResponse{}
+	if err := c.invoke(ctx, "Supports", &supportsRequest{Resource: resource}, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetProjectedCost prices a resource before deployment
+func (c *costSourceClient) GetProjectedCost(ctx context.Context, resource *resourceDescriptor) (*projectedCostResponse, error) {
+	resp := &projectedCostResponse{}
+	if err := c.invoke(ctx, "GetProjectedCost", &projectedCostRequest{Resource: resource}, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetActualCost returns historical costs of a deployed resource
+func (c *costSourceClient) GetActualCost(ctx context.Context, req *actualCostRequest) (*actualCostResponse, error) {
+	resp := &actualCostResponse{}
+	if err := c.invoke(ctx, "GetActualCost", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *costSourceClient) invoke(ctx context.Context, method string, req, resp wireMessage) error {
+	return c.conn.Invoke(ctx, costSourceService+method, req, resp, grpc.ForceCodec(wireCodec{}))
+}
+
+// wireMessage is a hand-encoded protobuf message
+type wireMessage interface {
+	marshal() []byte
+	unmarshal(data []byte) error
+}
+
+// wireCodec encodes wireMessages. It is registered under the "proto" name so
+// requests carry the standard application/grpc+proto content type.
+type wireCodec struct{}
+
+func (wireCodec) Name() string { return "proto" }
+
+func (wireCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(wireMessage)
+	if !ok {
+		return nil, fmt.Errorf("wire codec: cannot marshal %T", v)
+	}
+	return m.marshal(), nil
+}
+
+func (wireCodec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(wireMessage)
+	if !ok {
+		return fmt.Errorf("wire codec: cannot unmarshal into %T", v)
+	}
+	return m.unmarshal(data)
+}
+
+// emptyMessage is a request without fields, such as NameRequest
+type emptyMessage struct{}
+
+func (*emptyMessage) marshal() []byte             { return nil }
+func (*emptyMessage) unmarshal(data []byte) error { return walkFields(data, nil) }
+
+type nameResponse struct {
+	Name string
+}
+
+func (m *nameResponse) marshal() []byte {
+	return appendString(nil, 1, m.Name)
+}
+
+func (m *nameResponse) unmarshal(data []byte) error {
+	return walkFields(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if num == 1 && typ == protowire.BytesType {
+			m.Name = string(value)
+		}
+		return nil
+	})
+}
+
+// resourceDescriptor identifies a resource to price
+type resourceDescriptor struct {
+	Provider     string
+	ResourceType string
+	SKU          string
+	Region       string
+	Tags         map[string]string
+}
+
+func (m *resourceDescriptor) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.Provider)
+	b = appendString(b, 2, m.ResourceType)
+	b = appendString(b, 3, m.SKU)
+	b = appendString(b, 4, m.Region)
+	return appendStringMap(b, 5, m.Tags)
+}
+
+func (m *resourceDescriptor) unmarshal(data []byte) error {
+	return walkFields(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case 1:
+			m.Provider = string(value)
+		case 2:
+			m.ResourceType = string(value)
+		case 3:
+			m.SKU = string(value)
+		case 4:
+			m.Region = string(value)
+		case 5:
+			if m.Tags == nil {
+				m.Tags = make(map[string]string)
+			}
+			return unmarshalMapEntry(value, m.Tags)
+		}
+		return nil
+	})
+}
+
+type supportsRequest struct {
+	Resource *resourceDescriptor
+}
+
+func (m *supportsRequest) marshal() []byte {
+	return appendMessage(nil, 1, m.Resource)
+}
+
+func (m *supportsRequest) unmarshal(data []byte) error {
+	return walkFields(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if num == 1 && typ == protowire.BytesType {
+			m.Resource = &resourceDescriptor{}
+			return m.Resource.unmarshal(value)
+		}
+		return nil
+	})
+}
+
+type supportsResponse struct {
+	Supported bool
+	Reason    string
+}
+
+func (m *supportsResponse) marshal() []byte {
+	var b []byte
+	if m.Supported {
+		b = protowire.AppendTag(b, 1, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+	return appendString(b, 2, m.Reason)
+}
+
+func (m *supportsResponse) unmarshal(data []byte) error {
+	return walkFields(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			v, _ := protowire.ConsumeVarint(value)
+			m.Supported = v != 0
+		case num == 2 && typ == protowire.BytesType:
+			m.Reason = string(value)
+		}
+		return nil
+	})
+}
+
+type projectedCostRequest struct {
+	Resource *resourceDescriptor
+}
+
+func (m *projectedCostRequest) marshal() []byte {
+	return appendMessage(nil, 1, m.Resource)
+}
+
+func (m *projectedCostRequest) unmarshal(data []byte) error {
+	return walkFields(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if num == 1 && typ == protowire.BytesType {
+			m.Resource = &resourceDescriptor{}
+			return m.Resource.unmarshal(value)
+		}
+		return nil
+	})
+}
+
+type projectedCostResponse struct {
+	UnitPrice     float64
+	Currency      string
+	CostPerMonth  float64
+	BillingDetail string
+}
+
+func (m *projectedCostResponse) marshal() []byte {
+	var b []byte
+	b = appendDouble(b, 1, m.UnitPrice)
+	b = appendString(b, 2, m.Currency)
+	b = appendDouble(b, 3, m.CostPerMonth)
+	return appendString(b, 4, m.BillingDetail)
+}
+
+func (m *projectedCostResponse) unmarshal(data []byte) error {
+	return walkFields(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		switch {
+		case num == 1 && typ == protowire.Fixed64Type:
+			m.UnitPrice = decodeDouble(value)
+		case num == 2 && typ == protowire.BytesType:
+			m.Currency = string(value)
+		case num == 3 && typ == protowire.Fixed64Type:
+			m.CostPerMonth = decodeDouble(value)
+		case num == 4 && typ == protowire.BytesType:
+			m.BillingDetail = string(value)
+		}
+		return nil
+	})
+}
+
+type actualCostRequest struct {
+	ResourceID string
+	Start      time.Time
+	End        time.Time
+	Tags       map[string]string
+}
+
+func (m *actualCostRequest) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.ResourceID)
+	b = appendTimestamp(b, 2, m.Start)
+	b = appendTimestamp(b, 3, m.End)
+	return appendStringMap(b, 4, m.Tags)
+}
+
+func (m *actualCostRequest) unmarshal(data []byte) error {
+	return walkFields(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case 1:
+			m.ResourceID = string(value)
+		case 2:
+			m.Start = decodeTimestamp(value)
+		case 3:
+			m.End = decodeTimestamp(value)
+		case 4:
+			if m.Tags == nil {
+				m.Tags = make(map[string]string)
+			}
+			return unmarshalMapEntry(value, m.Tags)
+		}
+		return nil
+	})
+}
+
+type actualCostResponse struct {
+	Results []*actualCostResult
+}
+
+func (m *actualCostResponse) marshal() []byte {
+	var b []byte
+	for _, r := range m.Results {
+		b = appendMessage(b, 1, r)
+	}
+	return b
+}
+
+func (m *actualCostResponse) unmarshal(data []byte) error {
+	return walkFields(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if num == 1 && typ == protowire.BytesType {
+			r := &actualCostResult{}
+			if err := r.unmarshal(value); err != nil {
+				return err
+			}
+			m.Results = append(m.Results, r)
+		}
+		return nil
+	})
+}
+
+type actualCostResult struct {
+	Timestamp   time.Time
+	Cost        float64
+	UsageAmount float64
+	UsageUnit   string
+	Source      string
+}
+
+func (m *actualCostResult) marshal() []byte {
+	var b []byte
+	b = appendTimestamp(b, 1, m.Timestamp)
+	b = appendDouble(b, 2, m.Cost)
+	b = appendDouble(b, 3, m.UsageAmount)
+	b = appendString(b, 4, m.UsageUnit)
+	return appendString(b, 5, m.Source)
+}
+
+func (m *actualCostResult) unmarshal(data []byte) error {
+	return walkFields(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			m.Timestamp = decodeTimestamp(value)
+		case num == 2 && typ == protowire.Fixed64Type:
+			m.Cost = decodeDouble(value)
+		case num == 3 && typ == protowire.Fixed64Type:
+			m.UsageAmount = decodeDouble(value)
+		case num == 4 && typ == protowire.BytesType:
+			m.UsageUnit = string(value)
+		case num == 5 && typ == protowire.BytesType:
+			m.Source = string(value)
+		}
+		return nil
+	})
+}
+
+// walkFields calls fn for every field in a message. Varint and fixed64
+// values are passed in their encoded form; length-delimited values are
+// passed without their length prefix.
+func walkFields(data []byte, fn func(num protowire.Number, typ protowire.Type, value []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return fmt.Errorf("decode field tag: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		var value []byte
+		switch typ {
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(data)
+			if m < 0 {
+				return fmt.Errorf("decode field %d: %w", num, protowire.ParseError(m))
+			}
+			value, n = v, m
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return fmt.Errorf("decode field %d: %w", num, protowire.ParseError(n))
+			}
+			value = data[:n]
+		}
+		data = data[n:]
+
+		if fn != nil {
+			if err := fn(num, typ, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func appendDouble(b []byte, num protowire.Number, f float64) []byte {
+	if f == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(f))
+}
+
+func appendMessage(b []byte, num protowire.Number, m wireMessage) []byte {
+	if m == nil {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, m.marshal())
+}
+
+// appendStringMap encodes a map<string, string> field in key order
+func appendStringMap(b []byte, num protowire.Number, m map[string]string) []byte {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		var entry []byte
+		entry = appendString(entry, 1, k)
+		entry = appendString(entry, 2, m[k])
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		b = protowire.AppendBytes(b, entry)
+	}
+	return b
+}
+
+func unmarshalMapEntry(data []byte, m map[string]string) error {
+	var key, value string
+	err := walkFields(data, func(num protowire.Number, typ protowire.Type, v []byte) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case 1:
+			key = string(v)
+		case 2:
+			value = string(v)
+		}
+		return nil
+	})
+	m[key] = value
+	return err
+}
+
+// appendTimestamp encodes a google.protobuf.Timestamp field
+func appendTimestamp(b []byte, num protowire.Number, t time.Time) []byte {
+	if t.IsZero() {
+		return b
+	}
+	var ts []byte
+	if s := t.Unix(); s != 0 {
+		ts = protowire.AppendTag(ts, 1, protowire.VarintType)
+		ts = protowire.AppendVarint(ts, uint64(s))
+	}
+	if ns := t.Nanosecond(); ns != 0 {
+		ts = protowire.AppendTag(ts, 2, protowire.VarintType)
+		ts = protowire.AppendVarint(ts, uint64(ns))
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, ts)
+}
+
+func decodeTimestamp(data []byte) time.Time {
+	var seconds, nanos int64
+	_ = walkFields(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if typ != protowire.VarintType {
+			return nil
+		}
+		v, _ := protowire.ConsumeVarint(value)
+		switch num {
+		case 1:
+			seconds = int64(v)
+		case 2:
+			nanos = int64(int32(v))
+		}
+		return nil
+	})
+	return time.Unix(seconds, nanos).UTC()
+}
+
+func decodeDouble(data []byte) float64 {
+	v, _ := protowire.ConsumeFixed64(data)
+	return math.Float64frombits(v)
+}
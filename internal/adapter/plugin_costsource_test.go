@@ -0,0 +1,261 @@
+package adapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TestWireMessages_RoundTrip verifies hand-encoded spec messages survive encoding
+func TestWireMessages_RoundTrip(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 600, time.UTC)
+
+	tests := []struct {
+		name string
+		in   wireMessage
+		out  wireMessage
+	}{
+		{
+			name: "supports request",
+			in: &supportsRequest{Resource: &resourceDescriptor{
+				Provider: "aws", ResourceType: "aws:ec2/instance:Instance", SKU: "t3.micro", Region: "us-east-1",
+				Tags: map[string]string{"env": "prod", "team": "finops"},
+			}},
+			out: &supportsRequest{},
+		},
+		{
+			name: "projected cost response",
+			in:   &projectedCostResponse{UnitPrice: 0.0104, Currency: "USD", CostPerMonth: 7.59, BillingDetail: "on-demand"},
+			out:  &projectedCostResponse{},
+		},
+		{
+			name: "actual cost request",
+			in:   &actualCostRequest{ResourceID: "i-123", Start: start, End: start.Add(24 * time.Hour)},
+			out:  &actualCostRequest{},
+		},
+		{
+			name: "actual cost response",
+			in: &actualCostResponse{Results: []*actualCostResult{
+				{Timestamp: start, Cost: 1.5, UsageAmount: 24, UsageUnit: "hours", Source: "cur"},
+				{Timestamp: start.Add(time.Hour), Cost: 0.5},
+			}},
+			out: &actualCostResponse{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := wireCodec{}.Marshal(tt.in)
+			require.NoError(t, err)
+			require.NoError(t, wireCodec{}.Unmarshal(data, tt.out))
+			assert.Equal(t, tt.in, tt.out)
+		})
+	}
+}
+
+// TestWireCodec_RejectsForeignTypes verifies only wire messages are encoded
+func TestWireCodec_RejectsForeignTypes(t *testing.T) {
+	_, err := wireCodec{}.Marshal("not a message")
+	assert.ErrorContains(t, err, "cannot marshal string")
+
+	assert.ErrorContains(t, wireCodec{}.Unmarshal([]byte{0xff}, &nameResponse{}), "decode field tag")
+}
+
+// specFile is a hand transcription of proto/pulumicost/v1/costsource.proto
+// from pulumicost-spec. It is not generated from the spec: a field number or
+// type transcribed wrongly here and in wireCodec alike would go unnoticed.
+// Replace it with the spec's generated descriptor once pulumicost-spec is a
+// dependency of this module.
+func specFile(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+
+	field := func(name string, num int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string, repeated bool) *descriptorpb.FieldDescriptorProto {
+		label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+		if repeated {
+			label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+		}
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(num),
+			Label:    label.Enum(),
+			Type:     typ.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	const (
+		str     = descriptorpb.FieldDescriptorProto_TYPE_STRING
+		double  = descriptorpb.FieldDescriptorProto_TYPE_DOUBLE
+		boolean = descriptorpb.FieldDescriptorProto_TYPE_BOOL
+		msg     = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+	)
+	tagsEntry := &descriptorpb.DescriptorProto{
+		Name: proto.String("TagsEntry"),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			field("key", 1, str, "", false),
+			field("value", 2, str, "", false),
+		},
+		Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+	}
+	message := func(name string, fields ...*descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+		return &descriptorpb.DescriptorProto{Name: proto.String(name), Field: fields}
+	}
+	withTags := func(m *descriptorpb.DescriptorProto) *descriptorpb.DescriptorProto {
+		m.NestedType = []*descriptorpb.DescriptorProto{tagsEntry}
+		return m
+	}
+
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("pulumicost/v1/costsource.proto"),
+		Package:    proto.String("pulumicost.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			message("NameResponse", field("name", 1, str, "", false)),
+			withTags(message("ResourceDescriptor",
+				field("provider", 1, str, "", false),
+				field("resource_type", 2, str, "", false),
+				field("sku", 3, str, "", false),
+				field("region", 4, str, "", false),
+				field("tags", 5, msg, ".pulumicost.v1.ResourceDescriptor.TagsEntry", true),
+			)),
+			message("SupportsRequest", field("resource", 1, msg, ".pulumicost.v1.ResourceDescriptor", false)),
+			message("SupportsResponse",
+				field("supported", 1, boolean, "", false),
+				field("reason", 2, str, "", false),
+			),
+			message("GetProjectedCostRequest", field("resource", 1, msg, ".pulumicost.v1.ResourceDescriptor", false)),
+			message("GetProjectedCostResponse",
+				field("unit_price", 1, double, "", false),
+				field("currency", 2, str, "", false),
+				field("cost_per_month", 3, double, "", false),
+				field("billing_detail", 4, str, "", false),
+			),
+			withTags(message("GetActualCostRequest",
+				field("resource_id", 1, str, "", false),
+				field("start", 2, msg, ".google.protobuf.Timestamp", false),
+				field("end", 3, msg, ".google.protobuf.Timestamp", false),
+				field("tags", 4, msg, ".pulumicost.v1.GetActualCostRequest.TagsEntry", true),
+			)),
+			message("ActualCostResult",
+				field("timestamp", 1, msg, ".google.protobuf.Timestamp", false),
+				field("cost", 2, double, "", false),
+				field("usage_amount", 3, double, "", false),
+				field("usage_unit", 4, str, "", false),
+				field("source", 5, str, "", false),
+			),
+			message("GetActualCostResponse", field("results", 1, msg, ".pulumicost.v1.ActualCostResult", true)),
+		},
+	}, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+	return file
+}
+
+// TestWireMessages_SpecEncoding verifies the hand-written messages decode
+// bytes encoded by the protobuf runtime from the spec's schema, and encode
+// bytes it decodes
+func TestWireMessages_SpecEncoding(t *testing.T) {
+	file := specFile(t)
+	newMessage := func(name string) *dynamicpb.Message {
+		return dynamicpb.NewMessage(file.Messages().ByName(protoreflect.Name(name)))
+	}
+	start := time.Date(2026, 1, 2, 3, 4, 5, 600, time.UTC)
+	timestamp := func(at time.Time) protoreflect.Value {
+		return protoreflect.ValueOfMessage(timestamppb.New(at).ProtoReflect())
+	}
+
+	t.Run("projected cost response", func(t *testing.T) {
+		spec := newMessage("GetProjectedCostResponse")
+		fields := spec.Descriptor().Fields()
+		spec.Set(fields.ByName("unit_price"), protoreflect.ValueOfFloat64(0.0104))
+		spec.Set(fields.ByName("currency"), protoreflect.ValueOfString("USD"))
+		spec.Set(fields.ByName("cost_per_month"), protoreflect.ValueOfFloat64(7.59))
+		spec.Set(fields.ByName("billing_detail"), protoreflect.ValueOfString("on-demand"))
+		data, err := proto.Marshal(spec)
+		require.NoError(t, err)
+
+		decoded := &projectedCostResponse{}
+		require.NoError(t, wireCodec{}.Unmarshal(data, decoded))
+		assert.Equal(t, &projectedCostResponse{UnitPrice: 0.0104, Currency: "USD", CostPerMonth: 7.59, BillingDetail: "on-demand"}, decoded)
+	})
+
+	t.Run("supports response", func(t *testing.T) {
+		spec := newMessage("SupportsResponse")
+		spec.Set(spec.Descriptor().Fields().ByName("supported"), protoreflect.ValueOfBool(true))
+		spec.Set(spec.Descriptor().Fields().ByName("reason"), protoreflect.ValueOfString("ok"))
+		data, err := proto.Marshal(spec)
+		require.NoError(t, err)
+
+		decoded := &supportsResponse{}
+		require.NoError(t, wireCodec{}.Unmarshal(data, decoded))
+		assert.Equal(t, &supportsResponse{Supported: true, Reason: "ok"}, decoded)
+	})
+
+	t.Run("actual cost response", func(t *testing.T) {
+		spec := newMessage("GetActualCostResponse")
+		results := spec.Mutable(spec.Descriptor().Fields().ByName("results")).List()
+		result := newMessage("ActualCostResult")
+		fields := result.Descriptor().Fields()
+		result.Set(fields.ByName("timestamp"), timestamp(start))
+		result.Set(fields.ByName("cost"), protoreflect.ValueOfFloat64(1.5))
+		result.Set(fields.ByName("usage_amount"), protoreflect.ValueOfFloat64(24))
+		result.Set(fields.ByName("usage_unit"), protoreflect.ValueOfString("hours"))
+		result.Set(fields.ByName("source"), protoreflect.ValueOfString("cur"))
+		results.Append(protoreflect.ValueOfMessage(result))
+		data, err := proto.Marshal(spec)
+		require.NoError(t, err)
+
+		decoded := &actualCostResponse{}
+		require.NoError(t, wireCodec{}.Unmarshal(data, decoded))
+		assert.Equal(t, &actualCostResponse{Results: []*actualCostResult{
+			{Timestamp: start, Cost: 1.5, UsageAmount: 24, UsageUnit: "hours", Source: "cur"},
+		}}, decoded)
+	})
+
+	t.Run("supports request", func(t *testing.T) {
+		data, err := wireCodec{}.Marshal(&supportsRequest{Resource: &resourceDescriptor{
+			Provider: "aws", ResourceType: "aws:ec2/instance:Instance", SKU: "t3.micro", Region: "us-east-1",
+			Tags: map[string]string{"env": "prod"},
+		}})
+		require.NoError(t, err)
+
+		spec := newMessage("SupportsRequest")
+		require.NoError(t, proto.Unmarshal(data, spec))
+		assert.Empty(t, spec.GetUnknown(), "every field is known to the spec")
+		resource := spec.Get(spec.Descriptor().Fields().ByName("resource")).Message()
+		fields := resource.Descriptor().Fields()
+		assert.Equal(t, "aws", resource.Get(fields.ByName("provider")).String())
+		assert.Equal(t, "aws:ec2/instance:Instance", resource.Get(fields.ByName("resource_type")).String())
+		assert.Equal(t, "t3.micro", resource.Get(fields.ByName("sku")).String())
+		assert.Equal(t, "us-east-1", resource.Get(fields.ByName("region")).String())
+		assert.Equal(t, "prod", resource.Get(fields.ByName("tags")).Map().Get(protoreflect.ValueOfString("env").MapKey()).String())
+	})
+
+	t.Run("actual cost request", func(t *testing.T) {
+		data, err := wireCodec{}.Marshal(&actualCostRequest{ResourceID: "i-123", Start: start, End: start.Add(24 * time.Hour)})
+		require.NoError(t, err)
+
+		spec := newMessage("GetActualCostRequest")
+		require.NoError(t, proto.Unmarshal(data, spec))
+		assert.Empty(t, spec.GetUnknown(), "every field is known to the spec")
+		fields := spec.Descriptor().Fields()
+		assert.Equal(t, "i-123", spec.Get(fields.ByName("resource_id")).String())
+
+		end := spec.Get(fields.ByName("end")).Message()
+		endFields := end.Descriptor().Fields()
+		assert.Equal(t, start.Add(24*time.Hour).Unix(), end.Get(endFields.ByName("seconds")).Int())
+		assert.Equal(t, int64(600), end.Get(endFields.ByName("nanos")).Int())
+	})
+}
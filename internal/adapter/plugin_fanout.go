@@ -0,0 +1,240 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+)
+
+// ProviderRequest is one provider's share of a multi-provider cost request
+type ProviderRequest struct {
+	// Provider is the provider to price. An empty Provider stands for every
+	// provider the other requests do not name, and is priced through Core.
+	Provider string
+	// URNs are the resources this request should price; they are reported
+	// as unpriced if every plugin for the provider fails
+	URNs []string
+	// Call prices the provider's resources using the given plugin
+	Call func(ctx context.Context, pluginName string) (*CostResult, error)
+	// Core prices the provider through pulumicost-core when no plugin is
+	// available for it. Without Core such a provider is reported as failed.
+	Core func(ctx context.Context) (*CostResult, error)
+}
+
+// ProviderFailure describes a provider that could not be priced
+type ProviderFailure struct {
+	Provider     string
+	Plugin       string
+	Err          error
+	UnpricedURNs []string
+}
+
+// FanOutResult is the merged outcome of a fan-out request
+type FanOutResult struct {
+	Result   *CostResult
+	Failures []ProviderFailure
+}
+
+// Partial reports whether some providers could not be priced
+func (r *FanOutResult) Partial() bool {
+	return len(r.Failures) > 0
+}
+
+// FanOutExecutor prices several providers concurrently, each through the
+// plugin chosen by the router, and merges their results
+type FanOutExecutor struct {
+	router        *PluginRouter
+	maxConcurrent int
+	logger        *logging.Logger
+}
+
+// NewFanOutExecutor creates an executor that keeps at most maxConcurrent plugin calls in flight
+func NewFanOutExecutor(router *PluginRouter, maxConcurrent int, logger *logging.Logger) *FanOutExecutor {
+	if logger == nil {
+		logger = logging.Default()
+	}
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return &FanOutExecutor{
+		router:        router,
+		maxConcurrent: maxConcurrent,
+		logger:        logger,
+	}
+}
+
+// providerOutcome is the result of pricing a single provider
+type providerOutcome struct {
+	plugin string
+	result *CostResult
+	err    error
+}
+
+// Execute runs every request and merges the successful results in request
+// order. Failed providers are reported in FanOutResult.Failures instead of
+// failing the call; an error is returned only when nothing could be priced
+// or the request itself was invalid.
+func (e *FanOutExecutor) Execute(ctx context.Context, kind CostKind, requests []ProviderRequest) (*FanOutResult, error) {
+	outcomes := make([]providerOutcome, len(requests))
+	semaphore := make(chan struct{}, e.maxConcurrent)
+
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		wg.Add(1)
+		go func(i int, req ProviderRequest) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				outcomes[i].err = ctx.Err()
+				return
+			}
+
+			if req.Provider == "" {
+				outcomes[i].plugin = CoreSource
+				outcomes[i].result, outcomes[i].err = req.Core(ctx)
+				return
+			}
+
+			_, outcomes[i].err = e.router.Route(ctx, req.Provider, kind, func(ctx context.Context, pluginName string) error {
+				// Remember the last attempted plugin so failures can name it
+				outcomes[i].plugin = pluginName
+				result, err := req.Call(ctx, pluginName)
+				outcomes[i].result = result
+				return err
+			})
+			if errors.Is(outcomes[i].err, ErrNoPluginAvailable) && req.Core != nil {
+				e.logger.Debug("no plugin available, using pulumicost-core", "provider", req.Provider, "kind", string(kind))
+				outcomes[i].plugin = CoreSource
+				outcomes[i].result, outcomes[i].err = req.Core(ctx)
+			}
+		}(i, req)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	named := make(map[string]bool, len(requests))
+	for _, req := range requests {
+		if req.Provider != "" {
+			named[strings.ToLower(req.Provider)] = true
+		}
+	}
+
+	merged := &FanOutResult{Result: &CostResult{}}
+	var errs []error
+	for i, outcome := range outcomes {
+		req := requests[i]
+		provider := req.Provider
+		if provider == "" {
+			provider = CoreSource
+		}
+
+		if outcome.err == nil && outcome.result != nil && merged.Result.Currency != "" && outcome.result.Currency != merged.Result.Currency {
+			// Amounts are passed through as reported; never mix currencies
+			outcome.err = fmt.Errorf("currency %s does not match %s", outcome.result.Currency, merged.Result.Currency)
+		}
+
+		if outcome.err != nil {
+			if errors.Is(outcome.err, ErrInvalidInput) {
+				return nil, outcome.err
+			}
+			e.logger.Warn("provider could not be priced", "provider", provider, "plugin", outcome.plugin, "error", outcome.err)
+			merged.Failures = append(merged.Failures, ProviderFailure{
+				Provider:     provider,
+				Plugin:       outcome.plugin,
+				Err:          outcome.err,
+				UnpricedURNs: req.URNs,
+			})
+			errs = append(errs, fmt.Errorf("%s: %w", provider, outcome.err))
+			continue
+		}
+
+		keep := func(res ResourceCost) bool {
+			return res.Provider == nil || strings.EqualFold(*res.Provider, req.Provider)
+		}
+		switch {
+		case req.Provider == "":
+			keep = func(res ResourceCost) bool { return !named[res.ProviderName()] }
+		case outcome.plugin == CoreSource:
+			// Core prices the whole stack, so take only this provider's share
+			keep = func(res ResourceCost) bool { return res.ProviderName() == strings.ToLower(req.Provider) }
+		}
+		mergeProviderResult(merged.Result, outcome.result, keep)
+	}
+
+	if len(requests) > 0 && len(merged.Failures) == len(requests) {
+		return nil, fmt.Errorf("no provider could be priced: %w", errors.Join(errs...))
+	}
+
+	merged.Result.TotalMonthly = calculateTotal(merged.Result.Resources)
+	return merged, nil
+}
+
+// mergeProviderResult appends the resources of one request's result that
+// keep accepts, so plugins that cover several providers are not counted
+// twice. The daily breakdown is added as reported when every resource is
+// kept, and rebuilt from the kept resources' data points otherwise.
+func mergeProviderResult(merged, result *CostResult, keep func(ResourceCost) bool) {
+	if result == nil {
+		return
+	}
+	if merged.Currency == "" {
+		merged.Currency = result.Currency
+	}
+
+	var kept []ResourceCost
+	for _, res := range result.Resources {
+		if keep(res) {
+			kept = append(kept, res)
+		}
+	}
+	merged.Resources = append(merged.Resources, kept...)
+
+	byDate := make(map[string]float64)
+	if len(kept) == len(result.Resources) && result.Breakdown != nil && len(result.Breakdown.Daily) > 0 {
+		for _, daily := range result.Breakdown.Daily {
+			byDate[daily.Date] += daily.Amount
+		}
+	} else {
+		for _, res := range kept {
+			for _, point := range res.DataPoints {
+				day, err := time.Parse(time.RFC3339, point.Timestamp)
+				if err != nil {
+					continue
+				}
+				byDate[day.UTC().Format("2006-01-02")] += point.Cost
+			}
+		}
+	}
+	if len(byDate) == 0 {
+		return
+	}
+
+	if merged.Breakdown == nil {
+		merged.Breakdown = &CostBreakdown{}
+	}
+	for i := range merged.Breakdown.Daily {
+		daily := &merged.Breakdown.Daily[i]
+		if amount, ok := byDate[daily.Date]; ok {
+			daily.Amount += amount
+			delete(byDate, daily.Date)
+		}
+	}
+	for date, amount := range byDate {
+		merged.Breakdown.Daily = append(merged.Breakdown.Daily, DailyCost{Date: date, Amount: amount})
+	}
+	sort.Slice(merged.Breakdown.Daily, func(i, j int) bool {
+		return merged.Breakdown.Daily[i].Date < merged.Breakdown.Daily[j].Date
+	})
+}
@@ -0,0 +1,207 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFanOutExecutor_PartialFailure verifies one failing provider does not fail the request
+func TestFanOutExecutor_PartialFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	createMockPlugin(t, tmpDir, "aws-cur", "1.0.0", "aws")
+	createMockPlugin(t, tmpDir, "gcp-billing", "1.0.0", "gcp")
+
+	router := NewPluginRouter(NewPluginAdapter(tmpDir, logging.Default()), nil, nil)
+	executor := NewFanOutExecutor(router, 2, nil)
+
+	requests := []ProviderRequest{
+		{
+			Provider: "aws",
+			URNs:     []string{"urn:aws"},
+			Call: func(ctx context.Context, pluginName string) (*CostResult, error) {
+				return &CostResult{
+					Currency: "USD",
+					Resources: []ResourceCost{
+						{Urn: "urn:aws", Provider: stringPtr("aws"), MonthlyCost: 10},
+						{Urn: "urn:other", Provider: stringPtr("azure"), MonthlyCost: 99},
+					},
+				}, nil
+			},
+		},
+		{
+			Provider: "gcp",
+			URNs:     []string{"urn:gcp"},
+			Call: func(ctx context.Context, pluginName string) (*CostResult, error) {
+				return nil, errors.New("billing export unavailable")
+			},
+		},
+		{
+			Provider: "oracle",
+			URNs:     []string{"urn:oracle"},
+			Call: func(ctx context.Context, pluginName string) (*CostResult, error) {
+				t.Fatal("no plugin covers oracle")
+				return nil, nil
+			},
+		},
+	}
+
+	result, err := executor.Execute(context.Background(), CostKindProjected, requests)
+	require.NoError(t, err)
+	assert.True(t, result.Partial())
+
+	require.Len(t, result.Result.Resources, 1, "resources from other providers are dropped")
+	assert.Equal(t, 10.0, result.Result.TotalMonthly)
+	assert.Equal(t, "USD", result.Result.Currency)
+
+	require.Len(t, result.Failures, 2)
+	assert.Equal(t, "gcp", result.Failures[0].Provider)
+	assert.Equal(t, "gcp-billing", result.Failures[0].Plugin)
+	assert.Equal(t, []string{"urn:gcp"}, result.Failures[0].UnpricedURNs)
+	assert.Equal(t, "oracle", result.Failures[1].Provider)
+	assert.ErrorIs(t, result.Failures[1].Err, ErrNoPluginAvailable)
+}
+
+// TestFanOutExecutor_AllFail verifies an error is returned when nothing is priced
+func TestFanOutExecutor_AllFail(t *testing.T) {
+	tmpDir := t.TempDir()
+	createMockPlugin(t, tmpDir, "aws-cur", "1.0.0", "aws")
+
+	router := NewPluginRouter(NewPluginAdapter(tmpDir, logging.Default()), nil, nil)
+	executor := NewFanOutExecutor(router, 1, nil)
+
+	_, err := executor.Execute(context.Background(), CostKindActual, []ProviderRequest{{
+		Provider: "aws",
+		Call: func(ctx context.Context, pluginName string) (*CostResult, error) {
+			return nil, errors.New("boom")
+		},
+	}})
+	assert.Error(t, err)
+}
+
+// TestFanOutExecutor_MaxConcurrent verifies the number of in-flight plugin calls is bounded
+func TestFanOutExecutor_MaxConcurrent(t *testing.T) {
+	tmpDir := t.TempDir()
+	providers := []string{"aws", "azure", "gcp", "kubernetes", "oci"}
+	for _, provider := range providers {
+		createMockPlugin(t, tmpDir, provider+"-plugin", "1.0.0", provider)
+	}
+
+	router := NewPluginRouter(NewPluginAdapter(tmpDir, logging.Default()), nil, nil)
+	executor := NewFanOutExecutor(router, 2, nil)
+
+	var inFlight, peak int32
+	requests := make([]ProviderRequest, 0, len(providers))
+	for _, provider := range providers {
+		requests = append(requests, ProviderRequest{
+			Provider: provider,
+			Call: func(ctx context.Context, pluginName string) (*CostResult, error) {
+				current := atomic.AddInt32(&inFlight, 1)
+				for {
+					observed := atomic.LoadInt32(&peak)
+					if current <= observed || atomic.CompareAndSwapInt32(&peak, observed, current) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				atomic.AddInt32(&inFlight, -1)
+				return &CostResult{Currency: "USD"}, nil
+			},
+		})
+	}
+
+	result, err := executor.Execute(context.Background(), CostKindProjected, requests)
+	require.NoError(t, err)
+	assert.False(t, result.Partial())
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+}
+
+// TestFanOutExecutor_CoreFallback verifies providers without a plugin are
+// priced by pulumicost-core and the remainder request fills in the rest
+func TestFanOutExecutor_CoreFallback(t *testing.T) {
+	tmpDir := t.TempDir()
+	createMockPlugin(t, tmpDir, "aws-cur", "1.0.0", "aws")
+
+	router := NewPluginRouter(NewPluginAdapter(tmpDir, logging.Default()), nil, nil)
+	executor := NewFanOutExecutor(router, 2, nil)
+
+	day := func(date string, cost float64) ActualCostDataPoint {
+		return ActualCostDataPoint{Timestamp: date + "T00:00:00Z", Cost: cost}
+	}
+	core := func(ctx context.Context) (*CostResult, error) {
+		return &CostResult{
+			Currency: "USD",
+			Resources: []ResourceCost{
+				{Urn: "urn:aws", Type: "aws:ec2/instance:Instance", MonthlyCost: 10, DataPoints: []ActualCostDataPoint{day("2024-01-01", 1)}},
+				{Urn: "urn:gcp", Type: "gcp:compute/instance:Instance", MonthlyCost: 20, DataPoints: []ActualCostDataPoint{day("2024-01-01", 2)}},
+				{Urn: "urn:azure", Type: "azure:compute/virtualMachine:VirtualMachine", MonthlyCost: 30, DataPoints: []ActualCostDataPoint{day("2024-01-02", 3)}},
+			},
+			Breakdown: &CostBreakdown{Daily: []DailyCost{{Date: "2024-01-01", Amount: 3}, {Date: "2024-01-02", Amount: 3}}},
+		}, nil
+	}
+
+	result, err := executor.Execute(context.Background(), CostKindActual, []ProviderRequest{
+		{
+			Provider: "aws",
+			Call: func(ctx context.Context, pluginName string) (*CostResult, error) {
+				return &CostResult{
+					Currency:  "USD",
+					Resources: []ResourceCost{{Urn: "urn:aws", Provider: stringPtr("aws"), MonthlyCost: 12}},
+					Breakdown: &CostBreakdown{Daily: []DailyCost{{Date: "2024-01-01", Amount: 1.2}}},
+				}, nil
+			},
+			Core: core,
+		},
+		{
+			Provider: "gcp",
+			Call: func(ctx context.Context, pluginName string) (*CostResult, error) {
+				t.Fatal("no plugin covers gcp")
+				return nil, nil
+			},
+			Core: core,
+		},
+		{Core: core},
+	})
+
+	require.NoError(t, err)
+	assert.False(t, result.Partial())
+	urns := make([]string, 0, len(result.Result.Resources))
+	for _, res := range result.Result.Resources {
+		urns = append(urns, res.Urn)
+	}
+	assert.Equal(t, []string{"urn:aws", "urn:gcp", "urn:azure"}, urns, "each resource is counted once")
+	assert.Equal(t, 62.0, result.Result.TotalMonthly)
+
+	require.NotNil(t, result.Result.Breakdown)
+	assert.Equal(t, []DailyCost{{Date: "2024-01-01", Amount: 3.2}, {Date: "2024-01-02", Amount: 3}}, result.Result.Breakdown.Daily)
+}
+
+// TestFanOutExecutor_CoreFailure verifies a failing remainder request makes the result partial
+func TestFanOutExecutor_CoreFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	createMockPlugin(t, tmpDir, "aws-cur", "1.0.0", "aws")
+
+	router := NewPluginRouter(NewPluginAdapter(tmpDir, logging.Default()), nil, nil)
+	executor := NewFanOutExecutor(router, 1, nil)
+
+	result, err := executor.Execute(context.Background(), CostKindActual, []ProviderRequest{
+		{
+			Provider: "aws",
+			Call: func(ctx context.Context, pluginName string) (*CostResult, error) {
+				return &CostResult{Currency: "USD", Resources: []ResourceCost{{Urn: "urn:aws", MonthlyCost: 1}}}, nil
+			},
+		},
+		{Core: func(ctx context.Context) (*CostResult, error) { return nil, errors.New("core unavailable") }},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Partial())
+	require.Len(t, result.Failures, 1)
+	assert.Equal(t, CoreSource, result.Failures[0].Provider)
+}
@@ -0,0 +1,490 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/gen/plugin"
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+)
+
+const (
+	// rollbackDirName holds the previous version of each upgraded or removed plugin
+	rollbackDirName = ".rollback"
+	// stagingDirPrefix prefixes temporary directories archives are unpacked into
+	stagingDirPrefix = ".staging-"
+)
+
+// ErrPluginExists is returned when installing a plugin that is already installed
+var ErrPluginExists = errors.New("plugin already installed")
+
+// InstallSource identifies the archive to install, either a local path or a
+// release in the registry index
+type InstallSource struct {
+	// ArchivePath is a local .tar.gz, .tgz or .zip plugin archive
+	ArchivePath string
+	// SHA256 is the expected archive digest; required for registry releases
+	SHA256 string
+	// Name and Version select a registry release; an empty version means latest
+	Name    string
+	Version string
+}
+
+// InstallResult describes the outcome of a plugin management operation
+type InstallResult struct {
+	Name            string
+	Version         string
+	PreviousVersion string
+	// RollbackPath holds the replaced or removed version, if any
+	RollbackPath string
+	Validation   *plugin.PluginValidationReport
+	Integrity    IntegrityResult
+}
+
+// registryIndex is a file-based catalog of plugin releases. Archive paths are
+// relative to the index file.
+type registryIndex struct {
+	Plugins map[string]struct {
+		Latest   string `json:"latest"`
+		Versions map[string]struct {
+			Archive string `json:"archive"`
+			SHA256  string `json:"sha256"`
+		} `json:"versions"`
+	} `json:"plugins"`
+}
+
+// PluginInstaller installs, upgrades and removes plugins in the plugin directory.
+// Archives are unpacked into a staging directory and validated before they
+// replace the active plugin; the replaced version is kept for rollback.
+type PluginInstaller struct {
+	plugins       *PluginAdapter
+	spec          *SpecAdapter
+	registryIndex string
+	logger        *logging.Logger
+	mu            sync.Mutex // serializes operations on the plugin directory
+}
+
+// NewPluginInstaller creates an installer for the adapter's plugin directory.
+// registryIndex may be empty when only local archives are installed.
+func NewPluginInstaller(plugins *PluginAdapter, registryIndex string, logger *logging.Logger) *PluginInstaller {
+	if logger == nil {
+		logger = logging.Default()
+	}
+	return &PluginInstaller{
+		plugins:       plugins,
+		spec:          NewSpecAdapterWithPlugins(plugins, logger),
+		registryIndex: registryIndex,
+		logger:        logger,
+	}
+}
+
+// Install unpacks and activates a plugin that is not yet installed
+func (i *PluginInstaller) Install(ctx context.Context, src InstallSource) (*InstallResult, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	staged, err := i.stage(ctx, src, src.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer staged.cleanup()
+
+	target := filepath.Join(i.plugins.pluginDir, staged.meta.Name)
+	if _, err := os.Stat(target); err == nil {
+		return nil, fmt.Errorf("%w: %s (use upgrade_plugin)", ErrPluginExists, staged.meta.Name)
+	}
+
+	if err := os.Rename(staged.root, target); err != nil {
+		return nil, fmt.Errorf("activate plugin %s: %w", staged.meta.Name, err)
+	}
+
+	i.logger.Info("installed plugin", "name", staged.meta.Name, "version", staged.meta.Version)
+	return staged.result(), nil
+}
+
+// Upgrade replaces an installed plugin, keeping the current version for rollback
+func (i *PluginInstaller) Upgrade(ctx context.Context, name string, src InstallSource) (*InstallResult, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	current, err := i.installedMetadata(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if src.ArchivePath == "" {
+		src.Name = name
+	}
+	staged, err := i.stage(ctx, src, name)
+	if err != nil {
+		return nil, err
+	}
+	defer staged.cleanup()
+
+	rollbackPath, err := i.swap(name, staged.root)
+	if err != nil {
+		return nil, err
+	}
+
+	result := staged.result()
+	result.PreviousVersion = current.Version
+	result.RollbackPath = rollbackPath
+
+	i.logger.Info("upgraded plugin", "name", name, "from", current.Version, "to", staged.meta.Version)
+	return result, nil
+}
+
+// Rollback restores the version kept by the last upgrade or removal. The
+// version it replaces becomes the new rollback copy.
+func (i *PluginInstaller) Rollback(ctx context.Context, name string) (*InstallResult, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if !validPluginName(name) {
+		return nil, fmt.Errorf("%w: %s", ErrPluginNotFound, name)
+	}
+
+	saved := filepath.Join(i.plugins.pluginDir, rollbackDirName, name)
+	previous, err := loadPluginMetadata(filepath.Join(saved, "plugin.json"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: no previous version of %s to roll back to", ErrPluginNotFound, name)
+	}
+
+	result := &InstallResult{Name: name, Version: previous.Version}
+
+	current, err := i.installedMetadata(name)
+	switch {
+	case err == nil:
+		// Move the rollback copy aside first so swap can replace it with the current version
+		restoring, err := os.MkdirTemp(i.plugins.pluginDir, stagingDirPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("create staging directory: %w", err)
+		}
+		defer os.RemoveAll(restoring)
+
+		restored := filepath.Join(restoring, name)
+		if err := os.Rename(saved, restored); err != nil {
+			return nil, fmt.Errorf("restore plugin %s: %w", name, err)
+		}
+		if result.RollbackPath, err = i.swap(name, restored); err != nil {
+			_ = os.Rename(restored, saved)
+			return nil, err
+		}
+		result.PreviousVersion = current.Version
+	case errors.Is(err, ErrPluginNotFound):
+		if err := os.Rename(saved, filepath.Join(i.plugins.pluginDir, name)); err != nil {
+			return nil, fmt.Errorf("restore plugin %s: %w", name, err)
+		}
+	default:
+		return nil, err
+	}
+
+	i.logger.Info("rolled back plugin", "name", name, "version", previous.Version)
+	return result, nil
+}
+
+// Remove deactivates a plugin, keeping it for rollback
+func (i *PluginInstaller) Remove(ctx context.Context, name string) (*InstallResult, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	current, err := i.installedMetadata(name)
+	if err != nil {
+		return nil, err
+	}
+
+	rollbackPath, err := i.keepForRollback(name)
+	if err != nil {
+		return nil, err
+	}
+	i.plugins.closeConnection(name)
+	i.plugins.resetCircuitBreaker(name)
+
+	i.logger.Info("removed plugin", "name", name, "version", current.Version)
+	return &InstallResult{Name: name, PreviousVersion: current.Version, RollbackPath: rollbackPath}, nil
+}
+
+// stagedPlugin is an unpacked, validated archive awaiting activation
+type stagedPlugin struct {
+	dir        string
+	root       string
+	meta       *pluginMetadata
+	validation *plugin.PluginValidationReport
+	integrity  IntegrityResult
+}
+
+func (s *stagedPlugin) cleanup() {
+	_ = os.RemoveAll(s.dir)
+}
+
+func (s *stagedPlugin) result() *InstallResult {
+	return &InstallResult{
+		Name:       s.meta.Name,
+		Version:    s.meta.Version,
+		Validation: s.validation,
+		Integrity:  s.integrity,
+	}
+}
+
+// stage verifies and unpacks an archive into a staging directory inside the
+// plugin directory, so activation is a rename on the same filesystem.
+// wantName, when set, must match the name declared in plugin.json.
+func (i *PluginInstaller) stage(ctx context.Context, src InstallSource, wantName string) (*stagedPlugin, error) {
+	archivePath, digest, err := i.resolve(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArchiveDigest(archivePath, digest); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(i.plugins.pluginDir, 0755); err != nil {
+		return nil, fmt.Errorf("create plugin directory: %w", err)
+	}
+	dir, err := os.MkdirTemp(i.plugins.pluginDir, stagingDirPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("create staging directory: %w", err)
+	}
+	staged := &stagedPlugin{dir: dir}
+
+	if err := extractArchive(archivePath, dir); err != nil {
+		staged.cleanup()
+		return nil, err
+	}
+
+	if staged.root, err = archiveRoot(dir); err != nil {
+		staged.cleanup()
+		return nil, err
+	}
+
+	if staged.meta, err = loadPluginMetadata(filepath.Join(staged.root, "plugin.json")); err != nil {
+		staged.cleanup()
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+
+	if wantName != "" && staged.meta.Name != wantName {
+		staged.cleanup()
+		return nil, fmt.Errorf("%w: archive contains plugin %q, expected %q", ErrInvalidInput, staged.meta.Name, wantName)
+	}
+
+	staged.validation, staged.integrity = i.plugins.validateStaged(staged.meta, staged.root)
+	if staged.validation.Passed {
+		if err := i.launchStaged(ctx, staged); err != nil {
+			staged.cleanup()
+			return nil, err
+		}
+	}
+	if !staged.validation.Passed {
+		staged.cleanup()
+		return nil, fmt.Errorf("%w: plugin %s failed validation: %s", ErrInvalidInput, staged.meta.Name, failedChecks(staged.validation))
+	}
+
+	return staged, nil
+}
+
+// launchStaged starts the staged plugin's binary and runs the basic
+// conformance suite against it, adding the results to its validation report.
+// A plugin that ships no binary, such as one reached only through
+// grpc_address, is not launched.
+func (i *PluginInstaller) launchStaged(ctx context.Context, staged *stagedPlugin) error {
+	if _, ok := staged.meta.localBinary(staged.root); !ok {
+		return nil
+	}
+
+	binary, err := resolvePluginBinary(staged.root)
+	if err != nil {
+		return err
+	}
+	report, err := i.spec.validateLaunch(ctx, binary, ConformanceBasic, nil)
+	if err != nil {
+		return fmt.Errorf("launch plugin %s: %w", staged.meta.Name, err)
+	}
+
+	staged.validation.TestResults = append(staged.validation.TestResults, report.TestResults...)
+	staged.validation.Passed = staged.validation.Passed && report.Passed
+	return nil
+}
+
+// resolve returns the archive path and expected digest for an install source
+func (i *PluginInstaller) resolve(src InstallSource) (string, string, error) {
+	if src.ArchivePath != "" {
+		return src.ArchivePath, src.SHA256, nil
+	}
+	if src.Name == "" {
+		return "", "", fmt.Errorf("%w: either an archive path or a plugin name is required", ErrInvalidInput)
+	}
+	if i.registryIndex == "" {
+		return "", "", fmt.Errorf("%w: no plugin registry index is configured", ErrInvalidInput)
+	}
+
+	data, err := os.ReadFile(i.registryIndex)
+	if err != nil {
+		return "", "", fmt.Errorf("read registry index: %w", err)
+	}
+	var index registryIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return "", "", fmt.Errorf("parse registry index: %w", err)
+	}
+
+	entry, ok := index.Plugins[src.Name]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s is not in the registry", ErrPluginNotFound, src.Name)
+	}
+	version := src.Version
+	if version == "" {
+		version = entry.Latest
+	}
+	release, ok := entry.Versions[version]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s version %q is not in the registry", ErrPluginNotFound, src.Name, version)
+	}
+	if release.SHA256 == "" {
+		return "", "", fmt.Errorf("%w: registry release %s@%s has no sha256", ErrInvalidInput, src.Name, version)
+	}
+
+	return resolvePath(filepath.Dir(i.registryIndex), release.Archive), release.SHA256, nil
+}
+
+// installedMetadata loads the metadata of an active plugin
+func (i *PluginInstaller) installedMetadata(name string) (*pluginMetadata, error) {
+	if !validPluginName(name) {
+		return nil, fmt.Errorf("%w: %s", ErrPluginNotFound, name)
+	}
+	meta, err := loadPluginMetadata(filepath.Join(i.plugins.pluginDir, name, "plugin.json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrPluginNotFound, name)
+		}
+		return nil, err
+	}
+	return meta, nil
+}
+
+// swap replaces the active plugin directory with replacement, moving the
+// current version to the rollback directory and restoring it on failure
+func (i *PluginInstaller) swap(name, replacement string) (string, error) {
+	rollbackPath, err := i.keepForRollback(name)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(replacement, filepath.Join(i.plugins.pluginDir, name)); err != nil {
+		if restoreErr := os.Rename(rollbackPath, filepath.Join(i.plugins.pluginDir, name)); restoreErr != nil {
+			i.logger.Error("failed to restore plugin after failed activation", "plugin", name, "error", restoreErr)
+		}
+		return "", fmt.Errorf("activate plugin %s: %w", name, err)
+	}
+
+	i.plugins.closeConnection(name)
+	i.plugins.resetCircuitBreaker(name)
+	return rollbackPath, nil
+}
+
+// keepForRollback moves the active plugin into the rollback directory,
+// replacing any older rollback copy
+func (i *PluginInstaller) keepForRollback(name string) (string, error) {
+	rollbackDir := filepath.Join(i.plugins.pluginDir, rollbackDirName)
+	if err := os.MkdirAll(rollbackDir, 0755); err != nil {
+		return "", fmt.Errorf("create rollback directory: %w", err)
+	}
+
+	rollbackPath := filepath.Join(rollbackDir, name)
+	if err := os.RemoveAll(rollbackPath); err != nil {
+		return "", fmt.Errorf("remove old rollback copy of %s: %w", name, err)
+	}
+	if err := os.Rename(filepath.Join(i.plugins.pluginDir, name), rollbackPath); err != nil {
+		return "", fmt.Errorf("keep %s for rollback: %w", name, err)
+	}
+	return rollbackPath, nil
+}
+
+// validateStaged checks an unpacked plugin before it is activated. These are
+// static checks of plugin.json and its referenced files; the plugin is not run.
+// launchStaged runs it once these pass.
+func (a *PluginAdapter) validateStaged(meta *pluginMetadata, dir string) (*plugin.PluginValidationReport, IntegrityResult) {
+	integrity := IntegrityResult{Status: IntegrityNotChecked, Reason: "integrity verification is off"}
+	if mode := a.options.Integrity.Mode; mode != "" && mode != IntegrityOff {
+		integrity = a.checkIntegrity(meta, dir)
+	}
+
+	checks := []struct {
+		name string
+		err  error
+	}{
+		{"metadata", validateMetadata(meta)},
+		{"grpc_address", func() error { _, err := dialTarget(meta.GRPCAddress, dir); return err }()},
+		{"tls", func() error { _, err := a.transportCredentials(meta, dir); return err }()},
+		{"integrity", a.enforceIntegrity(meta, integrity)},
+		{"configuration", func() error { _, err := a.resolveConfiguration(meta); return err }()},
+		{"spec_version", func() error {
+			if compat := a.checkCompatibility(meta); compat.Status == CompatibilityIncompatible {
+				return errors.New(compat.Reason)
+			}
+			return nil
+		}()},
+	}
+
+	timestamp := time.Now().Format(time.RFC3339)
+	report := &plugin.PluginValidationReport{
+		PluginName:       meta.Name,
+		ConformanceLevel: ConformanceBasic.String(),
+		Passed:           true,
+		Timestamp:        &timestamp,
+	}
+	for _, check := range checks {
+		test := &plugin.ValidationTest{Name: check.name, Passed: check.err == nil}
+		if check.err != nil {
+			msg := check.err.Error()
+			test.ErrorMessage = &msg
+			report.Passed = false
+		}
+		report.TestResults = append(report.TestResults, test)
+	}
+
+	return report, integrity
+}
+
+// validateMetadata checks the fields every plugin.json must declare
+func validateMetadata(meta *pluginMetadata) error {
+	switch {
+	case !validPluginName(meta.Name):
+		return fmt.Errorf("name %q must be a non-empty directory name", meta.Name)
+	case meta.Version == "":
+		return fmt.Errorf("version is required")
+	case !declaresCapability(meta.capabilities()):
+		return fmt.Errorf("plugin declares no capabilities")
+	case len(meta.providers()) == 0:
+		return fmt.Errorf("plugin declares no providers")
+	}
+	return nil
+}
+
+// validPluginName reports whether name can be used as a plugin directory
+func validPluginName(name string) bool {
+	return name != "" && !isReservedDir(name) && filepath.Base(name) == name && name != ".."
+}
+
+// isReservedDir reports whether a plugin directory entry holds installer state
+// (staging or rollback copies) rather than an active plugin
+func isReservedDir(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+// failedChecks summarizes the failed tests of a validation report
+func failedChecks(report *plugin.PluginValidationReport) string {
+	var failed []string
+	for _, test := range report.TestResults {
+		if !test.Passed && test.ErrorMessage != nil {
+			failed = append(failed, test.Name+": "+*test.ErrorMessage)
+		}
+	}
+	return strings.Join(failed, "; ")
+}
@@ -0,0 +1,319 @@
+package adapter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPluginInstaller_InstallArchive verifies local archives are validated and activated
+func TestPluginInstaller_InstallArchive(t *testing.T) {
+	for _, format := range []string{"tar.gz", "zip"} {
+		t.Run(format, func(t *testing.T) {
+			pluginDir := t.TempDir()
+			archive := writePluginArchive(t, t.TempDir(), format, "aws-cur", pluginFiles("aws-cur", "1.0.0"))
+
+			plugins := NewPluginAdapter(pluginDir, logging.Default())
+			installer := NewPluginInstaller(plugins, "", logging.Default())
+
+			result, err := installer.Install(context.Background(), InstallSource{ArchivePath: archive})
+			require.NoError(t, err)
+			assert.Equal(t, "aws-cur", result.Name)
+			assert.Equal(t, "1.0.0", result.Version)
+			require.NotNil(t, result.Validation)
+			assert.True(t, result.Validation.Passed)
+
+			discovered, err := plugins.DiscoverPlugins(context.Background())
+			require.NoError(t, err)
+			require.Len(t, discovered, 1)
+			assert.Equal(t, "aws-cur", discovered[0].Name)
+
+			info, err := os.Stat(filepath.Join(pluginDir, "aws-cur", "bin", "aws-cur"))
+			require.NoError(t, err)
+			assert.NotZero(t, info.Mode()&0100, "executable bit should be kept")
+
+			_, err = installer.Install(context.Background(), InstallSource{ArchivePath: archive})
+			assert.ErrorIs(t, err, ErrPluginExists)
+		})
+	}
+}
+
+// TestPluginInstaller_RejectsBadArchives verifies nothing is activated from unsafe or invalid archives
+func TestPluginInstaller_RejectsBadArchives(t *testing.T) {
+	archiveDir := t.TempDir()
+
+	noCapabilities := pluginFiles("aws-cur", "1.0.0")
+	noCapabilities["plugin.json"] = `{"name": "aws-cur", "version": "1.0.0", "providers": "aws", "grpc_address": "localhost:1"}`
+
+	traversal := pluginFiles("aws-cur", "1.0.0")
+	traversal["../../evil"] = "boom"
+
+	tests := []struct {
+		name    string
+		source  InstallSource
+		wantErr string
+	}{
+		{
+			name:    "path traversal",
+			source:  InstallSource{ArchivePath: writePluginArchive(t, archiveDir, "tar.gz", "traversal", traversal)},
+			wantErr: "escapes the plugin directory",
+		},
+		{
+			name:    "symlink",
+			source:  InstallSource{ArchivePath: writeSymlinkArchive(t, archiveDir)},
+			wantErr: "not a regular file or directory",
+		},
+		{
+			name: "digest mismatch",
+			source: InstallSource{
+				ArchivePath: writePluginArchive(t, archiveDir, "tar.gz", "digest", pluginFiles("aws-cur", "1.0.0")),
+				SHA256:      "sha256:0000",
+			},
+			wantErr: "does not match expected sha256",
+		},
+		{
+			name:    "failed validation",
+			source:  InstallSource{ArchivePath: writePluginArchive(t, archiveDir, "tar.gz", "invalid", noCapabilities)},
+			wantErr: "metadata: plugin declares no capabilities",
+		},
+		{
+			name:    "no plugin.json",
+			source:  InstallSource{ArchivePath: writePluginArchive(t, archiveDir, "zip", "empty", map[string]string{"README": "hi"})},
+			wantErr: "does not contain plugin.json",
+		},
+		{
+			name:    "unsupported format",
+			source:  InstallSource{ArchivePath: filepath.Join(archiveDir, "plugin.rar")},
+			wantErr: "unsupported archive format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pluginDir := t.TempDir()
+			installer := NewPluginInstaller(NewPluginAdapter(pluginDir, logging.Default()), "", logging.Default())
+
+			_, err := installer.Install(context.Background(), tt.source)
+			require.ErrorIs(t, err, ErrInvalidInput)
+			assert.ErrorContains(t, err, tt.wantErr)
+
+			entries, err := os.ReadDir(pluginDir)
+			require.NoError(t, err)
+			assert.Empty(t, entries, "staging directories should be cleaned up")
+		})
+	}
+}
+
+// TestPluginInstaller_UpgradeRollbackRemove verifies registry upgrades keep the previous version
+func TestPluginInstaller_UpgradeRollbackRemove(t *testing.T) {
+	pluginDir := t.TempDir()
+	registryDir := t.TempDir()
+	ctx := context.Background()
+
+	v1 := writePluginArchive(t, registryDir, "tar.gz", "aws-cur-1.0.0", pluginFiles("aws-cur", "1.0.0"))
+	v2 := writePluginArchive(t, registryDir, "tar.gz", "aws-cur-2.0.0", pluginFiles("aws-cur", "2.0.0"))
+	index := writeRegistryIndex(t, registryDir, "aws-cur", "2.0.0", map[string]string{"1.0.0": v1, "2.0.0": v2})
+
+	plugins := NewPluginAdapter(pluginDir, logging.Default())
+	installer := NewPluginInstaller(plugins, index, logging.Default())
+
+	result, err := installer.Install(ctx, InstallSource{Name: "aws-cur", Version: "1.0.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", result.Version)
+
+	result, err = installer.Upgrade(ctx, "aws-cur", InstallSource{})
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", result.Version)
+	assert.Equal(t, "1.0.0", result.PreviousVersion)
+	assert.Equal(t, filepath.Join(pluginDir, ".rollback", "aws-cur"), result.RollbackPath)
+	assert.Equal(t, "2.0.0", installedVersion(t, pluginDir, "aws-cur"))
+
+	result, err = installer.Rollback(ctx, "aws-cur")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", result.Version)
+	assert.Equal(t, "2.0.0", result.PreviousVersion)
+	assert.Equal(t, "1.0.0", installedVersion(t, pluginDir, "aws-cur"))
+
+	result, err = installer.Remove(ctx, "aws-cur")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", result.PreviousVersion)
+
+	discovered, err := plugins.DiscoverPlugins(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, discovered, "rollback copies must not be discovered")
+
+	_, err = installer.Upgrade(ctx, "aws-cur", InstallSource{})
+	assert.ErrorIs(t, err, ErrPluginNotFound)
+
+	_, err = installer.Rollback(ctx, "aws-cur")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", installedVersion(t, pluginDir, "aws-cur"))
+
+	_, err = installer.Install(ctx, InstallSource{Name: "aws-cur", Version: "9.9.9"})
+	assert.ErrorIs(t, err, ErrPluginNotFound)
+}
+
+// TestPluginInstaller_UpgradeNameMismatch verifies an upgrade cannot replace a plugin with a different one
+func TestPluginInstaller_UpgradeNameMismatch(t *testing.T) {
+	pluginDir := t.TempDir()
+	archiveDir := t.TempDir()
+	ctx := context.Background()
+
+	installer := NewPluginInstaller(NewPluginAdapter(pluginDir, logging.Default()), "", logging.Default())
+	_, err := installer.Install(ctx, InstallSource{ArchivePath: writePluginArchive(t, archiveDir, "tar.gz", "aws", pluginFiles("aws-cur", "1.0.0"))})
+	require.NoError(t, err)
+
+	_, err = installer.Upgrade(ctx, "aws-cur", InstallSource{ArchivePath: writePluginArchive(t, archiveDir, "tar.gz", "gcp", pluginFiles("gcp-billing", "1.0.0"))})
+	assert.ErrorContains(t, err, `archive contains plugin "gcp-billing", expected "aws-cur"`)
+	assert.Equal(t, "1.0.0", installedVersion(t, pluginDir, "aws-cur"))
+}
+
+// TestPluginInstaller_LaunchFailure verifies a plugin that does not start is
+// never activated and an upgrade to it keeps the current version
+func TestPluginInstaller_LaunchFailure(t *testing.T) {
+	pluginDir := t.TempDir()
+	archiveDir := t.TempDir()
+	ctx := context.Background()
+
+	broken := pluginFiles("aws-cur", "2.0.0")
+	broken["bin/aws-cur"] = "#!/bin/sh\necho 'missing API key' >&2\nexit 3\n"
+	brokenArchive := writePluginArchive(t, archiveDir, "tar.gz", "broken", broken)
+
+	installer := NewPluginInstaller(NewPluginAdapter(pluginDir, logging.Default()), "", logging.Default())
+
+	_, err := installer.Install(ctx, InstallSource{ArchivePath: brokenArchive})
+	require.ErrorIs(t, err, ErrInvalidInput)
+	assert.ErrorContains(t, err, "basic/startup: plugin exited: exit status 3: missing API key")
+	entries, err := os.ReadDir(pluginDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "nothing should be activated or left staged")
+
+	_, err = installer.Install(ctx, InstallSource{ArchivePath: writePluginArchive(t, archiveDir, "tar.gz", "working", pluginFiles("aws-cur", "1.0.0"))})
+	require.NoError(t, err)
+
+	_, err = installer.Upgrade(ctx, "aws-cur", InstallSource{ArchivePath: brokenArchive})
+	require.ErrorIs(t, err, ErrInvalidInput)
+	assert.Equal(t, "1.0.0", installedVersion(t, pluginDir, "aws-cur"))
+	_, err = os.Stat(filepath.Join(pluginDir, ".rollback", "aws-cur"))
+	assert.ErrorIs(t, err, os.ErrNotExist, "the current version should stay active")
+}
+
+// Helper functions
+
+// pluginFiles returns the files of a minimal conforming plugin, nested in a top-level directory
+func pluginFiles(name, version string) map[string]string {
+	return map[string]string{
+		"plugin.json": `{"name": "` + name + `", "version": "` + version + `", "providers": "aws",
+			"grpc_address": "localhost:50051", "capabilities": {"supports_actual_cost": true}}`,
+		"bin/" + name: fakePluginScript(name),
+	}
+}
+
+// fakePluginScript is a plugin binary that runs the test binary as a
+// conforming plugin named name
+func fakePluginScript(name string) string {
+	executable, err := os.Executable()
+	if err != nil {
+		panic(err)
+	}
+	return "#!/bin/sh\n" + fakePluginEnv + "=" + name + " exec '" + executable + "' \"$@\"\n"
+}
+
+// writePluginArchive writes files under a top-level directory into a .tar.gz or .zip archive
+func writePluginArchive(t *testing.T, dir, format, name string, files map[string]string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name+"."+format)
+	out, err := os.Create(path)
+	require.NoError(t, err)
+	defer out.Close()
+
+	switch format {
+	case "tar.gz":
+		gz := gzip.NewWriter(out)
+		tw := tar.NewWriter(gz)
+		for file, content := range files {
+			require.NoError(t, tw.WriteHeader(&tar.Header{
+				Name:     name + "/" + file,
+				Mode:     0755,
+				Size:     int64(len(content)),
+				Typeflag: tar.TypeReg,
+			}))
+			_, err := tw.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, tw.Close())
+		require.NoError(t, gz.Close())
+	case "zip":
+		zw := zip.NewWriter(out)
+		for file, content := range files {
+			header := &zip.FileHeader{Name: name + "/" + file, Method: zip.Deflate}
+			header.SetMode(0755)
+			w, err := zw.CreateHeader(header)
+			require.NoError(t, err)
+			_, err = w.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, zw.Close())
+	}
+
+	return path
+}
+
+// writeSymlinkArchive writes a tar.gz whose plugin binary is a symlink
+func writeSymlinkArchive(t *testing.T, dir string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "symlink.tar.gz")
+	out, err := os.Create(path)
+	require.NoError(t, err)
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "bin/aws-cur", Typeflag: tar.TypeSymlink, Linkname: "/bin/sh"}))
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	return path
+}
+
+// writeRegistryIndex writes a registry index for one plugin, referencing archives by base name
+func writeRegistryIndex(t *testing.T, dir, name, latest string, archives map[string]string) string {
+	t.Helper()
+
+	versions := make(map[string]map[string]string)
+	for version, archive := range archives {
+		digest, err := archiveSHA256(archive)
+		require.NoError(t, err)
+		versions[version] = map[string]string{"archive": filepath.Base(archive), "sha256": digest}
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"plugins": map[string]any{
+			name: map[string]any{"latest": latest, "versions": versions},
+		},
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "index.json")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	return path
+}
+
+func installedVersion(t *testing.T, pluginDir, name string) string {
+	t.Helper()
+
+	meta, err := loadPluginMetadata(filepath.Join(pluginDir, name, "plugin.json"))
+	require.NoError(t, err)
+	return meta.Version
+}
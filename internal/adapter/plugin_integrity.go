@@ -0,0 +1,307 @@
+package adapter
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// IntegrityMode controls what happens when a plugin fails verification
+type IntegrityMode string
+
+const (
+	// IntegrityOff skips verification entirely
+	IntegrityOff IntegrityMode = "off"
+	// IntegrityWarn verifies plugins and logs failures but still uses them
+	IntegrityWarn IntegrityMode = "warn"
+	// IntegrityEnforce refuses to discover or connect to unverified plugins
+	IntegrityEnforce IntegrityMode = "enforce"
+)
+
+// IntegrityStatus is the outcome of verifying a plugin
+type IntegrityStatus string
+
+const (
+	// IntegrityVerified means all checksums match and a trusted key signed them
+	IntegrityVerified IntegrityStatus = "verified"
+	// IntegrityUnsigned means the plugin declares no signature (or no checksums)
+	IntegrityUnsigned IntegrityStatus = "unsigned"
+	// IntegrityFailed means a checksum or signature did not verify
+	IntegrityFailed IntegrityStatus = "failed"
+	// IntegrityNotChecked means verification is turned off
+	IntegrityNotChecked IntegrityStatus = "not_checked"
+)
+
+// IntegrityOptions configures plugin verification
+type IntegrityOptions struct {
+	Mode IntegrityMode
+	// TrustedKeys maps key IDs to the ed25519 keys allowed to sign plugins
+	TrustedKeys map[string]ed25519.PublicKey
+}
+
+// IntegrityResult describes how a plugin fared during verification
+type IntegrityResult struct {
+	Status IntegrityStatus
+	// KeyID names the trusted key that signed the plugin, if any
+	KeyID string
+	// Reason explains any status other than verified
+	Reason string
+}
+
+// pluginSignature is the plugin.json "signature" section
+type pluginSignature struct {
+	KeyID     string `json:"key_id"`
+	Algorithm string `json:"algorithm"`
+	// Value is the base64 signature over IntegrityManifest
+	Value string `json:"value"`
+}
+
+// integrityCacheEntry avoids re-verifying the signature of a plugin whose
+// files have not changed
+type integrityCacheEntry struct {
+	fingerprint string
+	result      IntegrityResult
+}
+
+// integrityCache memoizes verification results per plugin directory
+type integrityCache struct {
+	mu      sync.Mutex
+	entries map[string]integrityCacheEntry
+}
+
+// ParseTrustedKeys decodes base64 ed25519 public keys keyed by key ID
+func ParseTrustedKeys(encoded map[string]string) (map[string]ed25519.PublicKey, error) {
+	keys := make(map[string]ed25519.PublicKey, len(encoded))
+	for id, value := range encoded {
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("trusted key %s: decode base64: %w", id, err)
+		}
+		if len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("trusted key %s: expected %d-byte ed25519 public key, got %d bytes", id, ed25519.PublicKeySize, len(raw))
+		}
+		keys[id] = ed25519.PublicKey(raw)
+	}
+	return keys, nil
+}
+
+// IntegrityManifest returns the bytes a plugin signature covers: the
+// plugin.json document without its "signature" section, re-encoded as compact
+// JSON with object keys sorted. The signature thereby covers every field,
+// including checksums, binary, grpc_address and tls. Plugin publishers sign
+// this with their ed25519 key.
+func IntegrityManifest(pluginJSON []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(pluginJSON))
+	decoder.UseNumber()
+	var document map[string]any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("parse plugin metadata: %w", err)
+	}
+	delete(document, "signature")
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(document); err != nil {
+		return nil, fmt.Errorf("encode plugin metadata: %w", err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// admitPlugin applies the integrity mode to a plugin, returning an error only
+// when the plugin must not be used
+func (a *PluginAdapter) admitPlugin(meta *pluginMetadata, pluginPath string) error {
+	mode := a.options.Integrity.Mode
+	if mode == "" || mode == IntegrityOff {
+		return nil
+	}
+	return a.enforceIntegrity(meta, a.verifyIntegrity(meta, pluginPath))
+}
+
+// enforceIntegrity rejects an unverified plugin in enforce mode and logs it in warn mode
+func (a *PluginAdapter) enforceIntegrity(meta *pluginMetadata, result IntegrityResult) error {
+	if result.Status == IntegrityVerified || result.Status == IntegrityNotChecked {
+		return nil
+	}
+
+	if a.options.Integrity.Mode == IntegrityEnforce {
+		return fmt.Errorf("plugin %s failed integrity verification (%s): %s", meta.Name, result.Status, result.Reason)
+	}
+
+	a.logger.Warn("plugin failed integrity verification", "plugin", meta.Name, "status", string(result.Status), "reason", result.Reason)
+	return nil
+}
+
+// verifyIntegrity checks a plugin's checksums and signature, reusing the
+// previous result when the contents of the plugin's files have not changed
+func (a *PluginAdapter) verifyIntegrity(meta *pluginMetadata, pluginPath string) IntegrityResult {
+	mode := a.options.Integrity.Mode
+	if mode == "" || mode == IntegrityOff {
+		return IntegrityResult{Status: IntegrityNotChecked, Reason: "integrity verification is off"}
+	}
+
+	fingerprint := integrityFingerprint(meta, pluginPath)
+
+	a.integrity.mu.Lock()
+	cached, ok := a.integrity.entries[pluginPath]
+	a.integrity.mu.Unlock()
+	if ok && cached.fingerprint == fingerprint {
+		return cached.result
+	}
+
+	result := a.checkIntegrity(meta, pluginPath)
+
+	a.integrity.mu.Lock()
+	if a.integrity.entries == nil {
+		a.integrity.entries = make(map[string]integrityCacheEntry)
+	}
+	a.integrity.entries[pluginPath] = integrityCacheEntry{fingerprint: fingerprint, result: result}
+	a.integrity.mu.Unlock()
+
+	return result
+}
+
+// checkIntegrity performs the actual checksum and signature verification
+func (a *PluginAdapter) checkIntegrity(meta *pluginMetadata, pluginPath string) IntegrityResult {
+	if len(meta.Checksums) == 0 {
+		return IntegrityResult{Status: IntegrityUnsigned, Reason: "plugin.json declares no checksums"}
+	}
+
+	for path, expected := range meta.Checksums {
+		if err := verifyChecksum(pluginPath, path, expected); err != nil {
+			return IntegrityResult{Status: IntegrityFailed, Reason: err.Error()}
+		}
+	}
+
+	if binary, ok := meta.localBinary(pluginPath); ok && !meta.checksummed(binary) {
+		return IntegrityResult{Status: IntegrityFailed, Reason: fmt.Sprintf("plugin binary %s is not covered by checksums", binary)}
+	}
+
+	if meta.Signature == nil {
+		return IntegrityResult{Status: IntegrityUnsigned, Reason: "checksums match but plugin.json has no signature"}
+	}
+
+	sig := meta.Signature
+	if sig.Algorithm != "" && sig.Algorithm != "ed25519" {
+		return IntegrityResult{Status: IntegrityFailed, KeyID: sig.KeyID, Reason: fmt.Sprintf("unsupported signature algorithm %q", sig.Algorithm)}
+	}
+
+	key, trusted := a.options.Integrity.TrustedKeys[sig.KeyID]
+	if !trusted {
+		return IntegrityResult{Status: IntegrityFailed, KeyID: sig.KeyID, Reason: fmt.Sprintf("signing key %q is not trusted", sig.KeyID)}
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil {
+		return IntegrityResult{Status: IntegrityFailed, KeyID: sig.KeyID, Reason: fmt.Sprintf("decode signature: %v", err)}
+	}
+
+	manifest, err := IntegrityManifest(meta.raw)
+	if err != nil {
+		return IntegrityResult{Status: IntegrityFailed, KeyID: sig.KeyID, Reason: err.Error()}
+	}
+	if !ed25519.Verify(key, manifest, signature) {
+		return IntegrityResult{Status: IntegrityFailed, KeyID: sig.KeyID, Reason: "signature does not match plugin.json"}
+	}
+
+	return IntegrityResult{Status: IntegrityVerified, KeyID: sig.KeyID}
+}
+
+// verifyChecksum compares a file's sha256 digest with a "sha256:<hex>" value
+func verifyChecksum(pluginPath, relPath, expected string) error {
+	cleaned := filepath.Clean(relPath)
+	if filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("checksum path %q must stay inside the plugin directory", relPath)
+	}
+
+	algorithm, digest, found := strings.Cut(expected, ":")
+	if !found || algorithm != "sha256" {
+		return fmt.Errorf("checksum for %s must be of the form sha256:<hex>", relPath)
+	}
+
+	actual, err := fileDigest(filepath.Join(pluginPath, cleaned))
+	if err != nil {
+		return fmt.Errorf("checksum %s: %w", relPath, err)
+	}
+	if !strings.EqualFold(actual, digest) {
+		return fmt.Errorf("checksum mismatch for %s", relPath)
+	}
+	return nil
+}
+
+// fileDigest returns the hex sha256 digest of a file's contents
+func fileDigest(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// integrityFingerprint summarizes the plugin.json contents and the digest of
+// every checksummed file. Files are rehashed on every call: metadata such as
+// size and modification time can be restored after swapping a file's
+// contents, so only the contents themselves can key a cached result.
+func integrityFingerprint(meta *pluginMetadata, pluginPath string) string {
+	var b strings.Builder
+	b.Write(meta.raw)
+	b.WriteString("\n")
+
+	paths := make([]string, 0, len(meta.Checksums))
+	for path := range meta.Checksums {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		digest, err := fileDigest(filepath.Join(pluginPath, filepath.Clean(path)))
+		if err != nil {
+			fmt.Fprintf(&b, "missing %s\n", path)
+			continue
+		}
+		fmt.Fprintf(&b, "%s %s\n", path, digest)
+	}
+	return b.String()
+}
+
+// localBinary returns the plugin executable relative to the plugin directory:
+// the "binary" field, bin/<name> or <name>. ok is false when the plugin ships
+// no executable, such as a plugin reached only through grpc_address.
+func (m *pluginMetadata) localBinary(pluginPath string) (binary string, ok bool) {
+	for _, candidate := range m.binaryCandidates() {
+		if _, err := os.Stat(filepath.Join(pluginPath, filepath.Clean(candidate))); err == nil {
+			return filepath.Clean(candidate), true
+		}
+	}
+	if m.Binary != "" {
+		// A declared binary must be checksummed even before it is installed
+		return filepath.Clean(m.Binary), true
+	}
+	return "", false
+}
+
+// checksummed reports whether a relative path has a checksum
+func (m *pluginMetadata) checksummed(relPath string) bool {
+	for path := range m.Checksums {
+		if filepath.Clean(path) == relPath {
+			return true
+		}
+	}
+	return false
+}
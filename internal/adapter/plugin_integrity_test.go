@@ -0,0 +1,270 @@
+package adapter
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rshade/pulumicost-mcp/gen/plugin"
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVerifyIntegrity verifies checksum and signature outcomes
+func TestVerifyIntegrity(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, otherPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	writeSignedPlugin(t, tmpDir, "signed", "release", priv, false)
+	writeSignedPlugin(t, tmpDir, "tampered", "release", priv, true)
+	writeSignedPlugin(t, tmpDir, "untrusted", "someone-else", otherPriv, false)
+	writeSignedPlugin(t, tmpDir, "forged", "release", otherPriv, false)
+	writePluginJSON(t, tmpDir, "unsigned", `{"name": "unsigned", "version": "1.0.0"}`)
+
+	// Redirecting a signed plugin to another address breaks its signature
+	writeSignedPlugin(t, tmpDir, "redirected", "release", priv, false)
+	redirected := filepath.Join(tmpDir, "redirected", "plugin.json")
+	data, err := os.ReadFile(redirected)
+	require.NoError(t, err)
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(data, &document))
+	document["grpc_address"] = "attacker.example.com:443"
+	data, err = json.Marshal(document)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(redirected, data, 0644))
+
+	// A signed plugin whose checksums leave out its binary
+	uncovered := filepath.Join(tmpDir, "uncovered")
+	require.NoError(t, os.MkdirAll(filepath.Join(uncovered, "bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(uncovered, "bin", "uncovered"), []byte("#!/bin/sh\n"), 0755))
+	readme := []byte("readme")
+	digest := sha256.Sum256(readme)
+	require.NoError(t, os.WriteFile(filepath.Join(uncovered, "README"), readme, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(uncovered, "plugin.json"), signPluginJSON(t, "release", priv, map[string]any{
+		"name":      "uncovered",
+		"version":   "1.0.0",
+		"checksums": map[string]string{"README": "sha256:" + hex.EncodeToString(digest[:])},
+	}), 0644))
+
+	adapter := NewPluginAdapterWithOptions(tmpDir, PluginAdapterOptions{
+		Integrity: IntegrityOptions{
+			Mode:        IntegrityWarn,
+			TrustedKeys: map[string]ed25519.PublicKey{"release": pub},
+		},
+	}, logging.Default())
+
+	tests := []struct {
+		plugin string
+		status IntegrityStatus
+		reason string
+	}{
+		{plugin: "signed", status: IntegrityVerified},
+		{plugin: "tampered", status: IntegrityFailed, reason: "checksum mismatch"},
+		{plugin: "untrusted", status: IntegrityFailed, reason: "not trusted"},
+		{plugin: "forged", status: IntegrityFailed, reason: "signature does not match"},
+		{plugin: "unsigned", status: IntegrityUnsigned},
+		{plugin: "redirected", status: IntegrityFailed, reason: "signature does not match plugin.json"},
+		{plugin: "uncovered", status: IntegrityFailed, reason: "plugin binary bin/uncovered is not covered by checksums"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.plugin, func(t *testing.T) {
+			details, err := adapter.DescribePlugin(context.Background(), tt.plugin)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, details.Integrity.Status)
+			assert.Contains(t, details.Integrity.Reason, tt.reason)
+		})
+	}
+
+	// Warn mode still discovers every plugin
+	plugins, err := adapter.DiscoverPlugins(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, plugins, len(tests))
+}
+
+// TestDiscoverPlugins_IntegrityEnforce verifies enforce mode excludes unverified plugins
+func TestDiscoverPlugins_IntegrityEnforce(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	writeSignedPlugin(t, tmpDir, "signed", "release", priv, false)
+	writeSignedPlugin(t, tmpDir, "tampered", "release", priv, true)
+	writePluginJSON(t, tmpDir, "unsigned", `{"name": "unsigned", "version": "1.0.0", "grpc_address": "localhost:1"}`)
+
+	adapter := NewPluginAdapterWithOptions(tmpDir, PluginAdapterOptions{
+		Integrity: IntegrityOptions{
+			Mode:        IntegrityEnforce,
+			TrustedKeys: map[string]ed25519.PublicKey{"release": pub},
+		},
+	}, logging.Default())
+	defer adapter.Close()
+
+	ctx := context.Background()
+	plugins, err := adapter.DiscoverPlugins(ctx)
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	assert.Equal(t, "signed", plugins[0].Name)
+
+	require.NoError(t, adapter.EstablishConnection(ctx, &plugin.Plugin{Name: "signed"}))
+	err = adapter.EstablishConnection(ctx, &plugin.Plugin{Name: "unsigned"})
+	assert.ErrorContains(t, err, "failed integrity verification")
+}
+
+// TestVerifyIntegrity_SwappedBinary verifies a cached pass is not reused after
+// the binary is replaced with same-size contents and its mtime is restored
+func TestVerifyIntegrity_SwappedBinary(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	writeSignedPlugin(t, tmpDir, "signed", "release", priv, false)
+
+	adapter := NewPluginAdapterWithOptions(tmpDir, PluginAdapterOptions{
+		Integrity: IntegrityOptions{
+			Mode:        IntegrityEnforce,
+			TrustedKeys: map[string]ed25519.PublicKey{"release": pub},
+		},
+	}, logging.Default())
+	defer adapter.Close()
+
+	ctx := context.Background()
+	details, err := adapter.DescribePlugin(ctx, "signed")
+	require.NoError(t, err)
+	require.Equal(t, IntegrityVerified, details.Integrity.Status)
+
+	binary := filepath.Join(tmpDir, "signed", "bin", "signed")
+	info, err := os.Stat(binary)
+	require.NoError(t, err)
+	original, err := os.ReadFile(binary)
+	require.NoError(t, err)
+
+	swapped := []byte("#!/bin/sh\nexit 1\n")
+	swapped = append(swapped, make([]byte, len(original)-len(swapped))...)
+	require.Len(t, swapped, len(original))
+	require.NoError(t, os.WriteFile(binary, swapped, 0755))
+	require.NoError(t, os.Chtimes(binary, info.ModTime(), info.ModTime()))
+
+	details, err = adapter.DescribePlugin(ctx, "signed")
+	require.NoError(t, err)
+	assert.Equal(t, IntegrityFailed, details.Integrity.Status)
+	assert.Contains(t, details.Integrity.Reason, "checksum mismatch")
+
+	_, err = adapter.DiscoverPlugins(ctx)
+	require.NoError(t, err)
+	err = adapter.EstablishConnection(ctx, &plugin.Plugin{Name: "signed"})
+	assert.ErrorContains(t, err, "failed integrity verification")
+}
+
+// TestIntegrityManifest verifies the manifest ignores formatting, key order and the signature
+func TestIntegrityManifest(t *testing.T) {
+	manifest, err := IntegrityManifest([]byte(`{
+		"version": "1.0.0",
+		"name": "aws-cur",
+		"tls": {"server_name": "a<b"},
+		"port": 8080,
+		"signature": {"key_id": "release", "value": "c2ln"}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"aws-cur","port":8080,"tls":{"server_name":"a<b"},"version":"1.0.0"}`, string(manifest))
+
+	_, err = IntegrityManifest([]byte(`["not", "an", "object"]`))
+	assert.Error(t, err)
+}
+
+// TestVerifyIntegrity_Off verifies nothing is checked when verification is off
+func TestVerifyIntegrity_Off(t *testing.T) {
+	tmpDir := t.TempDir()
+	writePluginJSON(t, tmpDir, "unsigned", `{"name": "unsigned", "version": "1.0.0"}`)
+
+	details, err := NewPluginAdapter(tmpDir, logging.Default()).DescribePlugin(context.Background(), "unsigned")
+	require.NoError(t, err)
+	assert.Equal(t, IntegrityNotChecked, details.Integrity.Status)
+}
+
+// TestVerifyChecksum_PathEscape verifies checksums cannot reference files outside the plugin
+func TestVerifyChecksum_PathEscape(t *testing.T) {
+	err := verifyChecksum(t.TempDir(), "../other/plugin-binary", "sha256:00")
+	assert.ErrorContains(t, err, "must stay inside the plugin directory")
+}
+
+// TestDescribePlugin_NotFound verifies unknown or unsafe names are reported as not found
+func TestDescribePlugin_NotFound(t *testing.T) {
+	adapter := NewPluginAdapter(t.TempDir(), logging.Default())
+
+	for _, name := range []string{"missing", "../missing"} {
+		_, err := adapter.DescribePlugin(context.Background(), name)
+		assert.ErrorIs(t, err, ErrPluginNotFound)
+	}
+}
+
+// TestParseTrustedKeys verifies trusted keys must be base64 ed25519 public keys
+func TestParseTrustedKeys(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keys, err := ParseTrustedKeys(map[string]string{"release": base64.StdEncoding.EncodeToString(pub)})
+	require.NoError(t, err)
+	assert.Equal(t, pub, keys["release"])
+
+	_, err = ParseTrustedKeys(map[string]string{"bad": "not base64!"})
+	assert.ErrorContains(t, err, "trusted key bad")
+
+	_, err = ParseTrustedKeys(map[string]string{"short": base64.StdEncoding.EncodeToString([]byte("short"))})
+	assert.ErrorContains(t, err, "ed25519 public key")
+}
+
+// writeSignedPlugin creates a plugin with a checksummed binary signed by key.
+// When tamper is set the binary is modified after signing.
+func writeSignedPlugin(t *testing.T, baseDir, name, keyID string, key ed25519.PrivateKey, tamper bool) {
+	t.Helper()
+
+	pluginDir := filepath.Join(baseDir, name)
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "bin"), 0755))
+
+	binary := []byte("#!/bin/sh\necho " + name + "\n")
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "bin", name), binary, 0755))
+
+	digest := sha256.Sum256(binary)
+	metadata := signPluginJSON(t, keyID, key, map[string]any{
+		"name":         name,
+		"version":      "1.0.0",
+		"grpc_address": "localhost:1",
+		"checksums":    map[string]string{"bin/" + name: "sha256:" + hex.EncodeToString(digest[:])},
+	})
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "plugin.json"), metadata, 0644))
+
+	if tamper {
+		require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "bin", name), append(binary, '#'), 0755))
+	}
+}
+
+// signPluginJSON encodes a plugin.json document with a signature by key over its manifest
+func signPluginJSON(t *testing.T, keyID string, key ed25519.PrivateKey, document map[string]any) []byte {
+	t.Helper()
+
+	unsigned, err := json.MarshalIndent(document, "", "  ")
+	require.NoError(t, err)
+	manifest, err := IntegrityManifest(unsigned)
+	require.NoError(t, err)
+
+	document["signature"] = map[string]string{
+		"key_id":    keyID,
+		"algorithm": "ed25519",
+		"value":     base64.StdEncoding.EncodeToString(ed25519.Sign(key, manifest)),
+	}
+	signed, err := json.MarshalIndent(document, "", "  ")
+	require.NoError(t, err)
+	return signed
+}
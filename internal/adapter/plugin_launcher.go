@@ -0,0 +1,249 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Plugin process limits
+const (
+	pluginStopTimeout = 5 * time.Second
+	// pluginOutputLimit is how much of a launched plugin's output is kept for error reports
+	pluginOutputLimit = 4096
+)
+
+// pluginPortEnv tells a launched plugin which port to listen on, in addition to --port
+const pluginPortEnv = "PULUMICOST_PLUGIN_PORT"
+
+// ErrLaunchForbidden is returned when server configuration does not allow
+// launching the plugin at a path
+var ErrLaunchForbidden = errors.New("plugin launch not allowed")
+
+// LaunchOptions controls which plugins the server launches by path for
+// validation. Installed plugins are validated over their connections either way.
+type LaunchOptions struct {
+	// Allow enables launching plugin binaries by path
+	Allow bool
+	// Roots are directories besides the plugin directory whose plugins may be launched
+	Roots []string
+}
+
+// withinRoots reports whether path, with symlinks resolved, lies inside one of roots
+func withinRoots(path string, roots []string) bool {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	if resolved, err = filepath.Abs(resolved); err != nil {
+		return false
+	}
+	for _, root := range roots {
+		root, err := filepath.EvalSymlinks(root)
+		if err != nil {
+			continue
+		}
+		if root, err = filepath.Abs(root); err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(root, resolved); err == nil && filepath.IsLocal(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// pluginBinary is a plugin executable and, if present, its plugin.json
+type pluginBinary struct {
+	path string
+	// meta is nil for a bare binary without plugin.json
+	meta *pluginMetadata
+	// dir is the directory holding plugin.json, if any
+	dir string
+}
+
+// resolvePluginBinary finds the executable for a plugin directory or binary
+// path. A directory must contain plugin.json; its "binary" field, bin/<name>
+// or <name> names the executable. A bare binary may have plugin.json beside it.
+func resolvePluginBinary(path string) (*pluginBinary, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: plugin path %s: %w", ErrInvalidInput, path, err)
+	}
+
+	if !info.IsDir() {
+		binary := &pluginBinary{path: path}
+		if meta, err := loadPluginMetadata(filepath.Join(filepath.Dir(path), "plugin.json")); err == nil {
+			binary.meta = meta
+			binary.dir = filepath.Dir(path)
+		}
+		return binary, checkExecutable(path, info)
+	}
+
+	meta, err := loadPluginMetadata(filepath.Join(path, "plugin.json"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: plugin directory %s: %w", ErrInvalidInput, path, err)
+	}
+
+	candidates := meta.binaryCandidates()
+	for _, candidate := range candidates {
+		if filepath.IsAbs(candidate) || !filepath.IsLocal(candidate) {
+			return nil, fmt.Errorf("%w: plugin binary %s escapes the plugin directory", ErrInvalidInput, candidate)
+		}
+		binaryPath := filepath.Join(path, candidate)
+		info, err := os.Stat(binaryPath)
+		if err != nil {
+			continue
+		}
+		return &pluginBinary{path: binaryPath, meta: meta, dir: path}, checkExecutable(binaryPath, info)
+	}
+
+	return nil, fmt.Errorf("%w: no plugin binary found in %s (tried %s)", ErrInvalidInput, path, strings.Join(candidates, ", "))
+}
+
+func checkExecutable(path string, info os.FileInfo) error {
+	if !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+		return fmt.Errorf("%w: plugin binary %s is not an executable file", ErrInvalidInput, path)
+	}
+	return nil
+}
+
+// launchedPlugin is a plugin process started in its own temporary working directory
+type launchedPlugin struct {
+	cmd     *exec.Cmd
+	workDir string
+	address string
+	output  *tailBuffer
+	done    chan struct{}
+	waitErr error
+}
+
+// launchPlugin starts a plugin binary listening on a fresh loopback port. The
+// process runs in an empty temporary directory that also serves as TMPDIR.
+func launchPlugin(binary string) (*launchedPlugin, error) {
+	port, err := freePort()
+	if err != nil {
+		return nil, fmt.Errorf("allocate plugin port: %w", err)
+	}
+
+	workDir, err := os.MkdirTemp("", "pulumicost-plugin-*")
+	if err != nil {
+		return nil, fmt.Errorf("create plugin working directory: %w", err)
+	}
+
+	absBinary, err := filepath.Abs(binary)
+	if err != nil {
+		_ = os.RemoveAll(workDir)
+		return nil, fmt.Errorf("resolve plugin binary: %w", err)
+	}
+
+	output := &tailBuffer{limit: pluginOutputLimit}
+	cmd := exec.Command(absBinary, "--port="+strconv.Itoa(port))
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), pluginPortEnv+"="+strconv.Itoa(port), "TMPDIR="+workDir)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	// Do not wait forever for output from processes the plugin left behind
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Start(); err != nil {
+		_ = os.RemoveAll(workDir)
+		return nil, fmt.Errorf("start plugin %s: %w", binary, err)
+	}
+
+	l := &launchedPlugin{
+		cmd:     cmd,
+		workDir: workDir,
+		address: net.JoinHostPort("127.0.0.1", strconv.Itoa(port)),
+		output:  output,
+		done:    make(chan struct{}),
+	}
+	go func() {
+		l.waitErr = cmd.Wait()
+		close(l.done)
+	}()
+
+	return l, nil
+}
+
+// exited reports why the process stopped, or nil while it is running
+func (l *launchedPlugin) exited() error {
+	select {
+	case <-l.done:
+		reason := "plugin exited"
+		if l.waitErr != nil {
+			reason = fmt.Sprintf("plugin exited: %v", l.waitErr)
+		}
+		if out := strings.TrimSpace(l.output.String()); out != "" {
+			reason += ": " + out
+		}
+		return errors.New(reason)
+	default:
+		return nil
+	}
+}
+
+// stop asks the plugin to shut down, killing it if it does not exit within
+// pluginStopTimeout, and removes its working directory. It returns an error
+// if the plugin had to be killed.
+func (l *launchedPlugin) stop() error {
+	defer os.RemoveAll(l.workDir)
+
+	select {
+	case <-l.done:
+		return nil
+	default:
+	}
+
+	_ = l.cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-l.done:
+		return nil
+	case <-time.After(pluginStopTimeout):
+		_ = l.cmd.Process.Kill()
+		<-l.done
+		return fmt.Errorf("plugin did not exit within %s of SIGTERM and was killed", pluginStopTimeout)
+	}
+}
+
+// freePort returns a loopback TCP port that is currently unused
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// tailBuffer keeps the last limit bytes written to it
+type tailBuffer struct {
+	mu    sync.Mutex
+	limit int
+	data  []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.data = append(b.data, p...)
+	if over := len(b.data) - b.limit; over > 0 {
+		b.data = b.data[over:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.data)
+}
@@ -0,0 +1,158 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/rshade/pulumicost-mcp/gen/plugin"
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePluginEnv makes the test binary act as a plugin named by its value when
+// launched by the validator
+const fakePluginEnv = "PULUMICOST_TEST_FAKE_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if name := os.Getenv(fakePluginEnv); name != "" {
+		serveFakePlugin(name)
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// serveFakePlugin serves a conforming fakeCostSource on the port the launcher chose
+func serveFakePlugin(name string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:"+os.Getenv(pluginPortEnv))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	server := newFakeServer(&fakeCostSource{name: name})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM)
+	go func() {
+		<-signals
+		server.GracefulStop()
+	}()
+	_ = server.Serve(listener)
+}
+
+// TestValidatePath_Directory verifies a plugin directory is launched, tested and stopped
+func TestValidatePath_Directory(t *testing.T) {
+	t.Setenv(fakePluginEnv, "aws-fake")
+	pluginDir := filepath.Join(t.TempDir(), "aws-fake")
+	writePluginJSON(t, filepath.Dir(pluginDir), "aws-fake", `{"name": "aws-fake", "version": "1.0.0", "providers": "aws",
+		"capabilities": {"supports_projected_cost": true, "supports_actual_cost": true}}`)
+	linkTestBinary(t, filepath.Join(pluginDir, "bin", "aws-fake"))
+
+	specAdapter := NewSpecAdapter(logging.Default())
+	report, err := specAdapter.ValidatePath(context.Background(), pluginDir, ConformanceStandard, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "aws-fake", report.PluginName)
+	assert.True(t, report.Passed, failedMessages(report))
+	last := report.TestResults[len(report.TestResults)-1]
+	assert.Equal(t, "basic/shutdown", last.Name)
+	assert.True(t, last.Passed)
+}
+
+// TestValidatePath_BareBinary verifies a binary without plugin.json is described from its answers
+func TestValidatePath_BareBinary(t *testing.T) {
+	t.Setenv(fakePluginEnv, "aws-fake")
+	binary := filepath.Join(t.TempDir(), "my-plugin")
+	linkTestBinary(t, binary)
+
+	specAdapter := NewSpecAdapter(logging.Default())
+	report, err := specAdapter.ValidatePath(context.Background(), binary, ConformanceStandard, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "aws-fake", report.PluginName, "name should come from the Name RPC")
+	assert.True(t, report.Passed, failedMessages(report))
+}
+
+// TestValidatePath_CrashingPlugin verifies a plugin that exits at startup is reported with its output
+func TestValidatePath_CrashingPlugin(t *testing.T) {
+	pluginDir := filepath.Join(t.TempDir(), "broken")
+	writePluginJSON(t, filepath.Dir(pluginDir), "broken", `{"name": "broken", "version": "1.0.0", "providers": "aws",
+		"binary": "run.sh", "capabilities": {"supports_projected_cost": true}}`)
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "run.sh"), []byte("#!/bin/sh\necho 'missing API key' >&2\nexit 3\n"), 0755))
+
+	specAdapter := NewSpecAdapter(logging.Default())
+	report, err := specAdapter.ValidatePath(context.Background(), pluginDir, ConformanceFull, nil)
+	require.NoError(t, err)
+
+	assert.False(t, report.Passed)
+	require.Len(t, report.TestResults, 1, "no shutdown result for a plugin that already exited")
+	assert.Equal(t, "basic/startup", report.TestResults[0].Name)
+	assert.Equal(t, "plugin exited: exit status 3: missing API key", *report.TestResults[0].ErrorMessage)
+}
+
+// TestValidatePath_InvalidPaths verifies unusable paths are rejected before anything is launched
+func TestValidatePath_InvalidPaths(t *testing.T) {
+	base := t.TempDir()
+
+	writePluginJSON(t, base, "no-binary", `{"name": "no-binary", "version": "1.0.0"}`)
+	writePluginJSON(t, base, "escapes", `{"name": "escapes", "version": "1.0.0", "binary": "../other/bin"}`)
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "no-metadata"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "not-executable"), []byte("#!/bin/sh\n"), 0644))
+
+	tests := map[string]string{
+		"missing":        "no such file or directory",
+		"no-metadata":    "read plugin metadata",
+		"no-binary":      "no plugin binary found",
+		"escapes":        "escapes the plugin directory",
+		"not-executable": "is not an executable file",
+	}
+
+	specAdapter := NewSpecAdapter(logging.Default())
+	for name, wantErr := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := specAdapter.ValidatePath(context.Background(), filepath.Join(base, name), ConformanceBasic, nil)
+			require.ErrorIs(t, err, ErrInvalidInput)
+			assert.ErrorContains(t, err, wantErr)
+		})
+	}
+}
+
+// TestWithinRoots verifies symlinks cannot lead a path out of the allowed roots
+func TestWithinRoots(t *testing.T) {
+	root, outside := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "plugin"), nil, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "plugin"), nil, 0755))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "plugin"), filepath.Join(root, "link")))
+
+	assert.True(t, withinRoots(filepath.Join(root, "plugin"), []string{root}))
+	assert.False(t, withinRoots(filepath.Join(outside, "plugin"), []string{root}))
+	assert.False(t, withinRoots(filepath.Join(root, "link"), []string{root}))
+	assert.False(t, withinRoots(filepath.Join(root, "missing"), []string{root}))
+}
+
+// linkTestBinary links the running test binary to path so it can be launched as a plugin
+func linkTestBinary(t *testing.T, path string) {
+	t.Helper()
+
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.Symlink(executable, path))
+}
+
+// failedMessages describes failed tests for assertion messages
+func failedMessages(report *plugin.PluginValidationReport) string {
+	var msg string
+	for _, test := range report.TestResults {
+		if !test.Passed && test.ErrorMessage != nil {
+			msg += test.Name + ": " + *test.ErrorMessage + "\n"
+		}
+	}
+	return msg
+}
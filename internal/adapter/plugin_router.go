@@ -0,0 +1,233 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rshade/pulumicost-mcp/gen/plugin"
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+)
+
+// CostKind identifies which plugin capability a cost request needs
+type CostKind string
+
+const (
+	// CostKindProjected requires SupportsProjected
+	CostKindProjected CostKind = "projected"
+	// CostKindActual requires SupportsActual
+	CostKindActual CostKind = "actual"
+	// CostKindOptimization requires SupportsOptimization
+	CostKindOptimization CostKind = "optimization"
+	// CostKindForecast requires SupportsForecast
+	CostKindForecast CostKind = "forecast"
+)
+
+// ErrNoPluginAvailable is returned when no installed plugin can serve a request
+var ErrNoPluginAvailable = errors.New("no plugin available")
+
+// PluginRouter selects cost source plugins per request from their declared
+// providers and capabilities, falling back along a configured priority order
+type PluginRouter struct {
+	plugins  *PluginAdapter
+	priority map[string]int
+	logger   *logging.Logger
+}
+
+// NewPluginRouter creates a router. Plugins named in priority are tried first,
+// in that order; any others follow alphabetically.
+func NewPluginRouter(plugins *PluginAdapter, priority []string, logger *logging.Logger) *PluginRouter {
+	if logger == nil {
+		logger = logging.Default()
+	}
+
+	ranks := make(map[string]int, len(priority))
+	for i, name := range priority {
+		if _, exists := ranks[name]; !exists {
+			ranks[name] = i
+		}
+	}
+
+	return &PluginRouter{
+		plugins:  plugins,
+		priority: ranks,
+		logger:   logger,
+	}
+}
+
+// Candidates returns the plugins able to price provider for kind, in the order
+// they should be tried. Plugins whose circuit breaker is open are skipped.
+func (r *PluginRouter) Candidates(ctx context.Context, provider string, kind CostKind) ([]*plugin.Plugin, error) {
+	discovered, err := r.plugins.DiscoverPlugins(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*plugin.Plugin
+	for _, p := range discovered {
+		if !supports(p, provider, kind) {
+			continue
+		}
+		if isIncompatible(p) {
+			r.logger.Debug("skipping incompatible plugin", "plugin", p.Name, "provider", provider, "reason", p.Compatibility.Reason)
+			continue
+		}
+		if r.plugins.IsCircuitOpen(p.Name) {
+			r.logger.Debug("skipping plugin with open circuit breaker", "plugin", p.Name, "provider", provider)
+			continue
+		}
+		candidates = append(candidates, p)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return r.less(candidates[i].Name, candidates[j].Name)
+	})
+
+	return candidates, nil
+}
+
+// Providers returns every provider covered by at least one compatible plugin for kind,
+// including providers whose plugins currently have an open circuit breaker
+func (r *PluginRouter) Providers(ctx context.Context, kind CostKind) ([]string, error) {
+	discovered, err := r.plugins.DiscoverPlugins(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var providers []string
+	for _, p := range discovered {
+		if p.Capabilities == nil || isIncompatible(p) {
+			continue
+		}
+		for _, provider := range p.Capabilities.SupportsProviders {
+			provider = strings.ToLower(provider)
+			if seen[provider] || !supports(p, provider, kind) {
+				continue
+			}
+			seen[provider] = true
+			providers = append(providers, provider)
+		}
+	}
+
+	sort.Strings(providers)
+	return providers, nil
+}
+
+// Route calls fn with each candidate plugin in priority order until one
+// succeeds, and returns the name of the plugin that served the request.
+// Failures count against the plugin's circuit breaker; invalid input and
+// canceled contexts stop the fallback immediately.
+func (r *PluginRouter) Route(ctx context.Context, provider string, kind CostKind, fn func(ctx context.Context, pluginName string) error) (string, error) {
+	candidates, err := r.Candidates(ctx, provider, kind)
+	if err != nil {
+		return "", fmt.Errorf("find plugins for %s: %w", provider, err)
+	}
+	return r.try(ctx, provider, kind, candidates, fn)
+}
+
+// RouteAll is like Route, but only tries plugins that can price every one of
+// providers, for requests that cannot be split by provider
+func (r *PluginRouter) RouteAll(ctx context.Context, providers []string, kind CostKind, fn func(ctx context.Context, pluginName string) error) (string, error) {
+	label := strings.Join(providers, ", ")
+	if len(providers) == 0 {
+		return "", fmt.Errorf("%w: no providers given for %s costs", ErrNoPluginAvailable, kind)
+	}
+
+	candidates, err := r.Candidates(ctx, providers[0], kind)
+	if err != nil {
+		return "", fmt.Errorf("find plugins for %s: %w", label, err)
+	}
+	covering := candidates[:0]
+	for _, p := range candidates {
+		coversAll := true
+		for _, provider := range providers[1:] {
+			if !supports(p, provider, kind) {
+				coversAll = false
+				break
+			}
+		}
+		if coversAll {
+			covering = append(covering, p)
+		}
+	}
+	return r.try(ctx, label, kind, covering, fn)
+}
+
+// try calls fn with each candidate in turn until one succeeds
+func (r *PluginRouter) try(ctx context.Context, provider string, kind CostKind, candidates []*plugin.Plugin, fn func(ctx context.Context, pluginName string) error) (string, error) {
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("%w for %s %s costs", ErrNoPluginAvailable, provider, kind)
+	}
+
+	var lastErr error
+	for _, p := range candidates {
+		err := fn(ctx, p.Name)
+		if err == nil {
+			r.plugins.resetFailures(p.Name)
+			return p.Name, nil
+		}
+
+		if errors.Is(err, ErrInvalidInput) || ctx.Err() != nil {
+			return "", err
+		}
+
+		r.plugins.recordFailure(p.Name)
+		r.logger.Warn("plugin failed, trying next", "plugin", p.Name, "provider", provider, "kind", string(kind), "error", err)
+		lastErr = err
+	}
+
+	return "", fmt.Errorf("all plugins failed for %s %s costs: %w", provider, kind, lastErr)
+}
+
+// less orders plugins by configured priority, then by name
+func (r *PluginRouter) less(a, b string) bool {
+	rankA, okA := r.priority[a]
+	rankB, okB := r.priority[b]
+
+	switch {
+	case okA && okB:
+		return rankA < rankB
+	case okA != okB:
+		return okA
+	default:
+		return a < b
+	}
+}
+
+// supports reports whether a plugin declares the capability for a provider
+func supports(p *plugin.Plugin, provider string, kind CostKind) bool {
+	if p.Capabilities == nil {
+		return false
+	}
+
+	switch kind {
+	case CostKindProjected:
+		if !p.Capabilities.SupportsProjected {
+			return false
+		}
+	case CostKindActual:
+		if !p.Capabilities.SupportsActual {
+			return false
+		}
+	case CostKindOptimization:
+		if !p.Capabilities.SupportsOptimization {
+			return false
+		}
+	case CostKindForecast:
+		if !p.Capabilities.SupportsForecast {
+			return false
+		}
+	default:
+		return false
+	}
+
+	for _, supported := range p.Capabilities.SupportsProviders {
+		if strings.EqualFold(supported, provider) {
+			return true
+		}
+	}
+	return false
+}
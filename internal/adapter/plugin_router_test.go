@@ -0,0 +1,136 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPluginRouter_Candidates verifies provider matching and priority ordering
+func TestPluginRouter_Candidates(t *testing.T) {
+	tmpDir := t.TempDir()
+	createMockPlugin(t, tmpDir, "infracost", "1.0.0", "aws,azure,gcp")
+	createMockPlugin(t, tmpDir, "aws-cur", "1.0.0", "aws")
+	createMockPlugin(t, tmpDir, "kubecost", "2.1.0", "kubernetes")
+
+	plugins := NewPluginAdapter(tmpDir, logging.Default())
+	ctx := context.Background()
+
+	router := NewPluginRouter(plugins, nil, nil)
+	candidates, err := router.Candidates(ctx, "AWS", CostKindProjected)
+	require.NoError(t, err)
+	require.Len(t, candidates, 2)
+	assert.Equal(t, "aws-cur", candidates[0].Name, "unprioritized plugins are ordered by name")
+	assert.Equal(t, "infracost", candidates[1].Name)
+
+	router = NewPluginRouter(plugins, []string{"infracost"}, nil)
+	candidates, err = router.Candidates(ctx, "aws", CostKindActual)
+	require.NoError(t, err)
+	require.Len(t, candidates, 2)
+	assert.Equal(t, "infracost", candidates[0].Name, "prioritized plugins come first")
+
+	candidates, err = router.Candidates(ctx, "oracle", CostKindProjected)
+	require.NoError(t, err)
+	assert.Empty(t, candidates)
+}
+
+// TestPluginRouter_RouteFallsBack verifies failures move on to the next plugin
+func TestPluginRouter_RouteFallsBack(t *testing.T) {
+	tmpDir := t.TempDir()
+	createMockPlugin(t, tmpDir, "aws-cur", "1.0.0", "aws")
+	createMockPlugin(t, tmpDir, "infracost", "1.0.0", "aws")
+
+	plugins := NewPluginAdapter(tmpDir, logging.Default())
+	router := NewPluginRouter(plugins, []string{"aws-cur", "infracost"}, nil)
+
+	var tried []string
+	served, err := router.Route(context.Background(), "aws", CostKindActual, func(ctx context.Context, pluginName string) error {
+		tried = append(tried, pluginName)
+		if pluginName == "aws-cur" {
+			return errors.New("plugin unavailable")
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "infracost", served)
+	assert.Equal(t, []string{"aws-cur", "infracost"}, tried)
+}
+
+// TestPluginRouter_RouteAll verifies only plugins covering every provider are tried
+func TestPluginRouter_RouteAll(t *testing.T) {
+	tmpDir := t.TempDir()
+	createMockPlugin(t, tmpDir, "aws-cur", "1.0.0", "aws")
+	createMockPlugin(t, tmpDir, "infracost", "1.0.0", "aws,azure,gcp")
+
+	router := NewPluginRouter(NewPluginAdapter(tmpDir, logging.Default()), []string{"aws-cur"}, nil)
+	ctx := context.Background()
+
+	var tried []string
+	served, err := router.RouteAll(ctx, []string{"aws", "azure"}, CostKindActual, func(ctx context.Context, pluginName string) error {
+		tried = append(tried, pluginName)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "infracost", served)
+	assert.Equal(t, []string{"infracost"}, tried, "aws-cur does not cover azure")
+
+	_, err = router.RouteAll(ctx, []string{"aws", "kubernetes"}, CostKindActual, func(ctx context.Context, pluginName string) error {
+		t.Fatalf("no plugin covers kubernetes, %s should not be called", pluginName)
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrNoPluginAvailable)
+
+	_, err = router.RouteAll(ctx, nil, CostKindActual, func(ctx context.Context, pluginName string) error { return nil })
+	assert.ErrorIs(t, err, ErrNoPluginAvailable)
+}
+
+// TestPluginRouter_SkipsOpenCircuit verifies plugins with an open breaker are not called
+func TestPluginRouter_SkipsOpenCircuit(t *testing.T) {
+	tmpDir := t.TempDir()
+	createMockPlugin(t, tmpDir, "aws-cur", "1.0.0", "aws")
+	createMockPlugin(t, tmpDir, "infracost", "1.0.0", "aws")
+
+	plugins := NewPluginAdapter(tmpDir, logging.Default())
+	for i := 0; i < 5; i++ {
+		plugins.recordFailure("aws-cur")
+	}
+	require.True(t, plugins.IsCircuitOpen("aws-cur"))
+
+	router := NewPluginRouter(plugins, []string{"aws-cur"}, nil)
+	served, err := router.Route(context.Background(), "aws", CostKindProjected, func(ctx context.Context, pluginName string) error {
+		assert.NotEqual(t, "aws-cur", pluginName)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "infracost", served)
+}
+
+// TestPluginRouter_Errors verifies invalid input stops fallback and missing coverage is reported
+func TestPluginRouter_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	createMockPlugin(t, tmpDir, "aws-cur", "1.0.0", "aws")
+	createMockPlugin(t, tmpDir, "infracost", "1.0.0", "aws")
+
+	plugins := NewPluginAdapter(tmpDir, logging.Default())
+	router := NewPluginRouter(plugins, nil, nil)
+	ctx := context.Background()
+
+	calls := 0
+	_, err := router.Route(ctx, "aws", CostKindProjected, func(ctx context.Context, pluginName string) error {
+		calls++
+		return ErrInvalidInput
+	})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	assert.Equal(t, 1, calls, "invalid input should not be retried on another plugin")
+
+	_, err = router.Route(ctx, "gcp", CostKindProjected, func(ctx context.Context, pluginName string) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrNoPluginAvailable)
+}
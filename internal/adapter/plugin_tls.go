@@ -0,0 +1,124 @@
+package adapter
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TLSConfig describes how to secure the gRPC connection to a plugin. It can be
+// declared in plugin.json under "tls" or overridden per plugin in server config.
+// Relative paths are resolved against the plugin's directory.
+type TLSConfig struct {
+	// CAFile is a PEM bundle used to verify the plugin; empty uses system roots
+	CAFile string `json:"ca_file"`
+	// CertFile and KeyFile hold the client certificate presented for mTLS
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+	// ServerName overrides the host name verified against the plugin certificate
+	ServerName string `json:"server_name"`
+}
+
+// PluginAdapterOptions holds optional PluginAdapter settings
+type PluginAdapterOptions struct {
+	// TLS overrides plugin.json TLS settings, keyed by plugin name
+	TLS map[string]TLSConfig
+	// Integrity controls checksum and signature verification
+	Integrity IntegrityOptions
+	// SpecVersion is the pulumicost-spec version plugins are checked against
+	SpecVersion string
+	// Configuration overrides plugin.json "configuration", keyed by plugin name
+	Configuration map[string]map[string]any
+	// Launch controls which plugins may be launched by path for validation
+	Launch LaunchOptions
+}
+
+// tlsConfigFor returns the effective TLS settings for a plugin, preferring
+// server-side overrides over plugin.json. Nil means plaintext.
+func (a *PluginAdapter) tlsConfigFor(meta *pluginMetadata) *TLSConfig {
+	if override, ok := a.options.TLS[meta.Name]; ok {
+		return &override
+	}
+	return meta.TLS
+}
+
+// transportCredentials builds gRPC credentials for a plugin, validating its
+// TLS settings. Misconfiguration is reported as an error rather than falling
+// back to plaintext.
+func (a *PluginAdapter) transportCredentials(meta *pluginMetadata, pluginPath string) (credentials.TransportCredentials, error) {
+	cfg := a.tlsConfigFor(meta)
+	if cfg == nil {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConfig, err := cfg.build(pluginPath)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: invalid TLS configuration: %w", meta.Name, err)
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// build loads the referenced certificates into a crypto/tls configuration
+func (c *TLSConfig) build(baseDir string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: c.ServerName,
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(resolvePath(baseDir, c.CAFile))
+		if err != nil {
+			return nil, fmt.Errorf("read ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_file %s contains no PEM certificates", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	switch {
+	case c.CertFile != "" && c.KeyFile != "":
+		cert, err := tls.LoadX509KeyPair(resolvePath(baseDir, c.CertFile), resolvePath(baseDir, c.KeyFile))
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	case c.CertFile != "" || c.KeyFile != "":
+		return nil, fmt.Errorf("cert_file and key_file must be set together")
+	}
+
+	return tlsConfig, nil
+}
+
+// fingerprint hashes the settings and the certificate and key files they
+// refer to, so rotated files are noticed even when the settings themselves
+// are unchanged. Unreadable files hash as empty.
+func (c *TLSConfig) fingerprint(baseDir string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%q %q %q %q\n", c.CAFile, c.CertFile, c.KeyFile, c.ServerName)
+	for _, path := range []string{c.CAFile, c.CertFile, c.KeyFile} {
+		var sum [sha256.Size]byte
+		if path != "" {
+			data, _ := os.ReadFile(resolvePath(baseDir, path))
+			sum = sha256.Sum256(data)
+		}
+		hash.Write(sum[:])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// resolvePath makes relative certificate paths relative to the plugin directory
+func resolvePath(baseDir, path string) string {
+	if filepath.IsAbs(path) || baseDir == "" {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
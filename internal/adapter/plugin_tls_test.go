@@ -0,0 +1,227 @@
+package adapter
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/gen/plugin"
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// TestDiscoverPlugins_RejectsMisconfiguredTLS verifies broken TLS settings exclude a plugin
+func TestDiscoverPlugins_RejectsMisconfiguredTLS(t *testing.T) {
+	tmpDir := t.TempDir()
+	pki := generateTestPKI(t, tmpDir)
+
+	writePluginJSON(t, tmpDir, "good", `{"name": "good", "version": "1.0.0", "grpc_address": "localhost:1",
+		"tls": {"ca_file": "../`+pki.caFile+`", "cert_file": "../`+pki.clientCert+`", "key_file": "../`+pki.clientKey+`"}}`)
+	writePluginJSON(t, tmpDir, "missing-key", `{"name": "missing-key", "version": "1.0.0",
+		"tls": {"cert_file": "../`+pki.clientCert+`"}}`)
+	writePluginJSON(t, tmpDir, "missing-ca", `{"name": "missing-ca", "version": "1.0.0",
+		"tls": {"ca_file": "does-not-exist.pem"}}`)
+	writePluginJSON(t, tmpDir, "not-pem", `{"name": "not-pem", "version": "1.0.0",
+		"tls": {"ca_file": "plugin.json"}}`)
+
+	plugins, err := NewPluginAdapter(tmpDir, logging.Default()).DiscoverPlugins(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	assert.Equal(t, "good", plugins[0].Name)
+}
+
+// TestTLSConfig_Errors verifies misconfiguration produces a descriptive error
+func TestTLSConfig_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	pki := generateTestPKI(t, tmpDir)
+
+	_, err := (&TLSConfig{CertFile: pki.clientCert}).build(tmpDir)
+	assert.ErrorContains(t, err, "cert_file and key_file must be set together")
+
+	_, err = (&TLSConfig{CAFile: "missing.pem"}).build(tmpDir)
+	assert.ErrorContains(t, err, "read ca_file")
+
+	_, err = (&TLSConfig{CertFile: pki.clientCert, KeyFile: pki.caFile}).build(tmpDir)
+	assert.ErrorContains(t, err, "load client certificate")
+
+	cfg, err := (&TLSConfig{CAFile: pki.caFile, ServerName: "plugin.local"}).build(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, "plugin.local", cfg.ServerName)
+	assert.NotNil(t, cfg.RootCAs)
+}
+
+// TestDiscoverPlugins_TLSOverride verifies server config overrides plugin.json TLS settings
+func TestDiscoverPlugins_TLSOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+	pki := generateTestPKI(t, tmpDir)
+
+	writePluginJSON(t, tmpDir, "overridden", `{"name": "overridden", "version": "1.0.0",
+		"tls": {"ca_file": "does-not-exist.pem"}}`)
+
+	adapter := NewPluginAdapterWithOptions(tmpDir, PluginAdapterOptions{
+		TLS: map[string]TLSConfig{
+			"overridden": {CAFile: filepath.Join(tmpDir, pki.caFile)},
+		},
+	}, logging.Default())
+
+	plugins, err := adapter.DiscoverPlugins(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+}
+
+// TestEstablishConnection_MutualTLS verifies plugins can be reached over mTLS
+func TestEstablishConnection_MutualTLS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping gRPC integration test in short mode")
+	}
+
+	tmpDir := t.TempDir()
+	pki := generateTestPKI(t, tmpDir)
+	address := startTLSHealthServer(t, pki)
+
+	writePluginJSON(t, tmpDir, "secure", `{"name": "secure", "version": "1.0.0", "grpc_address": "`+address+`",
+		"tls": {"ca_file": "../`+pki.caFile+`", "cert_file": "../`+pki.clientCert+`", "key_file": "../`+pki.clientKey+`", "server_name": "localhost"}}`)
+	writePluginJSON(t, tmpDir, "no-client-cert", `{"name": "no-client-cert", "version": "1.0.0", "grpc_address": "`+address+`",
+		"tls": {"ca_file": "../`+pki.caFile+`", "server_name": "localhost"}}`)
+
+	adapter := NewPluginAdapter(tmpDir, logging.Default())
+	defer adapter.Close()
+
+	ctx := context.Background()
+	status, _, err := adapter.HealthCheck(ctx, &plugin.Plugin{Name: "secure"})
+	require.NoError(t, err)
+	assert.Equal(t, "healthy", status)
+
+	status, _, err = adapter.HealthCheck(ctx, &plugin.Plugin{Name: "no-client-cert"})
+	assert.Error(t, err, "server requires a client certificate")
+	assert.Equal(t, "unhealthy", status)
+}
+
+// Helper functions
+
+// testPKI holds file names, relative to the directory passed to generateTestPKI
+type testPKI struct {
+	caFile     string
+	serverCert string
+	serverKey  string
+	clientCert string
+	clientKey  string
+	dir        string
+}
+
+func generateTestPKI(t *testing.T, dir string) testPKI {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "pulumicost-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	issue := func(serial int64, name string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     []string{"localhost"},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	}
+
+	pki := testPKI{
+		caFile:     "ca.pem",
+		serverCert: "server.pem",
+		serverKey:  "server-key.pem",
+		clientCert: "client.pem",
+		clientKey:  "client-key.pem",
+		dir:        dir,
+	}
+
+	serverCert, serverKey := issue(2, "plugin", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := issue(3, "pulumicost-mcp", x509.ExtKeyUsageClientAuth)
+
+	files := map[string][]byte{
+		pki.caFile:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		pki.serverCert: serverCert,
+		pki.serverKey:  serverKey,
+		pki.clientCert: clientCert,
+		pki.clientKey:  clientKey,
+	}
+	for name, data := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0600))
+	}
+
+	return pki
+}
+
+// startTLSHealthServer runs a gRPC health service that requires client certificates
+func startTLSHealthServer(t *testing.T, pki testPKI) string {
+	t.Helper()
+
+	cert, err := tls.LoadX509KeyPair(filepath.Join(pki.dir, pki.serverCert), filepath.Join(pki.dir, pki.serverKey))
+	require.NoError(t, err)
+	caPEM, err := os.ReadFile(filepath.Join(pki.dir, pki.caFile))
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM(caPEM))
+
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	})))
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func writePluginJSON(t *testing.T, baseDir, name, metadata string) {
+	t.Helper()
+
+	pluginDir := filepath.Join(baseDir, name)
+	require.NoError(t, os.MkdirAll(pluginDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "plugin.json"), []byte(metadata), 0644))
+}
@@ -0,0 +1,192 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+)
+
+// PluginChangeKind describes how a plugin changed between two directory scans
+type PluginChangeKind string
+
+const (
+	// PluginAdded indicates a new plugin directory with valid metadata
+	PluginAdded PluginChangeKind = "added"
+	// PluginRemoved indicates a plugin directory or its metadata disappeared
+	PluginRemoved PluginChangeKind = "removed"
+	// PluginUpdated indicates the plugin.json contents changed
+	PluginUpdated PluginChangeKind = "updated"
+)
+
+// PluginChange describes a single plugin difference detected by the watcher
+type PluginChange struct {
+	Name       string
+	Kind       PluginChangeKind
+	OldVersion string
+	NewVersion string
+}
+
+// PluginWatcher polls the plugin directory and reconciles adapter state when
+// plugins are added, removed or have their metadata changed
+type PluginWatcher struct {
+	adapter  *PluginAdapter
+	interval time.Duration
+	onChange func([]PluginChange)
+	logger   *logging.Logger
+
+	mu       sync.Mutex
+	snapshot map[string]*pluginMetadata
+}
+
+// NewPluginWatcher creates a watcher for the adapter's plugin directory.
+// onChange is invoked after reconciliation whenever a poll detects changes.
+func NewPluginWatcher(a *PluginAdapter, interval time.Duration, onChange func([]PluginChange)) *PluginWatcher {
+	return &PluginWatcher{
+		adapter:  a,
+		interval: interval,
+		onChange: onChange,
+		logger:   a.logger,
+	}
+}
+
+// Run polls the plugin directory until the context is canceled
+func (w *PluginWatcher) Run(ctx context.Context) {
+	w.logger.Info("watching plugin directory", "dir", w.adapter.pluginDir, "interval", w.interval.String())
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Poll(ctx); err != nil {
+			w.logger.Warn("plugin directory poll failed", "dir", w.adapter.pluginDir, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll scans the plugin directory once, reconciles connections and circuit
+// breakers for changed plugins, and returns the detected changes. The first
+// poll records a baseline and reports no changes.
+func (w *PluginWatcher) Poll(ctx context.Context) ([]PluginChange, error) {
+	current, err := w.adapter.scanPluginMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	previous := w.snapshot
+	w.snapshot = current
+	w.mu.Unlock()
+
+	if previous == nil {
+		return nil, nil
+	}
+
+	changes := diffPluginMetadata(previous, current)
+	for _, change := range changes {
+		w.adapter.reconcile(change, previous[change.Name], current[change.Name])
+		w.logger.Info("plugin change detected",
+			"plugin", change.Name,
+			"kind", string(change.Kind),
+			"old_version", change.OldVersion,
+			"new_version", change.NewVersion)
+	}
+
+	if len(changes) > 0 && w.onChange != nil {
+		w.onChange(changes)
+	}
+
+	return changes, nil
+}
+
+// diffPluginMetadata compares two scans and returns changes sorted by plugin name
+func diffPluginMetadata(previous, current map[string]*pluginMetadata) []PluginChange {
+	var changes []PluginChange
+
+	for name, meta := range current {
+		old, existed := previous[name]
+		switch {
+		case !existed:
+			changes = append(changes, PluginChange{Name: name, Kind: PluginAdded, NewVersion: meta.Version})
+		case !reflect.DeepEqual(old, meta):
+			changes = append(changes, PluginChange{Name: name, Kind: PluginUpdated, OldVersion: old.Version, NewVersion: meta.Version})
+		}
+	}
+
+	for name, meta := range previous {
+		if _, exists := current[name]; !exists {
+			changes = append(changes, PluginChange{Name: name, Kind: PluginRemoved, OldVersion: meta.Version})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+
+	return changes
+}
+
+// scanPluginMetadata loads plugin.json for every plugin directory, keyed by directory name
+func (a *PluginAdapter) scanPluginMetadata() (map[string]*pluginMetadata, error) {
+	plugins := make(map[string]*pluginMetadata)
+
+	entries, err := os.ReadDir(a.pluginDir)
+	if os.IsNotExist(err) {
+		return plugins, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read plugin directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || isReservedDir(entry.Name()) {
+			continue
+		}
+
+		pluginPath := filepath.Join(a.pluginDir, entry.Name())
+		meta, err := loadPluginMetadata(filepath.Join(pluginPath, "plugin.json"))
+		if err != nil {
+			// Missing or unparseable metadata is treated the same as an absent plugin
+			continue
+		}
+		if cfg := a.tlsConfigFor(meta); cfg != nil {
+			meta.tlsFingerprint = cfg.fingerprint(pluginPath)
+		}
+		plugins[entry.Name()] = meta
+	}
+
+	return plugins, nil
+}
+
+// reconcile drops stale connection and circuit breaker state for a changed plugin
+func (a *PluginAdapter) reconcile(change PluginChange, old, current *pluginMetadata) {
+	switch change.Kind {
+	case PluginRemoved:
+		a.closeConnection(change.Name)
+		a.resetCircuitBreaker(change.Name)
+	case PluginUpdated:
+		if old.GRPCAddress != current.GRPCAddress || old.Version != current.Version ||
+			!reflect.DeepEqual(old.TLS, current.TLS) || old.tlsFingerprint != current.tlsFingerprint ||
+			!reflect.DeepEqual(old.Checksums, current.Checksums) || !reflect.DeepEqual(old.Signature, current.Signature) {
+			a.closeConnection(change.Name)
+		}
+		if old.Version != current.Version {
+			a.resetCircuitBreaker(change.Name)
+		}
+	case PluginAdded:
+		// A re-added plugin must not inherit failures from a previous install
+		a.resetCircuitBreaker(change.Name)
+	}
+}
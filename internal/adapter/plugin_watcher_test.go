@@ -0,0 +1,160 @@
+package adapter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TestPluginWatcher_DetectsChanges verifies additions, upgrades and removals are reported
+func TestPluginWatcher_DetectsChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	createMockPlugin(t, tmpDir, "infracost", "1.0.0", "aws")
+
+	adapter := NewPluginAdapter(tmpDir, logging.Default())
+
+	var notified [][]PluginChange
+	watcher := NewPluginWatcher(adapter, 0, func(changes []PluginChange) {
+		notified = append(notified, changes)
+	})
+
+	ctx := context.Background()
+
+	// First poll only records the baseline
+	changes, err := watcher.Poll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// Add a plugin
+	createMockPlugin(t, tmpDir, "kubecost", "2.1.0", "kubernetes")
+	changes, err = watcher.Poll(ctx)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, PluginChange{Name: "kubecost", Kind: PluginAdded, NewVersion: "2.1.0"}, changes[0])
+
+	// Upgrade a plugin
+	createMockPlugin(t, tmpDir, "infracost", "1.1.0", "aws")
+	changes, err = watcher.Poll(ctx)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, PluginChange{Name: "infracost", Kind: PluginUpdated, OldVersion: "1.0.0", NewVersion: "1.1.0"}, changes[0])
+
+	// Remove a plugin
+	require.NoError(t, os.RemoveAll(filepath.Join(tmpDir, "kubecost")))
+	changes, err = watcher.Poll(ctx)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, PluginChange{Name: "kubecost", Kind: PluginRemoved, OldVersion: "2.1.0"}, changes[0])
+
+	// No further changes
+	changes, err = watcher.Poll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	assert.Len(t, notified, 3, "onChange should fire once per poll with changes")
+}
+
+// TestPluginWatcher_ReconcilesState verifies stale connections and breakers are dropped
+func TestPluginWatcher_ReconcilesState(t *testing.T) {
+	tmpDir := t.TempDir()
+	createMockPlugin(t, tmpDir, "infracost", "1.0.0", "aws")
+	createMockPlugin(t, tmpDir, "kubecost", "2.1.0", "kubernetes")
+
+	adapter := NewPluginAdapter(tmpDir, logging.Default())
+	defer adapter.Close()
+
+	watcher := NewPluginWatcher(adapter, 0, nil)
+	ctx := context.Background()
+
+	_, err := watcher.Poll(ctx)
+	require.NoError(t, err)
+
+	// Simulate live connections and an open breaker for both plugins
+	for _, name := range []string{"infracost", "kubecost"} {
+		conn, err := grpc.NewClient("localhost:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		adapter.connections[name] = &pluginConnection{conn: conn}
+		for i := 0; i < 5; i++ {
+			adapter.recordFailure(name)
+		}
+		require.True(t, adapter.IsCircuitOpen(name))
+	}
+
+	// Upgrade one plugin and remove the other
+	createMockPlugin(t, tmpDir, "infracost", "1.1.0", "aws")
+	require.NoError(t, os.RemoveAll(filepath.Join(tmpDir, "kubecost")))
+
+	_, err = watcher.Poll(ctx)
+	require.NoError(t, err)
+
+	assert.Empty(t, adapter.connections, "stale connections should be closed")
+	assert.False(t, adapter.IsCircuitOpen("infracost"), "upgraded plugin should get a fresh breaker")
+	assert.False(t, adapter.IsCircuitOpen("kubecost"), "removed plugin breaker should be discarded")
+}
+
+// TestPluginWatcher_TLSChanges verifies changed TLS settings and rotated
+// certificate files drop the plugin's connection
+func TestPluginWatcher_TLSChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTLSPlugin := func(serverName string) {
+		writePluginJSON(t, tmpDir, "aws-cur", `{"name": "aws-cur", "version": "1.0.0", "providers": "aws",
+			"grpc_address": "localhost:50051", "capabilities": {"supports_actual_cost": true},
+			"tls": {"ca_file": "ca.pem", "server_name": "`+serverName+`"}}`)
+	}
+	caPath := filepath.Join(tmpDir, "aws-cur", "ca.pem")
+	writeTLSPlugin("cur.internal")
+	require.NoError(t, os.WriteFile(caPath, []byte("first CA"), 0644))
+
+	adapter := NewPluginAdapter(tmpDir, logging.Default())
+	defer adapter.Close()
+	watcher := NewPluginWatcher(adapter, 0, nil)
+	ctx := context.Background()
+
+	_, err := watcher.Poll(ctx)
+	require.NoError(t, err)
+
+	connect := func() {
+		conn, err := grpc.NewClient("localhost:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		adapter.connections["aws-cur"] = &pluginConnection{conn: conn}
+	}
+
+	connect()
+	writeTLSPlugin("cur.example.com")
+	changes, err := watcher.Poll(ctx)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Empty(t, adapter.connections, "a new server name needs a new connection")
+
+	connect()
+	require.NoError(t, os.WriteFile(caPath, []byte("rotated CA"), 0644))
+	changes, err = watcher.Poll(ctx)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, PluginUpdated, changes[0].Kind)
+	assert.Empty(t, adapter.connections, "a rotated CA needs a new connection")
+
+	connect()
+	changes, err = watcher.Poll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Len(t, adapter.connections, 1)
+}
+
+// TestPluginWatcher_MissingDirectory verifies a missing plugin directory is not an error
+func TestPluginWatcher_MissingDirectory(t *testing.T) {
+	adapter := NewPluginAdapter(filepath.Join(t.TempDir(), "missing"), logging.Default())
+	watcher := NewPluginWatcher(adapter, 0, nil)
+
+	changes, err := watcher.Poll(context.Background())
+
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,134 @@
+package adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ProviderPreview is the subset of a Pulumi preview that belongs to one provider
+type ProviderPreview struct {
+	Provider string
+	// JSON is the original preview document with only this provider's resources
+	JSON string
+	URNs []string
+}
+
+// previewResource holds the fields needed to attribute a preview entry to a provider
+type previewResource struct {
+	URN      string `json:"urn"`
+	Type     string `json:"type"`
+	NewState *struct {
+		Type string `json:"type"`
+	} `json:"newState"`
+}
+
+// previewArrayKeys lists the top-level keys that hold resources, in the
+// order they are checked: exported state uses "resources", `pulumi preview --json` uses "steps"
+var previewArrayKeys = []string{"resources", "steps"}
+
+// SplitPreviewByProvider partitions a Pulumi preview into one document per
+// cloud provider. Pulumi-internal resources (stacks, provider instances) carry
+// no cost and are dropped. Providers are returned in alphabetical order.
+func SplitPreviewByProvider(pulumiJSON string) ([]ProviderPreview, error) {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(pulumiJSON), &document); err != nil {
+		return nil, fmt.Errorf("invalid Pulumi JSON: %w: %w", ErrInvalidInput, err)
+	}
+
+	key := ""
+	for _, candidate := range previewArrayKeys {
+		if _, ok := document[candidate]; ok {
+			key = candidate
+			break
+		}
+	}
+	if key == "" {
+		return nil, nil
+	}
+
+	var entries []json.RawMessage
+	if err := json.Unmarshal(document[key], &entries); err != nil {
+		return nil, fmt.Errorf("invalid Pulumi JSON %q: %w: %w", key, ErrInvalidInput, err)
+	}
+
+	entriesByProvider := make(map[string][]json.RawMessage)
+	urnsByProvider := make(map[string][]string)
+	for _, entry := range entries {
+		var res previewResource
+		if err := json.Unmarshal(entry, &res); err != nil {
+			continue
+		}
+
+		provider := providerOf(res)
+		if provider == "" || provider == "pulumi" {
+			continue
+		}
+
+		entriesByProvider[provider] = append(entriesByProvider[provider], entry)
+		if res.URN != "" {
+			urnsByProvider[provider] = append(urnsByProvider[provider], res.URN)
+		}
+	}
+
+	providers := make([]string, 0, len(entriesByProvider))
+	for provider := range entriesByProvider {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	previews := make([]ProviderPreview, 0, len(providers))
+	for _, provider := range providers {
+		subset, err := json.Marshal(entriesByProvider[provider])
+		if err != nil {
+			return nil, fmt.Errorf("encode %s resources: %w", provider, err)
+		}
+		document[key] = subset
+
+		encoded, err := json.Marshal(document)
+		if err != nil {
+			return nil, fmt.Errorf("encode %s preview: %w", provider, err)
+		}
+
+		previews = append(previews, ProviderPreview{
+			Provider: provider,
+			JSON:     string(encoded),
+			URNs:     urnsByProvider[provider],
+		})
+	}
+
+	return previews, nil
+}
+
+// providerOf derives the provider package from a resource type such as
+// "aws:ec2/instance:Instance", falling back to the type embedded in the URN
+func providerOf(res previewResource) string {
+	resourceType := res.Type
+	if resourceType == "" && res.NewState != nil {
+		resourceType = res.NewState.Type
+	}
+	if resourceType == "" {
+		resourceType = typeFromURN(res.URN)
+	}
+
+	provider, _, found := strings.Cut(resourceType, ":")
+	if !found {
+		return ""
+	}
+	return strings.ToLower(provider)
+}
+
+// typeFromURN extracts the resource type from
+// urn:pulumi:<stack>::<project>::<parent$type>::<name>
+func typeFromURN(urn string) string {
+	parts := strings.Split(urn, "::")
+	if len(parts) < 4 {
+		return ""
+	}
+	qualifiedType := parts[2]
+	if i := strings.LastIndex(qualifiedType, "$"); i >= 0 {
+		qualifiedType = qualifiedType[i+1:]
+	}
+	return qualifiedType
+}
@@ -0,0 +1,66 @@
+package adapter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSplitPreviewByProvider verifies resources are partitioned by provider
+func TestSplitPreviewByProvider(t *testing.T) {
+	previewJSON := `{
+		"stack": "dev",
+		"resources": [
+			{"urn": "urn:pulumi:dev::app::pulumi:pulumi:Stack::app-dev", "type": "pulumi:pulumi:Stack"},
+			{"urn": "urn:pulumi:dev::app::aws:ec2/instance:Instance::web", "type": "aws:ec2/instance:Instance"},
+			{"urn": "urn:pulumi:dev::app::gcp:compute/instance:Instance::worker", "type": "gcp:compute/instance:Instance"},
+			{"urn": "urn:pulumi:dev::app::my:component:Web$aws:s3/bucket:Bucket::assets"}
+		]
+	}`
+
+	previews, err := SplitPreviewByProvider(previewJSON)
+	require.NoError(t, err)
+	require.Len(t, previews, 2)
+
+	assert.Equal(t, "aws", previews[0].Provider)
+	assert.Equal(t, []string{
+		"urn:pulumi:dev::app::aws:ec2/instance:Instance::web",
+		"urn:pulumi:dev::app::my:component:Web$aws:s3/bucket:Bucket::assets",
+	}, previews[0].URNs)
+
+	assert.Equal(t, "gcp", previews[1].Provider)
+	assert.Len(t, previews[1].URNs, 1)
+
+	// Each sub-preview keeps other top-level fields and only its own resources
+	var document struct {
+		Stack     string            `json:"stack"`
+		Resources []json.RawMessage `json:"resources"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(previews[1].JSON), &document))
+	assert.Equal(t, "dev", document.Stack)
+	assert.Len(t, document.Resources, 1)
+}
+
+// TestSplitPreviewByProvider_Steps verifies `pulumi preview --json` output is supported
+func TestSplitPreviewByProvider_Steps(t *testing.T) {
+	previewJSON := `{"steps": [
+		{"op": "create", "urn": "urn:pulumi:dev::app::kubernetes:apps/v1:Deployment::api", "newState": {"type": "kubernetes:apps/v1:Deployment"}}
+	]}`
+
+	previews, err := SplitPreviewByProvider(previewJSON)
+	require.NoError(t, err)
+	require.Len(t, previews, 1)
+	assert.Equal(t, "kubernetes", previews[0].Provider)
+}
+
+// TestSplitPreviewByProvider_InvalidJSON verifies malformed input is reported as invalid
+func TestSplitPreviewByProvider_InvalidJSON(t *testing.T) {
+	_, err := SplitPreviewByProvider("not json")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	previews, err := SplitPreviewByProvider(`{}`)
+	require.NoError(t, err)
+	assert.Empty(t, previews)
+}
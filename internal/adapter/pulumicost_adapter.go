@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -16,9 +18,22 @@ type PulumiCostAdapter interface {
 	GetProjectedCostWithFilters(ctx context.Context, pulumiJSON string, filters *ResourceFilters) (*CostResult, error)
 	GetActualCost(ctx context.Context, stackName string, timeRange TimeRange) (*CostResult, error)
 	GetActualCostWithGranularity(ctx context.Context, stackName string, timeRange TimeRange, granularity string) (*CostResult, error)
+	GetProjectedCostWithAdapter(ctx context.Context, pulumiJSON string, filters *ResourceFilters, adapterName string) (*CostResult, error)
+	GetActualCostWithAdapter(ctx context.Context, stackName string, timeRange TimeRange, granularity string, adapterName string) (*CostResult, error)
+	GetRecommendations(ctx context.Context, stackName string) (*RecommendationResult, error)
+	GetRecommendationsWithAdapter(ctx context.Context, stackName string, adapterName string) (*RecommendationResult, error)
+	GetForecast(ctx context.Context, stackName string, period TimeRange, granularity string, confidence float64, adapterName string) (*ForecastResult, error)
 	GetCorePath() string
 }
 
+// ErrInvalidInput marks errors caused by the request itself rather than by
+// pulumicost-core or a plugin, so callers know retrying elsewhere won't help
+var ErrInvalidInput = errors.New("invalid input")
+
+// ErrForecastUnsupported marks a cost source that cannot produce forecasts,
+// as opposed to one that failed while producing one
+var ErrForecastUnsupported = errors.New("forecasting not supported")
+
 // pulumiCostAdapter is the concrete implementation
 type pulumiCostAdapter struct {
 	corePath string
@@ -43,17 +58,28 @@ func (a *pulumiCostAdapter) GetProjectedCost(ctx context.Context, pulumiJSON str
 
 // GetProjectedCostWithFilters calculates projected costs with resource filters
 func (a *pulumiCostAdapter) GetProjectedCostWithFilters(ctx context.Context, pulumiJSON string, filters *ResourceFilters) (*CostResult, error) {
+	return a.GetProjectedCostWithAdapter(ctx, pulumiJSON, filters, "")
+}
+
+// GetProjectedCostWithAdapter calculates projected costs using a specific cost source plugin.
+// An empty adapterName lets pulumicost-core choose.
+func (a *pulumiCostAdapter) GetProjectedCostWithAdapter(ctx context.Context, pulumiJSON string, filters *ResourceFilters, adapterName string) (*CostResult, error) {
 	// Validate JSON first
 	var previewData map[string]interface{}
 	if err := json.Unmarshal([]byte(pulumiJSON), &previewData); err != nil {
-		return nil, fmt.Errorf("invalid Pulumi JSON: %w", err)
+		return nil, fmt.Errorf("invalid Pulumi JSON: %w: %w", ErrInvalidInput, err)
 	}
 
 	// Prepare command with timeout
 	cmdCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(cmdCtx, a.corePath, "analyze", "--projected")
+	args := []string{"analyze", "--projected"}
+	if adapterName != "" {
+		args = append(args, "--adapter", adapterName)
+	}
+
+	cmd := exec.CommandContext(cmdCtx, a.corePath, args...)
 
 	// Pass Pulumi JSON via stdin
 	cmd.Stdin = strings.NewReader(pulumiJSON)
@@ -80,6 +106,8 @@ func (a *pulumiCostAdapter) GetProjectedCostWithFilters(ctx context.Context, pul
 		return nil, fmt.Errorf("failed to parse pulumicost output: %w", err)
 	}
 
+	stampAdapter(result.Resources, adapterName)
+
 	// Apply filters if provided
 	if filters != nil {
 		result.Resources = applyFilters(result.Resources, filters)
@@ -97,12 +125,18 @@ func (a *pulumiCostAdapter) GetActualCost(ctx context.Context, stackName string,
 
 // GetActualCostWithGranularity retrieves historical costs with specific time granularity
 func (a *pulumiCostAdapter) GetActualCostWithGranularity(ctx context.Context, stackName string, timeRange TimeRange, granularity string) (*CostResult, error) {
+	return a.GetActualCostWithAdapter(ctx, stackName, timeRange, granularity, "")
+}
+
+// GetActualCostWithAdapter retrieves historical costs using a specific cost source plugin.
+// An empty adapterName lets pulumicost-core choose.
+func (a *pulumiCostAdapter) GetActualCostWithAdapter(ctx context.Context, stackName string, timeRange TimeRange, granularity string, adapterName string) (*CostResult, error) {
 	// Validate time range
 	if _, err := time.Parse(time.RFC3339, timeRange.Start); err != nil {
-		return nil, fmt.Errorf("invalid start time format: %w", err)
+		return nil, fmt.Errorf("invalid start time format: %w: %w", ErrInvalidInput, err)
 	}
 	if _, err := time.Parse(time.RFC3339, timeRange.End); err != nil {
-		return nil, fmt.Errorf("invalid end time format: %w", err)
+		return nil, fmt.Errorf("invalid end time format: %w: %w", ErrInvalidInput, err)
 	}
 
 	// Prepare command with timeout
@@ -113,6 +147,9 @@ func (a *pulumiCostAdapter) GetActualCostWithGranularity(ctx context.Context, st
 	if granularity != "" {
 		args = append(args, "--granularity", granularity)
 	}
+	if adapterName != "" {
+		args = append(args, "--adapter", adapterName)
+	}
 
 	cmd := exec.CommandContext(cmdCtx, a.corePath, args...)
 
@@ -138,9 +175,145 @@ func (a *pulumiCostAdapter) GetActualCostWithGranularity(ctx context.Context, st
 		return nil, fmt.Errorf("failed to parse pulumicost output: %w", err)
 	}
 
+	stampAdapter(result.Resources, adapterName)
+
 	return &result, nil
 }
 
+// CoreSource names pulumicost-core as the source of recommendations it produced itself
+const CoreSource = "pulumicost-core"
+
+// GetRecommendations retrieves optimization recommendations for a stack
+func (a *pulumiCostAdapter) GetRecommendations(ctx context.Context, stackName string) (*RecommendationResult, error) {
+	return a.GetRecommendationsWithAdapter(ctx, stackName, "")
+}
+
+// GetRecommendationsWithAdapter retrieves optimization recommendations from a
+// specific plugin. An empty adapterName lets pulumicost-core produce them.
+func (a *pulumiCostAdapter) GetRecommendationsWithAdapter(ctx context.Context, stackName string, adapterName string) (*RecommendationResult, error) {
+	if stackName == "" {
+		return nil, fmt.Errorf("%w: stack name cannot be empty", ErrInvalidInput)
+	}
+
+	// Prepare command with timeout
+	cmdCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	args := []string{"recommend", "--stack", stackName}
+	if adapterName != "" {
+		args = append(args, "--adapter", adapterName)
+	}
+
+	cmd := exec.CommandContext(cmdCtx, a.corePath, args...)
+
+	// Capture output
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// Execute command
+	if err := cmd.Run(); err != nil {
+		if cmdCtx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("pulumicost timeout: %w", cmdCtx.Err())
+		}
+		if cmdCtx.Err() == context.Canceled {
+			return nil, fmt.Errorf("context canceled: %w", cmdCtx.Err())
+		}
+		return nil, fmt.Errorf("pulumicost execution failed: %w (stderr: %s)", err, stderr.String())
+	}
+
+	// Parse output
+	var result RecommendationResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse pulumicost output: %w", err)
+	}
+
+	source := adapterName
+	if source == "" {
+		source = CoreSource
+	}
+	for i := range result.Recommendations {
+		if result.Recommendations[i].Source == "" {
+			result.Recommendations[i].Source = source
+		}
+	}
+
+	return &result, nil
+}
+
+// GetForecast forecasts a stack's costs over a future period, one data point
+// per granularity step, with bounds at the given confidence level. An empty
+// adapterName lets pulumicost-core produce the forecast.
+func (a *pulumiCostAdapter) GetForecast(ctx context.Context, stackName string, period TimeRange, granularity string, confidence float64, adapterName string) (*ForecastResult, error) {
+	if stackName == "" {
+		return nil, fmt.Errorf("%w: stack name cannot be empty", ErrInvalidInput)
+	}
+
+	// Prepare command with timeout
+	cmdCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	args := []string{
+		"forecast",
+		"--stack", stackName,
+		"--start", period.Start,
+		"--end", period.End,
+		"--granularity", granularity,
+		"--confidence", strconv.FormatFloat(confidence, 'f', -1, 64),
+	}
+	if adapterName != "" {
+		args = append(args, "--adapter", adapterName)
+	}
+
+	cmd := exec.CommandContext(cmdCtx, a.corePath, args...)
+
+	// Capture output
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// Execute command
+	if err := cmd.Run(); err != nil {
+		if cmdCtx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("pulumicost timeout: %w", cmdCtx.Err())
+		}
+		if cmdCtx.Err() == context.Canceled {
+			return nil, fmt.Errorf("context canceled: %w", cmdCtx.Err())
+		}
+		if unsupportedOutput(stderr.String()) {
+			return nil, fmt.Errorf("%w: %s", ErrForecastUnsupported, strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("pulumicost execution failed: %w (stderr: %s)", err, stderr.String())
+	}
+
+	// Parse output
+	var result ForecastResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse pulumicost output: %w", err)
+	}
+	if len(result.DataPoints) == 0 {
+		return nil, fmt.Errorf("%w: forecast returned no data points", ErrForecastUnsupported)
+	}
+
+	if result.Source == "" {
+		result.Source = adapterName
+		if result.Source == "" {
+			result.Source = CoreSource
+		}
+	}
+
+	return &result, nil
+}
+
+// unsupportedOutput reports whether pulumicost-core's error output says the
+// command or the plugin's RPC does not exist, rather than that it failed
+func unsupportedOutput(stderr string) bool {
+	stderr = strings.ToLower(stderr)
+	return strings.Contains(stderr, "unknown command") ||
+		strings.Contains(stderr, "unimplemented") ||
+		strings.Contains(stderr, "not supported")
+}
+
 // CostResult represents the result of a cost analysis
 type CostResult struct {
 	TotalMonthly float64         `json:"total_monthly"`
@@ -159,7 +332,154 @@ type ResourceCost struct {
 	MonthlyCost float64           `json:"monthly_cost"`
 	HourlyCost  *float64          `json:"hourly_cost,omitempty"`
 	Region      *string           `json:"region,omitempty"`
+	Adapter     *string           `json:"adapter,omitempty"`
+	// BillingMode is how the resource is billed, e.g. "on_demand", "reserved" or "spot"
+	BillingMode *string           `json:"billing_mode,omitempty"`
 	Tags        map[string]string `json:"tags,omitempty"`
+	// DataPoints are the resource's actual costs and usage over time, if reported
+	DataPoints []ActualCostDataPoint `json:"data_points,omitempty"`
+}
+
+// ActualCostDataPoint is a resource's actual cost and usage for one period
+type ActualCostDataPoint struct {
+	Timestamp   string            `json:"timestamp"`
+	Cost        float64           `json:"cost"`
+	UsageAmount *float64          `json:"usage_amount,omitempty"`
+	UsageUnit   string            `json:"usage_unit,omitempty"`
+	Currency    string            `json:"currency,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+// ProviderName returns the resource's provider, falling back to the package
+// of its type such as "aws:ec2/instance:Instance"
+func (r *ResourceCost) ProviderName() string {
+	if r.Provider != nil && *r.Provider != "" {
+		return strings.ToLower(*r.Provider)
+	}
+	provider, _, _ := strings.Cut(r.Type, ":")
+	return strings.ToLower(provider)
+}
+
+// Usage sums the cost and usage of a resource's data points. measured is
+// false if no data point reports usage or the points mix usage units.
+func (r *ResourceCost) Usage() (cost, usage float64, unit string, measured bool) {
+	mixed := false
+	for _, point := range r.DataPoints {
+		cost += point.Cost
+		if point.UsageAmount == nil {
+			continue
+		}
+		if !measured {
+			unit = point.UsageUnit
+			measured = true
+		} else if point.UsageUnit != unit {
+			mixed = true
+		}
+		usage += *point.UsageAmount
+	}
+	if mixed {
+		return cost, 0, "", false
+	}
+	return cost, usage, unit, measured
+}
+
+// RecommendationResult is the output of "pulumicost recommend"
+type RecommendationResult struct {
+	Recommendations []Recommendation `json:"recommendations"`
+}
+
+// Recommendation is a cost optimization suggestion for one or more resources
+type Recommendation struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// Category is accepted in place of Type, e.g. "reserved_instance" or "cleanup"
+	Category             string   `json:"category,omitempty"`
+	ResourceUrn          string   `json:"resource_urn"`
+	ResourceUrns         []string `json:"resource_urns,omitempty"`
+	CurrentCost          *float64 `json:"current_cost,omitempty"`
+	ProjectedSavings     float64  `json:"projected_savings"`
+	Confidence           string   `json:"confidence,omitempty"`
+	RiskLevel            string   `json:"risk_level,omitempty"`
+	ImplementationEffort string   `json:"implementation_effort,omitempty"`
+	Description          string   `json:"description"`
+	ActionSteps          []string `json:"action_steps,omitempty"`
+	// Source is the plugin that produced the recommendation, or CoreSource
+	Source string `json:"source,omitempty"`
+	// BreakEven is reported for reservation and savings plan recommendations
+	BreakEven *BreakEven `json:"break_even,omitempty"`
+}
+
+// BreakEven describes when a commitment pays for itself compared to on-demand
+type BreakEven struct {
+	Term            string   `json:"term,omitempty"`
+	UpfrontCost     *float64 `json:"upfront_cost,omitempty"`
+	BreakEvenMonths float64  `json:"break_even_months"`
+}
+
+// UnmarshalJSON also accepts the OptimizationRecommendation field names:
+// resources, estimated_monthly_savings, action_items and title. The native
+// names win when both are given; title is used when there is no description.
+func (r *Recommendation) UnmarshalJSON(data []byte) error {
+	type recommendation Recommendation
+	var aux struct {
+		recommendation
+		Resources               []string `json:"resources"`
+		EstimatedMonthlySavings *float64 `json:"estimated_monthly_savings"`
+		ActionItems             []string `json:"action_items"`
+		Title                   string   `json:"title"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*r = Recommendation(aux.recommendation)
+	if len(r.ResourceUrns) == 0 {
+		r.ResourceUrns = aux.Resources
+	}
+	if r.ProjectedSavings == 0 && aux.EstimatedMonthlySavings != nil {
+		r.ProjectedSavings = *aux.EstimatedMonthlySavings
+	}
+	if len(r.ActionSteps) == 0 {
+		r.ActionSteps = aux.ActionItems
+	}
+	if r.Description == "" {
+		r.Description = aux.Title
+	}
+	return nil
+}
+
+// URNs returns every resource the recommendation affects, ResourceUrn first
+func (r *Recommendation) URNs() []string {
+	var urns []string
+	if r.ResourceUrn != "" {
+		urns = append(urns, r.ResourceUrn)
+	}
+	for _, urn := range r.ResourceUrns {
+		if urn != "" && urn != r.ResourceUrn {
+			urns = append(urns, urn)
+		}
+	}
+	return urns
+}
+
+// ForecastResult is the output of "pulumicost forecast"
+type ForecastResult struct {
+	DataPoints  []ForecastPoint `json:"data_points"`
+	Methodology string          `json:"methodology"`
+	Currency    string          `json:"currency,omitempty"`
+	// HistoryStart and HistoryEnd bound the actual costs the forecast is based on
+	HistoryStart string `json:"history_start"`
+	HistoryEnd   string `json:"history_end"`
+	// Source is the plugin that produced the forecast, or CoreSource
+	Source string `json:"source,omitempty"`
+}
+
+// ForecastPoint is the predicted cost of one period with its confidence interval
+type ForecastPoint struct {
+	Timestamp     string  `json:"timestamp"`
+	PredictedCost float64 `json:"predicted_cost"`
+	LowerBound    float64 `json:"lower_bound"`
+	UpperBound    float64 `json:"upper_bound"`
 }
 
 // ResourceFilters specifies criteria for filtering resources
@@ -261,6 +581,20 @@ func matchesFilter(resource ResourceCost, filters *ResourceFilters) bool {
 	return true
 }
 
+// stampAdapter records which plugin priced each resource when pulumicost-core
+// did not report it itself
+func stampAdapter(resources []ResourceCost, adapterName string) {
+	if adapterName == "" {
+		return
+	}
+	for i := range resources {
+		if resources[i].Adapter == nil {
+			name := adapterName
+			resources[i].Adapter = &name
+		}
+	}
+}
+
 // calculateTotal sums up the monthly costs of all resources
 func calculateTotal(resources []ResourceCost) float64 {
 	total := 0.0
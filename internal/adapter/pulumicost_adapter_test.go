@@ -2,6 +2,7 @@ package adapter
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -304,3 +305,101 @@ func TestGetActualCost_WithMultipleGranularities(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+// TestGetRecommendations verifies each recommendation records the source that produced it
+func TestGetRecommendations(t *testing.T) {
+	adapter := NewPulumiCostAdapter("./testdata/mock_pulumicost.sh")
+	ctx := context.Background()
+
+	result, err := adapter.GetRecommendations(ctx, "myapp-dev")
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Recommendations)
+	for _, rec := range result.Recommendations {
+		assert.Equal(t, CoreSource, rec.Source)
+	}
+	assert.Nil(t, result.Recommendations[1].CurrentCost)
+	assert.Len(t, result.Recommendations[3].URNs(), 3, "resource_urns without a resource_urn")
+
+	result, err = adapter.GetRecommendationsWithAdapter(ctx, "myapp-dev", "aws-optimizer")
+	require.NoError(t, err)
+	assert.Equal(t, "aws-optimizer", result.Recommendations[0].Source)
+
+	_, err = adapter.GetRecommendations(ctx, "")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+// TestRecommendation_Aliases verifies OptimizationRecommendation field names are accepted
+func TestRecommendation_Aliases(t *testing.T) {
+	var rec Recommendation
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "stop-dev",
+		"category": "scheduling",
+		"title": "Stop dev at night",
+		"resources": ["urn:a", "urn:b"],
+		"estimated_monthly_savings": 8.5,
+		"action_items": ["Add a schedule"]
+	}`), &rec))
+	assert.Equal(t, "stop-dev", rec.ID)
+	assert.Equal(t, "scheduling", rec.Category)
+	assert.Equal(t, "Stop dev at night", rec.Description)
+	assert.Equal(t, []string{"urn:a", "urn:b"}, rec.URNs())
+	assert.Equal(t, 8.5, rec.ProjectedSavings)
+	assert.Equal(t, []string{"Add a schedule"}, rec.ActionSteps)
+
+	rec = Recommendation{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"description": "native", "title": "alias",
+		"projected_savings": 3, "estimated_monthly_savings": 9,
+		"resource_urns": ["urn:a"], "resources": ["urn:b"]
+	}`), &rec))
+	assert.Equal(t, "native", rec.Description, "native names win")
+	assert.Equal(t, 3.0, rec.ProjectedSavings)
+	assert.Equal(t, []string{"urn:a"}, rec.ResourceUrns)
+}
+
+func TestGetForecast(t *testing.T) {
+	adapter := NewPulumiCostAdapter("./testdata/mock_pulumicost.sh")
+	ctx := context.Background()
+	period := TimeRange{Start: "2024-04-01T00:00:00Z", End: "2024-04-04T00:00:00Z"}
+
+	result, err := adapter.GetForecast(ctx, "myapp-dev", period, "daily", 0.8, "")
+	require.NoError(t, err)
+	assert.Len(t, result.DataPoints, 3)
+	assert.Equal(t, CoreSource, result.Source)
+	assert.Contains(t, result.Methodology, "daily points at 0.8 confidence")
+	assert.Equal(t, "2024-01-01T00:00:00Z", result.HistoryStart)
+
+	result, err = adapter.GetForecast(ctx, "myapp-dev", period, "daily", 0.8, "aws-forecaster")
+	require.NoError(t, err)
+	assert.Equal(t, "aws-forecaster", result.Source)
+
+	_, err = adapter.GetForecast(ctx, "legacy", period, "daily", 0.8, "")
+	assert.ErrorIs(t, err, ErrForecastUnsupported)
+
+	_, err = adapter.GetForecast(ctx, "", period, "daily", 0.8, "")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+// TestResourceCostUsage verifies usage is summed only when data points share a unit
+func TestResourceCostUsage(t *testing.T) {
+	amount := func(v float64) *float64 { return &v }
+
+	res := ResourceCost{DataPoints: []ActualCostDataPoint{
+		{Cost: 1, UsageAmount: amount(2), UsageUnit: "GB"},
+		{Cost: 1},
+		{Cost: 1, UsageAmount: amount(3), UsageUnit: "GB"},
+	}}
+	cost, usage, unit, measured := res.Usage()
+	assert.Equal(t, 3.0, cost)
+	assert.Equal(t, 5.0, usage)
+	assert.Equal(t, "GB", unit)
+	assert.True(t, measured)
+
+	res.DataPoints = append(res.DataPoints, ActualCostDataPoint{Cost: 1, UsageAmount: amount(1), UsageUnit: "Hrs"})
+	cost, _, _, measured = res.Usage()
+	assert.Equal(t, 4.0, cost)
+	assert.False(t, measured, "mixed units")
+
+	_, _, _, measured = (&ResourceCost{}).Usage()
+	assert.False(t, measured)
+}
@@ -0,0 +1,219 @@
+package adapter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version. Build metadata is ignored.
+type Version struct {
+	Major, Minor, Patch int
+	Prerelease          string
+}
+
+// String formats the version without a "v" prefix
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// ParseVersion parses a semantic version such as "1.2.3", "v1.2.3" or
+// "1.2.3-beta.1". Missing minor and patch components are not accepted.
+func ParseVersion(s string) (Version, error) {
+	raw := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	raw, _, _ = strings.Cut(raw, "+")
+
+	var v Version
+	core, prerelease, hasPrerelease := strings.Cut(raw, "-")
+	if hasPrerelease {
+		if prerelease == "" {
+			return Version{}, fmt.Errorf("invalid semantic version %q: empty prerelease", s)
+		}
+		v.Prerelease = prerelease
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid semantic version %q: expected MAJOR.MINOR.PATCH", s)
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (len(part) > 1 && part[0] == '0') {
+			return Version{}, fmt.Errorf("invalid semantic version %q: bad component %q", s, part)
+		}
+		*nums[i] = n
+	}
+
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 as v is lower than, equal to or higher than o.
+// A prerelease sorts before the release it precedes.
+func (v Version) Compare(o Version) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	}
+	return comparePrerelease(v.Prerelease, o.Prerelease)
+}
+
+// comparePrerelease orders dot-separated prerelease identifiers per semver:
+// numeric identifiers compare numerically and sort before alphanumeric ones
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+// VersionRange is a set of version constraints. Comparators separated by
+// spaces must all hold; alternatives are separated by "||". Supported
+// operators are =, >, >=, <, <=, ^ (same major, or same minor below 1.0.0)
+// and ~ (same minor), e.g. ">=0.1.0 <0.3.0" or "^0.2.0 || ^1.0.0".
+type VersionRange struct {
+	raw          string
+	alternatives [][]comparator
+}
+
+type comparator struct {
+	op      string
+	version Version
+}
+
+// String returns the range as written
+func (r VersionRange) String() string {
+	return r.raw
+}
+
+// ParseVersionRange parses a version range expression
+func ParseVersionRange(s string) (VersionRange, error) {
+	r := VersionRange{raw: strings.TrimSpace(s)}
+	if r.raw == "" {
+		return VersionRange{}, fmt.Errorf("empty version range")
+	}
+
+	for _, alternative := range strings.Split(r.raw, "||") {
+		fields := strings.Fields(alternative)
+		if len(fields) == 0 {
+			return VersionRange{}, fmt.Errorf("invalid version range %q: empty alternative", s)
+		}
+
+		var comparators []comparator
+		for _, field := range fields {
+			c, err := parseComparator(field)
+			if err != nil {
+				return VersionRange{}, fmt.Errorf("invalid version range %q: %w", s, err)
+			}
+			comparators = append(comparators, c...)
+		}
+		r.alternatives = append(r.alternatives, comparators)
+	}
+
+	return r, nil
+}
+
+// parseComparator expands one constraint into primitive comparisons
+func parseComparator(field string) ([]comparator, error) {
+	op := ""
+	for _, candidate := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(field, candidate) {
+			op = candidate
+			break
+		}
+	}
+
+	v, err := ParseVersion(strings.TrimPrefix(field, op))
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "^":
+		upper := Version{Major: v.Major + 1}
+		if v.Major == 0 {
+			upper = Version{Minor: v.Minor + 1}
+		}
+		return []comparator{{">=", v}, {"<", upper}}, nil
+	case "~":
+		return []comparator{{">=", v}, {"<", Version{Major: v.Major, Minor: v.Minor + 1}}}, nil
+	case "":
+		op = "="
+	}
+	return []comparator{{op, v}}, nil
+}
+
+// Contains reports whether v satisfies the range
+func (r VersionRange) Contains(v Version) bool {
+	for _, comparators := range r.alternatives {
+		if allHold(comparators, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func allHold(comparators []comparator, v Version) bool {
+	for _, c := range comparators {
+		cmp := v.Compare(c.version)
+		var ok bool
+		switch c.op {
+		case "=":
+			ok = cmp == 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package adapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseVersion verifies semantic version parsing and ordering
+func TestParseVersion(t *testing.T) {
+	v, err := ParseVersion("v1.2.3-beta.1+build.5")
+	require.NoError(t, err)
+	assert.Equal(t, Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "beta.1"}, v)
+	assert.Equal(t, "1.2.3-beta.1", v.String())
+
+	for _, invalid := range []string{"", "1.2", "1.2.3.4", "1.02.3", "a.b.c", "1.2.3-"} {
+		_, err := ParseVersion(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	ordered := []string{"0.9.0", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0", "1.0.1", "1.10.0"}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, err := ParseVersion(ordered[i])
+		require.NoError(t, err)
+		b, err := ParseVersion(ordered[i+1])
+		require.NoError(t, err)
+		assert.Equal(t, -1, a.Compare(b), "%s < %s", ordered[i], ordered[i+1])
+		assert.Equal(t, 1, b.Compare(a), "%s > %s", ordered[i+1], ordered[i])
+	}
+}
+
+// TestVersionRange verifies range operators and alternatives
+func TestVersionRange(t *testing.T) {
+	tests := []struct {
+		rng      string
+		contains []string
+		excludes []string
+	}{
+		{rng: ">=0.1.0 <0.3.0", contains: []string{"0.1.0", "0.2.9"}, excludes: []string{"0.0.9", "0.3.0"}},
+		{rng: "^0.2.0", contains: []string{"0.2.0", "0.2.5"}, excludes: []string{"0.3.0", "0.1.9"}},
+		{rng: "^1.2.0", contains: []string{"1.2.0", "1.9.0"}, excludes: []string{"2.0.0", "1.1.0"}},
+		{rng: "~1.2.0", contains: []string{"1.2.7"}, excludes: []string{"1.3.0"}},
+		{rng: "0.1.0", contains: []string{"0.1.0"}, excludes: []string{"0.1.1"}},
+		{rng: "^0.1.0 || ^1.0.0", contains: []string{"0.1.4", "1.5.0"}, excludes: []string{"0.2.0", "2.0.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rng, func(t *testing.T) {
+			r, err := ParseVersionRange(tt.rng)
+			require.NoError(t, err)
+			for _, s := range tt.contains {
+				v, err := ParseVersion(s)
+				require.NoError(t, err)
+				assert.True(t, r.Contains(v), "%s should satisfy %s", s, tt.rng)
+			}
+			for _, s := range tt.excludes {
+				v, err := ParseVersion(s)
+				require.NoError(t, err)
+				assert.False(t, r.Contains(v), "%s should not satisfy %s", s, tt.rng)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"", ">=banana", "^0.1.0 ||", "=>0.1.0"} {
+		_, err := ParseVersionRange(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -2,111 +2,884 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/rshade/pulumicost-mcp/gen/plugin"
 	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
+// Conformance suite limits
+const (
+	conformanceStartupTimeout = 10 * time.Second
+	conformanceCallTimeout    = 5 * time.Second
+	conformanceLatencyBudget  = 500 * time.Millisecond
+	conformanceLatencySamples = 20
+	conformanceConcurrency    = 16
+	// conformanceOversizedTags is the tag count of the resource limits request (~1MB)
+	conformanceOversizedTags = 1024
+)
+
+// conformanceFixtures are the resources each provider's plugins are probed
+// with. A plugin must support at least one fixture of every provider it
+// declares.
+var conformanceFixtures = map[string][]resourceDescriptor{
+	"aws": {
+		{Provider: "aws", ResourceType: "aws:ec2/instance:Instance", SKU: "t3.micro", Region: "us-east-1"},
+		{Provider: "aws", ResourceType: "aws:s3/bucket:Bucket", Region: "us-east-1"},
+		{Provider: "aws", ResourceType: "aws:rds/instance:Instance", SKU: "db.t3.micro", Region: "us-east-1"},
+		{Provider: "aws", ResourceType: "aws:lambda/function:Function", Region: "us-east-1"},
+	},
+	"azure": {
+		{Provider: "azure", ResourceType: "azure-native:compute:VirtualMachine", SKU: "Standard_B1s", Region: "eastus"},
+		{Provider: "azure", ResourceType: "azure-native:storage:StorageAccount", SKU: "Standard_LRS", Region: "eastus"},
+	},
+	"gcp": {
+		{Provider: "gcp", ResourceType: "gcp:compute/instance:Instance", SKU: "e2-micro", Region: "us-central1"},
+		{Provider: "gcp", ResourceType: "gcp:storage/bucket:Bucket", SKU: "STANDARD", Region: "us-central1"},
+	},
+	"kubernetes": {
+		{Provider: "kubernetes", ResourceType: "kubernetes:apps/v1:Deployment"},
+	},
+}
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
 // SpecAdapter handles plugin conformance validation using pulumicost-spec
 type SpecAdapter struct {
-	logger *logging.Logger
+	plugins        *PluginAdapter
+	logger         *logging.Logger
+	startupTimeout time.Duration
+	latencyBudget  time.Duration
 }
 
 // NewSpecAdapter creates a new spec adapter
 func NewSpecAdapter(logger *logging.Logger) *SpecAdapter {
+	return NewSpecAdapterWithPlugins(nil, logger)
+}
+
+// NewSpecAdapterWithPlugins creates a spec adapter that validates plugins
+// installed in the plugin adapter's directory over their gRPC connections
+func NewSpecAdapterWithPlugins(plugins *PluginAdapter, logger *logging.Logger) *SpecAdapter {
 	if logger == nil {
 		logger = logging.Default()
 	}
 	return &SpecAdapter{
-		logger: logger,
+		plugins:        plugins,
+		logger:         logger,
+		startupTimeout: conformanceStartupTimeout,
+		latencyBudget:  conformanceLatencyBudget,
 	}
 }
 
-// ValidatePlugin runs conformance tests against a plugin (T062)
-func (a *SpecAdapter) ValidatePlugin(ctx context.Context, p *plugin.Plugin, conformanceLevel string) (*plugin.PluginValidationReport, error) {
-	a.logger.Info("validating plugin", "name", p.Name, "level", conformanceLevel)
+// conformanceTarget is a live plugin under test
+type conformanceTarget struct {
+	plugin *plugin.Plugin
+	conn   *grpc.ClientConn
+	client *costSourceClient
+}
+
+// conformanceTest is a single named check against a live plugin
+type conformanceTest struct {
+	name string
+	run  func(ctx context.Context, t *conformanceTarget) error
+	// costs, if set, is filled in by run with the costs it compared
+	costs *costComparison
+}
 
-	// Validate conformance level
-	validLevels := map[string]bool{
-		"basic":    true,
-		"standard": true,
-		"advanced": true,
+// ValidatePlugin runs conformance tests against an installed plugin (T062).
+// Higher levels include the suites of lower ones, and each suite only runs
+// once the suites below it pass. STANDARD and above also check the plugin's
+// costs against the baseline fixtures, those in its fixtures directory and
+// the given fixtures.
+func (a *SpecAdapter) ValidatePlugin(ctx context.Context, p *plugin.Plugin, level ConformanceLevel, fixtures []CostFixture) (*plugin.PluginValidationReport, error) {
+	if _, known := conformanceLevelNames[level]; !known {
+		return nil, &InvalidConformanceLevelError{Level: level.String()}
 	}
 
-	if !validLevels[conformanceLevel] {
-		report := &plugin.PluginValidationReport{
-			PluginName:       p.Name,
-			ConformanceLevel: conformanceLevel,
-			Passed:           false,
+	a.logger.Info("validating plugin", "name", p.Name, "level", level)
+
+	var pluginDir string
+	if a.plugins != nil && validPluginName(p.Name) {
+		pluginDir = filepath.Join(a.plugins.pluginDir, p.Name)
+	}
+	name, results := a.runSuites(ctx, p.Name, level, pluginDir, fixtures, func(ctx context.Context) (*conformanceTarget, error) {
+		return a.connect(ctx, p)
+	})
+	return a.report(name, level, results), nil
+}
+
+// ValidatePath validates a plugin directory or bare binary before it is
+// installed. The plugin is launched in a temporary working directory on a
+// fresh loopback port, tested and stopped again; a "basic/shutdown" result
+// records whether it exited on SIGTERM. A path that does not exist but names
+// an installed plugin validates that plugin instead. With a plugin adapter,
+// its launch options and integrity mode decide whether the path may be launched.
+func (a *SpecAdapter) ValidatePath(ctx context.Context, path string, level ConformanceLevel, fixtures []CostFixture) (*plugin.PluginValidationReport, error) {
+	if _, known := conformanceLevelNames[level]; !known {
+		return nil, &InvalidConformanceLevelError{Level: level.String()}
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) && a.plugins != nil && validPluginName(path) {
+		if _, err := a.plugins.DescribePlugin(ctx, path); err == nil {
+			return a.ValidatePlugin(ctx, &plugin.Plugin{Name: path}, level, fixtures)
 		}
-		return report, fmt.Errorf("invalid conformance level: %s", conformanceLevel)
 	}
 
-	// Initialize validation report
-	report := &plugin.PluginValidationReport{
-		PluginName:       p.Name,
-		ConformanceLevel: conformanceLevel,
-		Passed:           false,
+	binary, err := resolvePluginBinary(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.admitLaunch(binary); err != nil {
+		return nil, err
+	}
+
+	return a.validateLaunch(ctx, binary, level, fixtures)
+}
+
+// validateLaunch launches an admitted plugin binary, runs the suites level
+// includes against it and stops it again
+func (a *SpecAdapter) validateLaunch(ctx context.Context, binary *pluginBinary, level ConformanceLevel, fixtures []CostFixture) (*plugin.PluginValidationReport, error) {
+	name := filepath.Base(binary.path)
+	if binary.meta != nil {
+		name = binary.meta.Name
+	}
+	a.logger.Info("validating plugin binary", "name", name, "binary", binary.path, "level", level)
+
+	launched, err := launchPlugin(binary.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var conn *grpc.ClientConn
+	name, results := a.runSuites(ctx, name, level, binary.dir, fixtures, func(ctx context.Context) (*conformanceTarget, error) {
+		conn, err = grpc.NewClient(launched.address,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			// Poll quickly while the freshly started plugin begins listening
+			grpc.WithConnectParams(grpc.ConnectParams{
+				Backoff:           backoff.Config{BaseDelay: 50 * time.Millisecond, Multiplier: 1.6, MaxDelay: time.Second},
+				MinConnectTimeout: time.Second,
+			}))
+		if err != nil {
+			return nil, fmt.Errorf("create client for %s: %w", launched.address, err)
+		}
+		return a.launchedTarget(ctx, launched, conn, name, binary.meta)
+	})
+	if conn != nil {
+		_ = conn.Close()
 	}
 
-	// In a full implementation, this would:
-	// 1. Start the plugin process
-	// 2. Run pulumicost-spec conformance test suite
-	// 3. Collect test results
-	// 4. Generate detailed report
-	//
-	// For now, we'll return a basic report structure that indicates
-	// the plugin would need to be running for actual validation
+	running := launched.exited() == nil
+	start := time.Now()
+	stopErr := launched.stop()
+	if running {
+		shutdown := TestResult{Name: "basic/shutdown", Passed: stopErr == nil, Duration: time.Since(start).Milliseconds()}
+		if stopErr != nil {
+			shutdown.Error = stopErr.Error()
+		}
+		results = append(results, shutdown)
+	}
 
-	a.logger.Warn("spec validation not yet fully implemented",
-		"plugin", p.Name,
-		"note", "requires pulumicost-spec integration")
+	return a.report(name, level, results), nil
+}
 
-	report.Passed = false
+// admitLaunch checks a plugin binary against the plugin adapter's launch
+// options and integrity mode before it is executed. Without a plugin adapter,
+// as on the command line, any binary may be launched.
+func (a *SpecAdapter) admitLaunch(binary *pluginBinary) error {
+	if a.plugins == nil {
+		return nil
+	}
+
+	options := a.plugins.options
+	if !options.Launch.Allow {
+		return fmt.Errorf("%w: launching plugins by path is disabled", ErrLaunchForbidden)
+	}
+	roots := append([]string{a.plugins.pluginDir}, options.Launch.Roots...)
+	if !withinRoots(binary.path, roots) {
+		return fmt.Errorf("%w: %s is outside the plugin directory and the configured roots", ErrLaunchForbidden, binary.path)
+	}
 
-	return report, fmt.Errorf("plugin validation requires running plugin server (not yet implemented)")
+	if mode := options.Integrity.Mode; mode == "" || mode == IntegrityOff {
+		return nil
+	}
+	meta := binary.meta
+	result := IntegrityResult{Status: IntegrityUnsigned, Reason: "plugin binary has no plugin.json"}
+	if meta == nil {
+		meta = &pluginMetadata{Name: filepath.Base(binary.path)}
+	} else if rel, err := filepath.Rel(binary.dir, binary.path); err != nil || !meta.checksummed(rel) {
+		result = IntegrityResult{Status: IntegrityFailed, Reason: fmt.Sprintf("plugin binary %s is not covered by checksums", filepath.Base(binary.path))}
+	} else {
+		result = a.plugins.verifyIntegrity(meta, binary.dir)
+	}
+	if err := a.plugins.enforceIntegrity(meta, result); err != nil {
+		return fmt.Errorf("%w: %w", ErrLaunchForbidden, err)
+	}
+	return nil
 }
 
-// RunBasicTests runs basic conformance tests
-func (a *SpecAdapter) RunBasicTests(ctx context.Context, p *plugin.Plugin) ([]TestResult, error) {
-	// Basic tests would verify:
-	// - Plugin starts successfully
-	// - Health check responds
-	// - GetCapabilities returns valid response
-	// - Plugin stops gracefully
+// runSuites connects to a plugin and runs the suites level includes. A
+// connection failure is reported as a failed "basic/startup" test. It returns
+// the plugin name, which connect may have learned from the plugin itself.
+// pluginDir, if set, is searched for the plugin's own cost fixtures.
+func (a *SpecAdapter) runSuites(ctx context.Context, name string, level ConformanceLevel, pluginDir string, fixtures []CostFixture, connect func(ctx context.Context) (*conformanceTarget, error)) (string, []TestResult) {
+	start := time.Now()
+	target, err := connect(ctx)
+	if err != nil {
+		return name, []TestResult{{
+			Name:     "basic/startup",
+			Duration: time.Since(start).Milliseconds(),
+			Error:    err.Error(),
+		}}
+	}
 
-	return []TestResult{}, fmt.Errorf("not implemented")
+	suites := [][]conformanceTest{a.basicTests()}
+	if level.Includes(ConformanceStandard) {
+		fixtures, err := a.costFixtures(pluginDir, fixtures)
+		suites = append(suites, append(a.standardTests(target), a.accuracyTests(target, fixtures, err)...))
+	}
+	if level.Includes(ConformanceFull) {
+		suites = append(suites, a.fullTests())
+	}
+
+	var results []TestResult
+	for _, suite := range suites {
+		suiteResults := a.runTests(ctx, target, suite)
+		results = append(results, suiteResults...)
+		if failed := failedTests(suiteResults); len(failed) > 0 {
+			a.logger.Warn("conformance suite failed, skipping higher levels",
+				"plugin", target.plugin.Name, "failed", strings.Join(failed, ", "))
+			break
+		}
+	}
+	return target.plugin.Name, results
+}
+
+// report summarizes test results
+func (a *SpecAdapter) report(name string, level ConformanceLevel, results []TestResult) *plugin.PluginValidationReport {
+	timestamp := time.Now().Format(time.RFC3339)
+	report := &plugin.PluginValidationReport{
+		PluginName:       name,
+		ConformanceLevel: level.String(),
+		Passed:           len(failedTests(results)) == 0,
+		Timestamp:        &timestamp,
+	}
+	for _, r := range results {
+		report.TestResults = append(report.TestResults, r.toAPI())
+	}
+
+	a.logger.Info("plugin validation completed",
+		"plugin", name,
+		"level", level,
+		"passed", report.Passed,
+		"tests", len(results))
+
+	return report
+}
+
+// RunBasicTests runs the BASIC conformance tests: the plugin starts and accepts
+// connections, reports SERVING to health checks and describes itself
+func (a *SpecAdapter) RunBasicTests(ctx context.Context, p *plugin.Plugin) ([]TestResult, error) {
+	target, err := a.connect(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return a.runTests(ctx, target, a.basicTests()), nil
 }
 
-// RunStandardTests runs standard conformance tests
+// RunStandardTests runs the tests STANDARD conformance adds: projected and actual cost
+// responses are well formed, invalid requests get the right gRPC codes and
+// Supports answers match the fixture resource types
 func (a *SpecAdapter) RunStandardTests(ctx context.Context, p *plugin.Plugin) ([]TestResult, error) {
-	// Standard tests would verify:
-	// - All basic tests pass
-	// - GetProjectedCost returns valid data
-	// - GetActualCost returns valid data
-	// - Error handling is correct
-	// - Resource type support is accurate
+	target, err := a.connect(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return a.runTests(ctx, target, a.standardTests(target)), nil
+}
+
+// RunFullTests runs the tests FULL conformance adds: concurrent requests,
+// latency budgets and oversized requests
+func (a *SpecAdapter) RunFullTests(ctx context.Context, p *plugin.Plugin) ([]TestResult, error) {
+	target, err := a.connect(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return a.runTests(ctx, target, a.fullTests()), nil
+}
+
+// connect opens the plugin adapter's connection to an installed plugin
+func (a *SpecAdapter) connect(ctx context.Context, p *plugin.Plugin) (*conformanceTarget, error) {
+	if a.plugins == nil {
+		return nil, fmt.Errorf("no plugin directory configured for validation")
+	}
+
+	if err := a.plugins.EstablishConnection(ctx, p); err != nil {
+		return nil, fmt.Errorf("connect to plugin %s: %w", p.Name, err)
+	}
+	conn, ok := a.plugins.connection(p.Name)
+	if !ok {
+		return nil, fmt.Errorf("connect to plugin %s: connection closed", p.Name)
+	}
+
+	target := &conformanceTarget{plugin: p, conn: conn, client: newCostSourceClient(conn)}
+	if target.plugin.Capabilities == nil {
+		capabilities, err := a.plugins.GetPluginCapabilities(ctx, p)
+		if err != nil {
+			return nil, fmt.Errorf("load plugin capabilities: %w", err)
+		}
+		target.plugin = &plugin.Plugin{Name: p.Name, Version: p.Version, Capabilities: capabilities}
+	}
+
+	return target, nil
+}
+
+// launchedTarget waits for a launched plugin to accept connections, giving up
+// early if the process exits, and describes it from plugin.json or, for a
+// bare binary, from its answers
+func (a *SpecAdapter) launchedTarget(ctx context.Context, launched *launchedPlugin, conn *grpc.ClientConn, name string, meta *pluginMetadata) (*conformanceTarget, error) {
+	target := &conformanceTarget{plugin: &plugin.Plugin{Name: name}, conn: conn, client: newCostSourceClient(conn)}
+
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-launched.done:
+			cancel()
+		case <-waitCtx.Done():
+		}
+	}()
+
+	if err := a.testStartup(waitCtx, target); err != nil {
+		if exitErr := launched.exited(); exitErr != nil {
+			return nil, exitErr
+		}
+		return nil, err
+	}
+
+	if meta != nil {
+		target.plugin.Version = meta.Version
+		target.plugin.Capabilities = meta.capabilities()
+		return target, nil
+	}
+
+	target.plugin = inferPlugin(ctx, target.client, name)
+	a.logger.Info("inferred plugin capabilities",
+		"plugin", target.plugin.Name,
+		"providers", target.plugin.Capabilities.SupportsProviders,
+		"projected", target.plugin.Capabilities.SupportsProjected,
+		"actual", target.plugin.Capabilities.SupportsActual)
+	return target, nil
+}
+
+// inferPlugin describes a plugin that has no plugin.json: its name comes from
+// the Name RPC, its providers from the fixtures it supports and its cost kinds
+// from whether the cost RPCs are implemented
+func inferPlugin(ctx context.Context, client *costSourceClient, fallbackName string) *plugin.Plugin {
+	p := &plugin.Plugin{Name: fallbackName, Capabilities: &plugin.PluginCapabilities{}}
+
+	call := func(fn func(ctx context.Context) error) error {
+		callCtx, cancel := context.WithTimeout(ctx, conformanceCallTimeout)
+		defer cancel()
+		return fn(callCtx)
+	}
+
+	_ = call(func(ctx context.Context) error {
+		if name, err := client.Name(ctx); err == nil && name != "" {
+			p.Name = name
+		}
+		return nil
+	})
+
+	var probe *resourceDescriptor
+	providers := make([]string, 0, len(conformanceFixtures))
+	for provider := range conformanceFixtures {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	for _, provider := range providers {
+		for _, fixture := range conformanceFixtures[provider] {
+			var supported bool
+			_ = call(func(ctx context.Context) error {
+				resp, err := client.Supports(ctx, &fixture)
+				supported = err == nil && resp.Supported
+				return err
+			})
+			if supported {
+				p.Capabilities.SupportsProviders = append(p.Capabilities.SupportsProviders, provider)
+				if probe == nil {
+					probe = &fixture
+				}
+				break
+			}
+		}
+	}
+
+	if probe != nil {
+		err := call(func(ctx context.Context) error {
+			_, err := client.GetProjectedCost(ctx, probe)
+			return err
+		})
+		p.Capabilities.SupportsProjected = status.Code(err) != codes.Unimplemented
+	}
+
+	now := time.Now().UTC()
+	err := call(func(ctx context.Context) error {
+		_, err := client.GetActualCost(ctx, &actualCostRequest{
+			ResourceID: "pulumicost-conformance-probe",
+			Start:      now.Add(-time.Hour),
+			End:        now,
+		})
+		return err
+	})
+	p.Capabilities.SupportsActual = status.Code(err) != codes.Unimplemented
+
+	return p
+}
+
+// runTests runs tests in order, timing each one
+func (a *SpecAdapter) runTests(ctx context.Context, target *conformanceTarget, tests []conformanceTest) []TestResult {
+	results := make([]TestResult, 0, len(tests))
+	for _, test := range tests {
+		start := time.Now()
+		err := test.run(ctx, target)
+		if errors.Is(err, errSkipped) {
+			continue
+		}
+		result := TestResult{
+			Name:     test.name,
+			Passed:   err == nil,
+			Duration: time.Since(start).Milliseconds(),
+		}
+		if test.costs != nil {
+			expected := test.costs.expected
+			result.ExpectedCost = &expected
+			result.ActualCost = test.costs.actual
+		}
+		if err != nil {
+			result.Error = err.Error()
+			a.logger.Debug("conformance test failed", "plugin", target.plugin.Name, "test", test.name, "error", err)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func (a *SpecAdapter) basicTests() []conformanceTest {
+	return []conformanceTest{
+		{name: "basic/startup", run: a.testStartup},
+		{name: "basic/health", run: testHealth},
+		{name: "basic/capabilities", run: testCapabilities},
+	}
+}
+
+func (a *SpecAdapter) standardTests(target *conformanceTarget) []conformanceTest {
+	capabilities := target.plugin.Capabilities
+	var tests []conformanceTest
+	if capabilities.SupportsProjected {
+		tests = append(tests, conformanceTest{name: "standard/projected_cost", run: testProjectedCost})
+	}
+	if capabilities.SupportsActual {
+		tests = append(tests, conformanceTest{name: "standard/actual_cost", run: testActualCost})
+	}
+	return append(tests,
+		conformanceTest{name: "standard/error_codes", run: testErrorCodes},
+		conformanceTest{name: "standard/resource_types", run: testResourceTypes},
+	)
+}
 
-	return []TestResult{}, fmt.Errorf("not implemented")
+func (a *SpecAdapter) fullTests() []conformanceTest {
+	return []conformanceTest{
+		{name: "full/concurrency", run: testConcurrency},
+		{name: "full/latency", run: a.testLatency},
+		{name: "full/resource_limits", run: testResourceLimits},
+	}
+}
+
+// testStartup waits for the plugin to accept connections
+func (a *SpecAdapter) testStartup(ctx context.Context, t *conformanceTarget) error {
+	ctx, cancel := context.WithTimeout(ctx, a.startupTimeout)
+	defer cancel()
+
+	t.conn.Connect()
+	for {
+		state := t.conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if !t.conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("plugin did not accept connections within %s (state %s)", a.startupTimeout, state)
+		}
+	}
+}
+
+// testHealth requires the standard gRPC health service to report SERVING
+func testHealth(ctx context.Context, t *conformanceTarget) error {
+	ctx, cancel := context.WithTimeout(ctx, conformanceCallTimeout)
+	defer cancel()
+
+	resp, err := grpc_health_v1.NewHealthClient(t.conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("health status is %s, want SERVING", resp.Status)
+	}
+	return nil
+}
+
+// testCapabilities requires the plugin to name itself and declare what it prices
+func testCapabilities(ctx context.Context, t *conformanceTarget) error {
+	ctx, cancel := context.WithTimeout(ctx, conformanceCallTimeout)
+	defer cancel()
+
+	name, err := t.client.Name(ctx)
+	if err != nil {
+		return fmt.Errorf("Name: %w", err)
+	}
+	if name == "" {
+		return fmt.Errorf("Name returned an empty name")
+	}
+	if name != t.plugin.Name {
+		return fmt.Errorf("Name returned %q, plugin.json declares %q", name, t.plugin.Name)
+	}
+
+	capabilities := t.plugin.Capabilities
+	if len(capabilities.SupportsProviders) == 0 {
+		return fmt.Errorf("plugin declares no providers")
+	}
+	if !declaresCapability(capabilities) {
+		return fmt.Errorf("plugin declares no capabilities")
+	}
+	return nil
+}
+
+// testProjectedCost checks the shape of projected costs for supported fixtures
+func testProjectedCost(ctx context.Context, t *conformanceTarget) error {
+	supported, _, err := t.classifyFixtures(ctx)
+	if err != nil {
+		return err
+	}
+	if len(supported) == 0 {
+		return fmt.Errorf("plugin supports none of the fixture resources for %s",
+			strings.Join(t.plugin.Capabilities.SupportsProviders, ", "))
+	}
+
+	for _, fixture := range supported {
+		callCtx, cancel := context.WithTimeout(ctx, conformanceCallTimeout)
+		resp, err := t.client.GetProjectedCost(callCtx, &fixture)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("GetProjectedCost %s: %w", fixture.ResourceType, err)
+		}
+		if err := checkAmount("cost_per_month", resp.CostPerMonth); err != nil {
+			return fmt.Errorf("GetProjectedCost %s: %w", fixture.ResourceType, err)
+		}
+		if err := checkAmount("unit_price", resp.UnitPrice); err != nil {
+			return fmt.Errorf("GetProjectedCost %s: %w", fixture.ResourceType, err)
+		}
+		if !currencyPattern.MatchString(resp.Currency) {
+			return fmt.Errorf("GetProjectedCost %s: currency %q is not an ISO 4217 code", fixture.ResourceType, resp.Currency)
+		}
+	}
+	return nil
+}
+
+// testActualCost checks the shape of historical costs for the last day.
+// NotFound is accepted because the probe resource does not exist.
+func testActualCost(ctx context.Context, t *conformanceTarget) error {
+	ctx, cancel := context.WithTimeout(ctx, conformanceCallTimeout)
+	defer cancel()
+
+	end := time.Now().UTC().Truncate(time.Hour)
+	start := end.Add(-24 * time.Hour)
+	resp, err := t.client.GetActualCost(ctx, &actualCostRequest{
+		ResourceID: "pulumicost-conformance-probe",
+		Start:      start,
+		End:        end,
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("GetActualCost: %w", err)
+	}
+
+	for i, r := range resp.Results {
+		if err := checkAmount("cost", r.Cost); err != nil {
+			return fmt.Errorf("GetActualCost result %d: %w", i, err)
+		}
+		if err := checkAmount("usage_amount", r.UsageAmount); err != nil {
+			return fmt.Errorf("GetActualCost result %d: %w", i, err)
+		}
+		if r.Timestamp.Before(start) || r.Timestamp.After(end) {
+			return fmt.Errorf("GetActualCost result %d: timestamp %s is outside the requested range", i, r.Timestamp.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// testErrorCodes requires invalid requests to fail with InvalidArgument and
+// unknown providers to be reported as unsupported rather than as errors
+func testErrorCodes(ctx context.Context, t *conformanceTarget) error {
+	ctx, cancel := context.WithTimeout(ctx, conformanceCallTimeout)
+	defer cancel()
+
+	if t.plugin.Capabilities.SupportsProjected {
+		_, err := t.client.GetProjectedCost(ctx, &resourceDescriptor{})
+		if err := expectCode("GetProjectedCost with an empty resource", err, codes.InvalidArgument); err != nil {
+			return err
+		}
+	}
+
+	if t.plugin.Capabilities.SupportsActual {
+		now := time.Now().UTC()
+		_, err := t.client.GetActualCost(ctx, &actualCostRequest{
+			ResourceID: "pulumicost-conformance-probe",
+			Start:      now,
+			End:        now.Add(-time.Hour),
+		})
+		if err := expectCode("GetActualCost with end before start", err, codes.InvalidArgument); err != nil {
+			return err
+		}
+	}
+
+	resp, err := t.client.Supports(ctx, &resourceDescriptor{Provider: "pulumicost-conformance-unknown", ResourceType: "unknown:index:Resource"})
+	if err != nil {
+		return fmt.Errorf("Supports for an unknown provider must answer, got %w", err)
+	}
+	if resp.Supported {
+		return fmt.Errorf("Supports claims an unknown provider is supported")
+	}
+	return nil
+}
+
+// testResourceTypes requires every declared provider to have a supported
+// fixture, and projected cost answers to agree with Supports
+func testResourceTypes(ctx context.Context, t *conformanceTarget) error {
+	supported, unsupported, err := t.classifyFixtures(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, provider := range t.plugin.Capabilities.SupportsProviders {
+		fixtures, known := conformanceFixtures[provider]
+		if !known {
+			continue
+		}
+		if !containsProvider(supported, provider) {
+			types := make([]string, len(fixtures))
+			for i, f := range fixtures {
+				types[i] = f.ResourceType
+			}
+			return fmt.Errorf("plugin declares provider %s but supports none of %s", provider, strings.Join(types, ", "))
+		}
+	}
+
+	if !t.plugin.Capabilities.SupportsProjected {
+		return nil
+	}
+	for _, fixture := range unsupported {
+		callCtx, cancel := context.WithTimeout(ctx, conformanceCallTimeout)
+		_, err := t.client.GetProjectedCost(callCtx, &fixture)
+		cancel()
+		if err == nil {
+			return fmt.Errorf("GetProjectedCost priced %s although Supports reported it unsupported", fixture.ResourceType)
+		}
+		if err := expectCode("GetProjectedCost "+fixture.ResourceType, err,
+			codes.NotFound, codes.Unimplemented, codes.InvalidArgument); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// testConcurrency requires concurrent calls to all succeed with consistent answers
+func testConcurrency(ctx context.Context, t *conformanceTarget) error {
+	ctx, cancel := context.WithTimeout(ctx, conformanceCallTimeout)
+	defer cancel()
+
+	names := make([]string, conformanceConcurrency)
+	errs := make([]error, conformanceConcurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < conformanceConcurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			names[i], errs[i] = t.client.Name(ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	var failures int
+	for i, err := range errs {
+		if err != nil {
+			failures++
+			continue
+		}
+		if names[i] != names[0] {
+			return fmt.Errorf("concurrent Name calls returned %q and %q", names[0], names[i])
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d of %d concurrent calls failed: %w", failures, conformanceConcurrency, errors.Join(errs...))
+	}
+	return nil
+}
+
+// testLatency requires the 95th percentile of sequential calls to stay within budget
+func (a *SpecAdapter) testLatency(ctx context.Context, t *conformanceTarget) error {
+	latencies := make([]time.Duration, 0, conformanceLatencySamples)
+	for i := 0; i < conformanceLatencySamples; i++ {
+		callCtx, cancel := context.WithTimeout(ctx, conformanceCallTimeout)
+		start := time.Now()
+		_, err := t.client.Name(callCtx)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("Name: %w", err)
+		}
+		latencies = append(latencies, time.Since(start))
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	p95 := latencies[(len(latencies)*95+99)/100-1]
+	if p95 > a.latencyBudget {
+		return fmt.Errorf("p95 latency %s exceeds budget %s", p95.Round(time.Millisecond), a.latencyBudget)
+	}
+	return nil
+}
+
+// testResourceLimits sends an oversized request; the plugin may reject it
+// but must keep serving afterwards
+func testResourceLimits(ctx context.Context, t *conformanceTarget) error {
+	tags := make(map[string]string, conformanceOversizedTags)
+	value := strings.Repeat("x", 1024)
+	for i := 0; i < conformanceOversizedTags; i++ {
+		tags[fmt.Sprintf("tag-%04d", i)] = value
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, conformanceCallTimeout)
+	_, err := t.client.Supports(callCtx, &resourceDescriptor{
+		Provider:     t.plugin.Capabilities.SupportsProviders[0],
+		ResourceType: "pulumicost:conformance:Oversized",
+		Tags:         tags,
+	})
+	cancel()
+	if err != nil {
+		if err := expectCode("Supports with an oversized request", err,
+			codes.ResourceExhausted, codes.InvalidArgument); err != nil {
+			return err
+		}
+	}
+
+	if err := testHealth(ctx, t); err != nil {
+		return fmt.Errorf("after an oversized request: %w", err)
+	}
+	return nil
 }
 
-// RunAdvancedTests runs advanced conformance tests
-func (a *SpecAdapter) RunAdvancedTests(ctx context.Context, p *plugin.Plugin) ([]TestResult, error) {
-	// Advanced tests would verify:
-	// - All standard tests pass
-	// - Performance meets requirements
-	// - Concurrent request handling
-	// - Resource limits are respected
-	// - Edge cases handled correctly
+// classifyFixtures asks the plugin which fixtures of its providers it supports
+func (t *conformanceTarget) classifyFixtures(ctx context.Context) (supported, unsupported []resourceDescriptor, err error) {
+	for _, provider := range t.plugin.Capabilities.SupportsProviders {
+		for _, fixture := range conformanceFixtures[provider] {
+			callCtx, cancel := context.WithTimeout(ctx, conformanceCallTimeout)
+			resp, err := t.client.Supports(callCtx, &fixture)
+			cancel()
+			if err != nil {
+				return nil, nil, fmt.Errorf("Supports %s: %w", fixture.ResourceType, err)
+			}
+			if resp.Supported {
+				supported = append(supported, fixture)
+			} else {
+				unsupported = append(unsupported, fixture)
+			}
+		}
+	}
+	return supported, unsupported, nil
+}
 
-	return []TestResult{}, fmt.Errorf("not implemented")
+// checkAmount requires a monetary or usage amount to be finite and non-negative
+func checkAmount(field string, value float64) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+		return fmt.Errorf("%s must be a non-negative number, got %v", field, value)
+	}
+	return nil
+}
+
+// expectCode requires err to carry one of the given gRPC status codes
+func expectCode(call string, err error, want ...codes.Code) error {
+	got := status.Code(err)
+	for _, code := range want {
+		if got == code {
+			return nil
+		}
+	}
+	if err == nil {
+		return fmt.Errorf("%s succeeded, want %s", call, want[0])
+	}
+	return fmt.Errorf("%s returned %s, want %s", call, got, want[0])
+}
+
+func containsProvider(fixtures []resourceDescriptor, provider string) bool {
+	for _, f := range fixtures {
+		if f.Provider == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// failedTests returns the names of failed tests
+func failedTests(results []TestResult) []string {
+	var failed []string
+	for _, r := range results {
+		if !r.Passed {
+			failed = append(failed, r.Name)
+		}
+	}
+	return failed
 }
 
 // TestResult represents the result of a single conformance test
 type TestResult struct {
 	Name     string
 	Passed   bool
-	Duration int64
+	Duration int64 // milliseconds
 	Error    string
+	// ExpectedCost and ActualCost are the monthly costs compared by cost accuracy tests
+	ExpectedCost *float64
+	ActualCost   *float64
+}
+
+// toAPI converts the result to the API type
+func (r TestResult) toAPI() *plugin.ValidationTest {
+	duration := r.Duration
+	test := &plugin.ValidationTest{
+		Name:         r.Name,
+		Passed:       r.Passed,
+		DurationMs:   &duration,
+		ExpectedCost: r.ExpectedCost,
+		ActualCost:   r.ActualCost,
+	}
+	if r.Error != "" {
+		message := r.Error
+		test.ErrorMessage = &message
+	}
+	return test
 }
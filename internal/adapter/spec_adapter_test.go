@@ -2,12 +2,24 @@ package adapter
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/rshade/pulumicost-mcp/gen/plugin"
 	"github.com/rshade/pulumicost-mcp/internal/logging"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 // TestValidatePlugin verifies plugin conformance testing (T061)
@@ -26,7 +38,7 @@ func TestValidatePlugin(t *testing.T) {
 		Version: "1.0.0",
 	}
 
-	result, err := adapter.ValidatePlugin(ctx, testPlugin, "basic")
+	result, err := adapter.ValidatePlugin(ctx, testPlugin, ConformanceBasic, nil)
 
 	// Expected to fail without actual plugin running
 	// Just verify the interface works
@@ -38,7 +50,7 @@ func TestValidatePlugin(t *testing.T) {
 
 	require.NotNil(t, result)
 	assert.Equal(t, testPlugin.Name, result.PluginName)
-	assert.Equal(t, "basic", result.ConformanceLevel)
+	assert.Equal(t, "BASIC", result.ConformanceLevel)
 }
 
 // TestValidatePlugin_Levels verifies different conformance levels
@@ -57,23 +69,23 @@ func TestValidatePlugin_Levels(t *testing.T) {
 		Version: "1.0.0",
 	}
 
-	levels := []string{"basic", "standard", "advanced"}
+	levels := []ConformanceLevel{ConformanceBasic, ConformanceStandard, ConformanceFull}
 
 	for _, level := range levels {
-		t.Run("Level_"+level, func(t *testing.T) {
-			result, err := adapter.ValidatePlugin(ctx, testPlugin, level)
+		t.Run("Level_"+level.String(), func(t *testing.T) {
+			result, err := adapter.ValidatePlugin(ctx, testPlugin, level, nil)
 
 			// Without actual plugin, expect error
 			if err != nil {
 				assert.NotNil(t, result)
-				assert.Equal(t, level, result.ConformanceLevel)
+				assert.Equal(t, level.String(), result.ConformanceLevel)
 				return
 			}
 
 			// If no error, verify result
 			require.NotNil(t, result)
 			assert.Equal(t, testPlugin.Name, result.PluginName)
-			assert.Equal(t, level, result.ConformanceLevel)
+			assert.Equal(t, level.String(), result.ConformanceLevel)
 		})
 	}
 }
@@ -90,8 +102,277 @@ func TestValidatePlugin_InvalidLevel(t *testing.T) {
 		Version: "1.0.0",
 	}
 
-	result, err := adapter.ValidatePlugin(ctx, testPlugin, "invalid-level")
+	result, err := adapter.ValidatePlugin(ctx, testPlugin, ConformanceLevel(0), nil)
 
-	assert.Error(t, err, "should error on invalid conformance level")
-	assert.NotNil(t, result, "should still return result structure")
+	var invalid *InvalidConformanceLevelError
+	assert.ErrorAs(t, err, &invalid, "should reject unknown conformance levels")
+	assert.Nil(t, result)
+}
+
+// TestValidatePlugin_ConformingPlugin verifies every suite passes against a conforming plugin
+func TestValidatePlugin_ConformingPlugin(t *testing.T) {
+	pluginDir := t.TempDir()
+	startFakeCostSource(t, pluginDir, &fakeCostSource{name: "aws-fake"})
+
+	specAdapter := NewSpecAdapterWithPlugins(NewPluginAdapter(pluginDir, logging.Default()), logging.Default())
+	report, err := specAdapter.ValidatePlugin(context.Background(), &plugin.Plugin{Name: "aws-fake"}, ConformanceFull, nil)
+	require.NoError(t, err)
+
+	var names []string
+	for _, test := range report.TestResults {
+		names = append(names, test.Name)
+		assert.True(t, test.Passed, "%s: %v", test.Name, test.ErrorMessage)
+		assert.NotNil(t, test.DurationMs)
+	}
+	assert.Equal(t, []string{
+		"basic/startup", "basic/health", "basic/capabilities",
+		"standard/projected_cost", "standard/actual_cost", "standard/error_codes", "standard/resource_types",
+		"standard/accuracy/aws-ec2-t3-micro", "standard/accuracy/aws-ec2-t3-medium", "standard/accuracy/aws-ec2-m5-large",
+		"standard/accuracy/aws-ec2-c5-large", "standard/accuracy/aws-ec2-r5-large",
+		"full/concurrency", "full/latency", "full/resource_limits",
+	}, names)
+	assert.True(t, report.Passed)
+	assert.Equal(t, "FULL", report.ConformanceLevel)
+	assert.NotNil(t, report.Timestamp)
+}
+
+// TestValidatePlugin_NonConformingPlugin verifies each kind of violation fails the right test
+func TestValidatePlugin_NonConformingPlugin(t *testing.T) {
+	tests := []struct {
+		name    string
+		fake    *fakeCostSource
+		failed  string
+		wantErr string
+		notRun  string
+		latency time.Duration
+	}{
+		{
+			name:    "wrong name",
+			fake:    &fakeCostSource{name: "something-else"},
+			failed:  "basic/capabilities",
+			wantErr: `Name returned "something-else", plugin.json declares "aws-fake"`,
+			notRun:  "standard/projected_cost",
+		},
+		{
+			name:    "negative cost",
+			fake:    &fakeCostSource{name: "aws-fake", costPerMonth: -1},
+			failed:  "standard/projected_cost",
+			wantErr: "cost_per_month must be a non-negative number",
+			notRun:  "full/concurrency",
+		},
+		{
+			name:    "accepts invalid requests",
+			fake:    &fakeCostSource{name: "aws-fake", lenient: true},
+			failed:  "standard/error_codes",
+			wantErr: "GetProjectedCost with an empty resource succeeded, want InvalidArgument",
+		},
+		{
+			name:    "no supported fixtures",
+			fake:    &fakeCostSource{name: "aws-fake", unsupported: true},
+			failed:  "standard/resource_types",
+			wantErr: "plugin declares provider aws but supports none of",
+		},
+		{
+			name:    "slow",
+			fake:    &fakeCostSource{name: "aws-fake", delay: 20 * time.Millisecond},
+			latency: 5 * time.Millisecond,
+			failed:  "full/latency",
+			wantErr: "exceeds budget 5ms",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pluginDir := t.TempDir()
+			startFakeCostSource(t, pluginDir, tt.fake)
+
+			specAdapter := NewSpecAdapterWithPlugins(NewPluginAdapter(pluginDir, logging.Default()), logging.Default())
+			if tt.latency > 0 {
+				specAdapter.latencyBudget = tt.latency
+			}
+
+			report, err := specAdapter.ValidatePlugin(context.Background(), &plugin.Plugin{Name: "aws-fake"}, ConformanceFull, nil)
+			require.NoError(t, err)
+			assert.False(t, report.Passed)
+
+			results := make(map[string]*plugin.ValidationTest)
+			for _, test := range report.TestResults {
+				results[test.Name] = test
+			}
+			require.Contains(t, results, tt.failed)
+			assert.False(t, results[tt.failed].Passed)
+			require.NotNil(t, results[tt.failed].ErrorMessage)
+			assert.Contains(t, *results[tt.failed].ErrorMessage, tt.wantErr)
+			if tt.notRun != "" {
+				assert.NotContains(t, results, tt.notRun, "higher suites should not run after a failure")
+			}
+		})
+	}
+}
+
+// TestValidatePlugin_OptimizationOnly verifies plugins declaring only optimization or
+// forecast support pass the capabilities test, matching what install accepts
+func TestValidatePlugin_OptimizationOnly(t *testing.T) {
+	for _, capability := range []string{"supports_optimization", "supports_forecast"} {
+		t.Run(capability, func(t *testing.T) {
+			pluginDir := t.TempDir()
+			startFakeCostSource(t, pluginDir, &fakeCostSource{name: "aws-fake"})
+
+			path := filepath.Join(pluginDir, "aws-fake", "plugin.json")
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			data = []byte(strings.Replace(string(data), `"supports_projected_cost": true, "supports_actual_cost": true`,
+				fmt.Sprintf("%q: true", capability), 1))
+			require.NoError(t, os.WriteFile(path, data, 0644))
+
+			specAdapter := NewSpecAdapterWithPlugins(NewPluginAdapter(pluginDir, logging.Default()), logging.Default())
+			report, err := specAdapter.ValidatePlugin(context.Background(), &plugin.Plugin{Name: "aws-fake"}, ConformanceBasic, nil)
+			require.NoError(t, err)
+			for _, test := range report.TestResults {
+				assert.True(t, test.Passed, "%s: %v", test.Name, test.ErrorMessage)
+			}
+			assert.True(t, report.Passed)
+		})
+	}
+}
+
+// TestValidatePlugin_Unreachable verifies an unreachable plugin fails the startup test
+func TestValidatePlugin_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	pluginDir := t.TempDir()
+	writePluginJSON(t, pluginDir, "aws-fake", fmt.Sprintf(`{"name": "aws-fake", "version": "1.0.0", "providers": "aws",
+		"grpc_address": %q, "capabilities": {"supports_projected_cost": true}}`, address))
+
+	specAdapter := NewSpecAdapterWithPlugins(NewPluginAdapter(pluginDir, logging.Default()), logging.Default())
+	specAdapter.startupTimeout = 200 * time.Millisecond
+
+	report, err := specAdapter.ValidatePlugin(context.Background(), &plugin.Plugin{Name: "aws-fake"}, ConformanceStandard, nil)
+	require.NoError(t, err)
+	assert.False(t, report.Passed)
+	require.Len(t, report.TestResults, 3)
+	assert.Equal(t, "basic/startup", report.TestResults[0].Name)
+	assert.Contains(t, *report.TestResults[0].ErrorMessage, "did not accept connections within 200ms")
+}
+
+// fakeCostSource is an in-process pulumicost-spec plugin whose behaviour can be
+// bent to violate the spec
+type fakeCostSource struct {
+	name         string
+	costPerMonth float64
+	lenient      bool // answer invalid requests instead of rejecting them
+	unsupported  bool // support no resource types
+	delay        time.Duration
+}
+
+func (f *fakeCostSource) handle(method string, req wireMessage) (wireMessage, error) {
+	time.Sleep(f.delay)
+
+	switch method {
+	case "Name":
+		return &nameResponse{Name: f.name}, nil
+	case "Supports":
+		r := req.(*supportsRequest).Resource
+		supported := !f.unsupported && r.ResourceType == "aws:ec2/instance:Instance"
+		return &supportsResponse{Supported: supported}, nil
+	case "GetProjectedCost":
+		r := req.(*projectedCostRequest).Resource
+		switch {
+		case r == nil || r.Provider == "":
+			if f.lenient {
+				return &projectedCostResponse{Currency: "USD"}, nil
+			}
+			return nil, status.Error(codes.InvalidArgument, "resource is required")
+		case f.unsupported || r.ResourceType != "aws:ec2/instance:Instance":
+			return nil, status.Error(codes.NotFound, "unsupported resource type")
+		}
+		hourly, ok := fakeHourlyPrices[r.SKU]
+		if !ok {
+			hourly = fakeHourlyPrices["t3.micro"]
+		}
+		cost := hourly * 730
+		if f.costPerMonth != 0 {
+			cost = f.costPerMonth
+		}
+		return &projectedCostResponse{UnitPrice: hourly, Currency: "USD", CostPerMonth: cost}, nil
+	case "GetActualCost":
+		r := req.(*actualCostRequest)
+		if r.End.Before(r.Start) && !f.lenient {
+			return nil, status.Error(codes.InvalidArgument, "end before start")
+		}
+		return &actualCostResponse{Results: []*actualCostResult{{Timestamp: r.Start, Cost: 1.25, Source: "fake"}}}, nil
+	}
+	return nil, status.Error(codes.Unimplemented, method)
+}
+
+// fakeHourlyPrices are us-east-1 on-demand EC2 prices, matching the baseline fixtures
+var fakeHourlyPrices = map[string]float64{
+	"t3.micro":  0.0104,
+	"t3.medium": 0.0416,
+	"m5.large":  0.096,
+	"c5.large":  0.085,
+	"r5.large":  0.126,
+}
+
+// startFakeCostSource serves fake on a local port and installs it as "aws-fake" in pluginDir
+func startFakeCostSource(t *testing.T, pluginDir string, fake *fakeCostSource) {
+	t.Helper()
+
+	server := newFakeServer(fake)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	writePluginJSON(t, pluginDir, "aws-fake", fmt.Sprintf(`{"name": "aws-fake", "version": "1.0.0", "providers": "aws",
+		"grpc_address": %q, "capabilities": {"supports_projected_cost": true, "supports_actual_cost": true}}`,
+		listener.Addr().String()))
+}
+
+// newFakeServer returns a gRPC server exposing fake and a health service
+func newFakeServer(fake *fakeCostSource) *grpc.Server {
+	requests := map[string]func() wireMessage{
+		"Name":             func() wireMessage { return &emptyMessage{} },
+		"Supports":         func() wireMessage { return &supportsRequest{} },
+		"GetProjectedCost": func() wireMessage { return &projectedCostRequest{} },
+		"GetActualCost":    func() wireMessage { return &actualCostRequest{} },
+	}
+	desc := grpc.ServiceDesc{ServiceName: "pulumicost.v1.CostSourceService", HandlerType: (*any)(nil)}
+	for method, newRequest := range requests {
+		desc.Methods = append(desc.Methods, grpc.MethodDesc{
+			MethodName: method,
+			Handler: func(_ any, _ context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				req := newRequest()
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				return fake.handle(method, req)
+			},
+		})
+	}
+
+	server := grpc.NewServer(grpc.ForceServerCodec(fakeServerCodec{}))
+	server.RegisterService(&desc, fake)
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	return server
+}
+
+// fakeServerCodec serves both hand-encoded spec messages and the generated health messages
+type fakeServerCodec struct{ wireCodec }
+
+func (c fakeServerCodec) Marshal(v any) ([]byte, error) {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Marshal(m)
+	}
+	return c.wireCodec.Marshal(v)
+}
+
+func (c fakeServerCodec) Unmarshal(data []byte, v any) error {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Unmarshal(data, m)
+	}
+	return c.wireCodec.Unmarshal(data, v)
 }
@@ -4,6 +4,85 @@
 # Read input from stdin if provided
 INPUT=$(cat)
 
+# Return mock recommendations, including one for a resource of another stack
+if [ "$1" = "recommend" ]; then
+  cat <<EOF
+{
+  "recommendations": [
+    {
+      "id": "rightsize-web-server",
+      "type": "RIGHTSIZING",
+      "resource_urn": "urn:pulumi:dev::myapp::aws:ec2/instance:Instance::web-server",
+      "current_cost": 10.50,
+      "projected_savings": 5.25,
+      "confidence": "high",
+      "description": "CPU utilization below 10%; downsize from t3.small to t3.micro",
+      "action_steps": ["Change instance type to t3.micro", "Monitor CPU utilization"]
+    },
+    {
+      "type": "RESERVED_INSTANCES",
+      "resource_urn": "urn:pulumi:dev::myapp::aws:rds/instance:Instance::db",
+      "projected_savings": 12.80,
+      "description": "Database runs continuously; a 1-year reservation saves 40%",
+      "break_even": {"term": "1y", "upfront_cost": 150.00, "break_even_months": 7.5}
+    },
+    {
+      "id": "spot-batch",
+      "type": "SPOT_INSTANCES",
+      "resource_urn": "urn:pulumi:prod::other::aws:ec2/instance:Instance::batch",
+      "projected_savings": 56.00,
+      "confidence": "MEDIUM",
+      "description": "Batch workload tolerates interruptions"
+    },
+    {
+      "category": "scheduling",
+      "resource_urns": [
+        "urn:pulumi:dev::myapp::aws:ec2/instance:Instance::web-server",
+        "urn:pulumi:dev::myapp::aws:rds/instance:Instance::db",
+        "urn:pulumi:prod::other::aws:ec2/instance:Instance::batch"
+      ],
+      "projected_savings": 8.00,
+      "risk_level": "low",
+      "implementation_effort": "medium",
+      "description": "Stop development resources outside business hours"
+    }
+  ]
+}
+EOF
+  exit 0
+fi
+
+# Return a mock forecast that echoes its granularity and confidence; the
+# "legacy" stack behaves like a pulumicost-core without forecasting
+if [ "$1" = "forecast" ]; then
+  while [ $# -gt 0 ]; do
+    case "$1" in
+      --stack) STACK="$2"; shift ;;
+      --granularity) GRANULARITY="$2"; shift ;;
+      --confidence) CONFIDENCE="$2"; shift ;;
+    esac
+    shift
+  done
+  if [ "$STACK" = "legacy" ]; then
+    echo 'Error: unknown command "forecast" for "pulumicost"' >&2
+    exit 1
+  fi
+  cat <<EOF
+{
+  "methodology": "Linear regression over 90 days of daily actual costs, ${GRANULARITY} points at ${CONFIDENCE} confidence",
+  "currency": "USD",
+  "history_start": "2024-01-01T00:00:00Z",
+  "history_end": "2024-03-31T00:00:00Z",
+  "data_points": [
+    {"timestamp": "2024-04-01T00:00:00Z", "predicted_cost": 1.40, "lower_bound": 1.20, "upper_bound": 1.60},
+    {"timestamp": "2024-04-02T00:00:00Z", "predicted_cost": 1.42, "lower_bound": 1.18, "upper_bound": 1.66},
+    {"timestamp": "2024-04-03T00:00:00Z", "predicted_cost": 1.44, "lower_bound": -0.10, "upper_bound": 1.72}
+  ]
+}
+EOF
+  exit 0
+fi
+
 # Return mock cost analysis result
 cat <<EOF
 {
@@ -19,10 +98,16 @@ cat <<EOF
       "monthly_cost": 10.50,
       "hourly_cost": 0.014,
       "region": "us-east-1",
+      "billing_mode": "reserved",
       "tags": {
         "environment": "dev",
         "team": "platform"
-      }
+      },
+      "data_points": [
+        {"timestamp": "2024-01-01T00:00:00Z", "cost": 0.35, "usage_amount": 24, "usage_unit": "Hrs", "currency": "USD"},
+        {"timestamp": "2024-01-02T00:00:00Z", "cost": 0.35, "usage_amount": 24, "usage_unit": "Hrs", "currency": "USD"},
+        {"timestamp": "2024-01-03T00:00:00Z", "cost": 0.35, "usage_amount": 24, "usage_unit": "Hrs", "currency": "USD"}
+      ]
     },
     {
       "urn": "urn:pulumi:dev::myapp::aws:rds/instance:Instance::db",
@@ -32,10 +117,16 @@ cat <<EOF
       "monthly_cost": 32.00,
       "hourly_cost": 0.044,
       "region": "us-east-1",
+      "billing_mode": "on_demand",
       "tags": {
         "environment": "dev",
         "team": "backend"
-      }
+      },
+      "data_points": [
+        {"timestamp": "2024-01-01T00:00:00Z", "cost": 1.05, "usage_amount": 0, "usage_unit": "Connections", "currency": "USD"},
+        {"timestamp": "2024-01-02T00:00:00Z", "cost": 1.05, "usage_amount": 0, "usage_unit": "Connections", "currency": "USD"},
+        {"timestamp": "2024-01-03T00:00:00Z", "cost": 1.05, "usage_amount": 0, "usage_unit": "Connections", "currency": "USD"}
+      ]
     }
   ],
   "breakdown": {
@@ -3,9 +3,12 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/rshade/pulumicost-mcp/internal/adapter"
 	"gopkg.in/yaml.v3"
 )
 
@@ -20,6 +23,9 @@ type Config struct {
 	Security      SecurityConfig      `yaml:"security"`
 	Pulumi        PulumiConfig        `yaml:"pulumi"`
 	Features      FeaturesConfig      `yaml:"features"`
+	Budgets       BudgetsConfig       `yaml:"budgets"`
+	UnitCost      UnitCostConfig      `yaml:"unit_cost"`
+	Notifications NotificationsConfig `yaml:"notifications"`
 	RateLimiting  RateLimitingConfig  `yaml:"rate_limiting"`
 	CORS          CORSConfig          `yaml:"cors"`
 	Development   DevelopmentConfig   `yaml:"development"`
@@ -46,11 +52,44 @@ type PulumiCostConfig struct {
 
 // PluginsConfig defines plugin management settings
 type PluginsConfig struct {
-	Timeout             time.Duration `yaml:"timeout"`
-	MaxConcurrent       int           `yaml:"max_concurrent"`
-	HealthCheckInterval time.Duration `yaml:"health_check_interval"`
-	RetryAttempts       int           `yaml:"retry_attempts"`
-	RetryDelay          time.Duration `yaml:"retry_delay"`
+	Timeout             time.Duration              `yaml:"timeout"`
+	MaxConcurrent       int                        `yaml:"max_concurrent"`
+	HealthCheckInterval time.Duration              `yaml:"health_check_interval"`
+	RetryAttempts       int                        `yaml:"retry_attempts"`
+	RetryDelay          time.Duration              `yaml:"retry_delay"`
+	WatchInterval       time.Duration              `yaml:"watch_interval"` // 0 disables plugin directory watching
+	Priority            []string                   `yaml:"priority"`       // plugin names tried first when several cover a provider
+	TLS                 map[string]PluginTLSConfig `yaml:"tls"`            // per-plugin overrides of plugin.json TLS settings
+	Integrity           PluginIntegrityConfig      `yaml:"integrity"`
+	Management          PluginManagementConfig     `yaml:"management"`
+	Validation          PluginValidationConfig     `yaml:"validation"`
+	Configuration       map[string]map[string]any  `yaml:"configuration"` // per-plugin overrides of plugin.json configuration
+}
+
+// PluginManagementConfig defines whether and from where plugins can be installed
+type PluginManagementConfig struct {
+	AllowMutation bool   `yaml:"allow_mutation"` // enables install_plugin, upgrade_plugin and remove_plugin
+	RegistryIndex string `yaml:"registry_index"` // file-based index of plugin releases
+}
+
+// PluginValidationConfig defines which plugins validate_plugin_spec may launch by path
+type PluginValidationConfig struct {
+	AllowLaunch bool     `yaml:"allow_launch"` // enables launching plugin binaries by path
+	Roots       []string `yaml:"roots"`        // directories besides plugin_dir whose plugins may be launched
+}
+
+// PluginIntegrityConfig defines plugin checksum and signature verification
+type PluginIntegrityConfig struct {
+	Mode        string            `yaml:"mode"`         // off, warn or enforce
+	TrustedKeys map[string]string `yaml:"trusted_keys"` // key ID to base64 ed25519 public key
+}
+
+// PluginTLSConfig defines TLS/mTLS settings for a plugin gRPC connection
+type PluginTLSConfig struct {
+	CAFile     string `yaml:"ca_file"`
+	CertFile   string `yaml:"cert_file"`
+	KeyFile    string `yaml:"key_file"`
+	ServerName string `yaml:"server_name"`
 }
 
 // MCPConfig defines MCP protocol settings
@@ -136,6 +175,28 @@ type FeaturesConfig struct {
 	Recommendations   bool `yaml:"recommendations"`
 }
 
+// BudgetsConfig defines where budget definitions are read from
+type BudgetsConfig struct {
+	File string `yaml:"file"` // read-only YAML file of team and project budgets
+}
+
+// UnitCostConfig defines where compute_unit_cost reads metric files from
+type UnitCostConfig struct {
+	MetricsDir string `yaml:"metrics_dir"` // metric_file paths must lie inside this directory; unset disables metric_file
+}
+
+// NotificationsConfig defines proactive budget and anomaly alerts
+type NotificationsConfig struct {
+	Enabled            bool          `yaml:"enabled"`
+	Interval           time.Duration `yaml:"interval"`            // how often budgets and anomalies are evaluated
+	Cooldown           time.Duration `yaml:"cooldown"`            // duplicates of an alert are not re-sent within this window
+	Webhooks           []string      `yaml:"webhooks"`            // URLs alerts are POSTed to in addition to SSE clients
+	AnomalyStacks      []string      `yaml:"anomaly_stacks"`      // stacks checked for anomalies on each evaluation
+	AnomalySensitivity string        `yaml:"anomaly_sensitivity"` // LOW, MEDIUM or HIGH
+	CoreEvents         bool          `yaml:"core_events"`         // accept alerts POSTed by pulumicost-core on /rpc/alerts
+	CoreEventsToken    string        `yaml:"core_events_token"`   // shared secret pulumicost-core sends in the X-Alert-Token header
+}
+
 // RateLimitingConfig defines rate limiting settings
 type RateLimitingConfig struct {
 	Enabled           bool `yaml:"enabled"`
@@ -225,6 +286,13 @@ func applyEnvOverrides(cfg *Config) {
 	}
 }
 
+// isSemver reports whether s is a semantic version, as the plugin
+// compatibility check parses it
+func isSemver(s string) bool {
+	_, err := adapter.ParseVersion(s)
+	return err == nil
+}
+
 // Validate checks that required configuration values are set and valid
 func (c *Config) Validate() error {
 	// Validate server config
@@ -248,6 +316,10 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("pulumicost.plugin_dir is required")
 	}
 
+	if c.PulumiCost.SpecVersion != "" && !isSemver(c.PulumiCost.SpecVersion) {
+		return fmt.Errorf("pulumicost.spec_version must be a semantic version (MAJOR.MINOR.PATCH)")
+	}
+
 	if c.PulumiCost.BatchSize < 1 {
 		return fmt.Errorf("pulumicost.batch_size must be at least 1")
 	}
@@ -261,6 +333,60 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("plugins.retry_attempts cannot be negative")
 	}
 
+	for name, tlsCfg := range c.Plugins.TLS {
+		if (tlsCfg.CertFile == "") != (tlsCfg.KeyFile == "") {
+			return fmt.Errorf("plugins.tls.%s: cert_file and key_file must be set together", name)
+		}
+	}
+
+	switch c.Plugins.Integrity.Mode {
+	case "off", "warn":
+	case "enforce":
+		if len(c.Plugins.Integrity.TrustedKeys) == 0 {
+			return fmt.Errorf("plugins.integrity.trusted_keys must not be empty in enforce mode")
+		}
+	default:
+		return fmt.Errorf("plugins.integrity.mode must be one of: off, warn, enforce")
+	}
+
+	if c.Plugins.WatchInterval < 0 {
+		return fmt.Errorf("plugins.watch_interval cannot be negative")
+	}
+
+	for _, root := range c.Plugins.Validation.Roots {
+		if !filepath.IsAbs(root) {
+			return fmt.Errorf("plugins.validation.roots: %q must be an absolute path", root)
+		}
+	}
+
+	if dir := c.UnitCost.MetricsDir; dir != "" && !filepath.IsAbs(dir) {
+		return fmt.Errorf("unit_cost.metrics_dir: %q must be an absolute path", dir)
+	}
+
+	// Validate notifications config
+	if c.Notifications.Enabled {
+		if c.Notifications.Interval <= 0 {
+			return fmt.Errorf("notifications.interval must be positive")
+		}
+		if c.Notifications.Cooldown < 0 {
+			return fmt.Errorf("notifications.cooldown cannot be negative")
+		}
+		for _, webhook := range c.Notifications.Webhooks {
+			u, err := url.Parse(webhook)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("notifications.webhooks: %q is not an http(s) URL", webhook)
+			}
+		}
+		switch c.Notifications.AnomalySensitivity {
+		case "LOW", "MEDIUM", "HIGH":
+		default:
+			return fmt.Errorf("notifications.anomaly_sensitivity must be one of: LOW, MEDIUM, HIGH")
+		}
+		if c.Notifications.CoreEvents && c.Notifications.CoreEventsToken == "" {
+			return fmt.Errorf("notifications.core_events_token is required when core_events is enabled")
+		}
+	}
+
 	// Validate MCP config
 	if c.MCP.MaxMessageSize < 1024 {
 		return fmt.Errorf("mcp.max_message_size must be at least 1024 bytes")
@@ -316,6 +442,10 @@ func Default() *Config {
 			HealthCheckInterval: 60 * time.Second,
 			RetryAttempts:       3,
 			RetryDelay:          5 * time.Second,
+			WatchInterval:       10 * time.Second,
+			Integrity: PluginIntegrityConfig{
+				Mode: "off",
+			},
 		},
 		MCP: MCPConfig{
 			EnableStreaming:   true,
@@ -366,6 +496,12 @@ func Default() *Config {
 			AnomalyDetection: true,
 			Recommendations:  true,
 		},
+		Notifications: NotificationsConfig{
+			Enabled:            false,
+			Interval:           15 * time.Minute,
+			Cooldown:           6 * time.Hour,
+			AnomalySensitivity: "MEDIUM",
+		},
 		RateLimiting: RateLimitingConfig{
 			Enabled:           false,
 			RequestsPerMinute: 60,
@@ -274,6 +274,88 @@ func TestValidate_NegativeRetryAttempts(t *testing.T) {
 	assert.Contains(t, err.Error(), "retry_attempts cannot be negative")
 }
 
+func TestValidate_NegativeWatchInterval(t *testing.T) {
+	cfg := Default()
+	cfg.Plugins.WatchInterval = -time.Second
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "watch_interval cannot be negative")
+}
+
+func TestValidate_RelativeValidationRoot(t *testing.T) {
+	cfg := Default()
+	cfg.Plugins.Validation.Roots = []string{"builds"}
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "plugins.validation.roots")
+}
+
+func TestValidate_RelativeMetricsDir(t *testing.T) {
+	cfg := Default()
+	cfg.UnitCost.MetricsDir = "metrics"
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unit_cost.metrics_dir")
+}
+
+func TestValidate_PluginTLSIncompleteClientCert(t *testing.T) {
+	cfg := Default()
+	cfg.Plugins.TLS = map[string]PluginTLSConfig{
+		"aws-cur": {CAFile: "/path/to/ca.pem", CertFile: "/path/to/client.pem"},
+	}
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "plugins.tls.aws-cur: cert_file and key_file must be set together")
+}
+
+func TestValidate_PluginIntegrity(t *testing.T) {
+	cfg := Default()
+	cfg.Plugins.Integrity.Mode = "strict"
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "plugins.integrity.mode must be one of")
+
+	cfg.Plugins.Integrity.Mode = "enforce"
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "trusted_keys must not be empty")
+
+	cfg.Plugins.Integrity.TrustedKeys = map[string]string{"release": "key"}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_Notifications(t *testing.T) {
+	cfg := Default()
+	cfg.Notifications.Enabled = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Notifications.Webhooks = []string{"ftp://alerts.example.com"}
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "notifications.webhooks")
+
+	cfg.Notifications.Webhooks = []string{"https://alerts.example.com/hook"}
+	cfg.Notifications.Interval = 0
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "notifications.interval must be positive")
+
+	cfg.Notifications.Interval = time.Minute
+	cfg.Notifications.AnomalySensitivity = "EXTREME"
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "notifications.anomaly_sensitivity")
+
+	cfg.Notifications.AnomalySensitivity = "MEDIUM"
+	cfg.Notifications.CoreEvents = true
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "notifications.core_events_token is required")
+
+	cfg.Notifications.CoreEventsToken = "s3cret"
+	assert.NoError(t, cfg.Validate())
+}
+
 func TestValidate_InvalidMaxMessageSize(t *testing.T) {
 	cfg := Default()
 	cfg.MCP.MaxMessageSize = 512
@@ -355,6 +437,7 @@ func TestDefault(t *testing.T) {
 	assert.Equal(t, 60*time.Second, cfg.Plugins.HealthCheckInterval)
 	assert.Equal(t, 3, cfg.Plugins.RetryAttempts)
 	assert.Equal(t, 5*time.Second, cfg.Plugins.RetryDelay)
+	assert.Equal(t, 10*time.Second, cfg.Plugins.WatchInterval)
 
 	assert.True(t, cfg.MCP.EnableStreaming)
 	assert.Equal(t, int64(10*1024*1024), cfg.MCP.MaxMessageSize)
@@ -454,3 +537,22 @@ pulumicost:
 	assert.Equal(t, 1*time.Minute, cfg.Server.WriteTimeout)
 	assert.Equal(t, 15*time.Second, cfg.Server.ShutdownTimeout)
 }
+
+func TestValidate_InvalidSpecVersion(t *testing.T) {
+	cfg := Default()
+	cfg.PulumiCost.SpecVersion = "0.1"
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "pulumicost.spec_version must be a semantic version")
+
+	cfg.PulumiCost.SpecVersion = "v0.2.0-rc.1"
+	assert.NoError(t, cfg.Validate())
+
+	// Accepted exactly when plugin compatibility checks can parse it
+	for _, version := range []string{"01.2.3", "1.2.3-", "1.2"} {
+		cfg.PulumiCost.SpecVersion = version
+		assert.Error(t, cfg.Validate(), version)
+	}
+	cfg.PulumiCost.SpecVersion = "0.2.0+build.5"
+	assert.NoError(t, cfg.Validate())
+}
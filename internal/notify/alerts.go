@@ -0,0 +1,203 @@
+package notify
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+)
+
+// MethodMessage is the MCP logging notification alerts are pushed as
+const MethodMessage = "notifications/message"
+
+// alertLogger names the source of alert notifications for MCP clients
+const alertLogger = "pulumicost-mcp.alerts"
+
+// TokenHeader carries the shared secret pulumicost-core sends with alerts
+const TokenHeader = "X-Alert-Token"
+
+// Alert kinds
+const (
+	KindBudget  = "budget"
+	KindAnomaly = "anomaly"
+	KindCore    = "core"
+)
+
+// Alert is a cost event that crossed a threshold
+type Alert struct {
+	Kind string `json:"kind"`
+	// Key identifies the condition; alerts with the same kind, key and
+	// severity are duplicates of each other
+	Key       string    `json:"key"`
+	Severity  string    `json:"severity"`
+	Message   string    `json:"message"`
+	StackName string    `json:"stack_name,omitempty"`
+	Data      any       `json:"data,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// dedupKey identifies duplicates, so an escalation is delivered immediately
+func (a Alert) dedupKey() string {
+	return a.Kind + "/" + a.Key + "/" + strings.ToUpper(a.Severity)
+}
+
+// logLevel maps alert severity to an MCP logging level
+func (a Alert) logLevel() string {
+	switch strings.ToUpper(a.Severity) {
+	case "CRITICAL":
+		return "critical"
+	case "HIGH":
+		return "error"
+	case "MEDIUM", "WARNING":
+		return "warning"
+	case "LOW":
+		return "notice"
+	default:
+		return "info"
+	}
+}
+
+// Sink delivers alerts to a destination
+type Sink interface {
+	Deliver(ctx context.Context, alert Alert) error
+}
+
+// Deliver pushes the alert to every connected client as an MCP logging
+// notification. It fails with ErrNoSubscribers when no client received it.
+func (h *Hub) Deliver(_ context.Context, alert Alert) error {
+	queued, err := h.broadcast(MethodMessage, map[string]any{
+		"level":  alert.logLevel(),
+		"logger": alertLogger,
+		"data":   alert,
+	})
+	if err != nil {
+		return err
+	}
+	if queued == 0 {
+		return ErrNoSubscribers
+	}
+	return nil
+}
+
+// Dispatcher delivers alerts to its sinks, suppressing duplicates of an alert
+// delivered within the cooldown window
+type Dispatcher struct {
+	sinks    []Sink
+	cooldown time.Duration
+	logger   *logging.Logger
+	now      func() time.Time
+
+	mu   sync.Mutex
+	sent map[string]time.Time
+}
+
+// NewDispatcher creates a dispatcher. A zero cooldown delivers every alert.
+func NewDispatcher(sinks []Sink, cooldown time.Duration, logger *logging.Logger) *Dispatcher {
+	if logger == nil {
+		logger = logging.Default()
+	}
+	return &Dispatcher{
+		sinks:    sinks,
+		cooldown: cooldown,
+		logger:   logger,
+		now:      time.Now,
+		sent:     make(map[string]time.Time),
+	}
+}
+
+// Dispatch delivers an alert unless a duplicate was delivered within the
+// cooldown. It reports whether the alert reached at least one sink; sink
+// failures are joined into the error and do not stop delivery to the other
+// sinks. An alert no sink accepted does not start the cooldown, so it is
+// retried on the next dispatch.
+func (d *Dispatcher) Dispatch(ctx context.Context, alert Alert) (bool, error) {
+	now := d.now()
+	if alert.Timestamp.IsZero() {
+		alert.Timestamp = now.UTC()
+	}
+
+	key := alert.dedupKey()
+	d.mu.Lock()
+	for k, sentAt := range d.sent {
+		if now.Sub(sentAt) >= d.cooldown {
+			delete(d.sent, k)
+		}
+	}
+	if _, ok := d.sent[key]; ok {
+		d.mu.Unlock()
+		d.logger.Debug("alert suppressed by cooldown", "kind", alert.Kind, "key", alert.Key)
+		return false, nil
+	}
+	d.sent[key] = now
+	d.mu.Unlock()
+
+	var errs []error
+	for _, sink := range d.sinks {
+		if err := sink.Deliver(ctx, alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(d.sinks) > 0 && len(errs) == len(d.sinks) {
+		// Release the key so a later duplicate is not suppressed
+		d.mu.Lock()
+		if sentAt, ok := d.sent[key]; ok && sentAt.Equal(now) {
+			delete(d.sent, key)
+		}
+		d.mu.Unlock()
+		return false, errors.Join(errs...)
+	}
+
+	d.logger.Info("alert dispatched", "kind", alert.Kind, "key", alert.Key, "severity", alert.Severity, "sinks", len(d.sinks)-len(errs))
+	return true, errors.Join(errs...)
+}
+
+// ServeHTTP accepts alerts POSTed by pulumicost-core and dispatches them
+func (d *Dispatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var alert Alert
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&alert); err != nil {
+		http.Error(w, fmt.Sprintf("invalid alert: %v", err), http.StatusBadRequest)
+		return
+	}
+	if alert.Key == "" || alert.Message == "" {
+		http.Error(w, "invalid alert: key and message are required", http.StatusBadRequest)
+		return
+	}
+	if alert.Kind == "" {
+		alert.Kind = KindCore
+	}
+
+	delivered, err := d.Dispatch(r.Context(), alert)
+	if err != nil {
+		d.logger.Warn("alert delivery failed", "kind", alert.Kind, "key", alert.Key, "error", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]bool{"delivered": delivered})
+}
+
+// RequireToken wraps next so only requests carrying token in TokenHeader
+// reach it. An empty token rejects every request.
+func RequireToken(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		got := r.Header.Get(TokenHeader)
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
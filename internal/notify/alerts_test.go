@@ -0,0 +1,233 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink collects delivered alerts
+type recordingSink struct {
+	mu     sync.Mutex
+	alerts []Alert
+	err    error
+}
+
+func (s *recordingSink) Deliver(_ context.Context, alert Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.alerts = append(s.alerts, alert)
+	return s.err
+}
+
+// TestDispatcher_Cooldown verifies duplicates are suppressed until the cooldown expires
+func TestDispatcher_Cooldown(t *testing.T) {
+	sink := &recordingSink{}
+	dispatcher := NewDispatcher([]Sink{sink}, time.Hour, nil)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	dispatcher.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	alert := Alert{Kind: KindBudget, Key: "payments/80", Severity: "MEDIUM", Message: "80% used"}
+
+	sent, err := dispatcher.Dispatch(ctx, alert)
+	require.NoError(t, err)
+	assert.True(t, sent)
+
+	now = now.Add(30 * time.Minute)
+	sent, err = dispatcher.Dispatch(ctx, alert)
+	require.NoError(t, err)
+	assert.False(t, sent, "duplicate within cooldown")
+
+	escalated := alert
+	escalated.Severity = "HIGH"
+	sent, _ = dispatcher.Dispatch(ctx, escalated)
+	assert.True(t, sent, "escalation is not a duplicate")
+
+	now = now.Add(time.Hour)
+	sent, _ = dispatcher.Dispatch(ctx, alert)
+	assert.True(t, sent, "cooldown expired")
+
+	require.Len(t, sink.alerts, 3)
+	assert.Equal(t, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), sink.alerts[0].Timestamp)
+}
+
+// TestDispatcher_SinkFailure verifies a failing sink does not stop delivery to the others
+func TestDispatcher_SinkFailure(t *testing.T) {
+	failing := &recordingSink{err: errors.New("unreachable")}
+	working := &recordingSink{}
+	dispatcher := NewDispatcher([]Sink{failing, working}, time.Hour, nil)
+
+	sent, err := dispatcher.Dispatch(context.Background(), Alert{Kind: KindCore, Key: "k", Message: "m"})
+	assert.True(t, sent)
+	assert.ErrorContains(t, err, "unreachable")
+	assert.Len(t, working.alerts, 1)
+}
+
+// TestDispatcher_AllSinksFail verifies an undelivered alert does not start the cooldown
+func TestDispatcher_AllSinksFail(t *testing.T) {
+	sink := &recordingSink{err: errors.New("unreachable")}
+	dispatcher := NewDispatcher([]Sink{sink}, time.Hour, nil)
+	alert := Alert{Kind: KindBudget, Key: "payments/100", Severity: "HIGH", Message: "exceeded"}
+
+	sent, err := dispatcher.Dispatch(context.Background(), alert)
+	assert.False(t, sent)
+	assert.ErrorContains(t, err, "unreachable")
+
+	sink.err = nil
+	sent, err = dispatcher.Dispatch(context.Background(), alert)
+	require.NoError(t, err)
+	assert.True(t, sent, "retry after a failed delivery is not a duplicate")
+
+	sent, _ = dispatcher.Dispatch(context.Background(), alert)
+	assert.False(t, sent, "duplicate of a delivered alert")
+	assert.Len(t, sink.alerts, 2)
+}
+
+// TestHub_Deliver verifies alerts reach SSE clients as MCP logging notifications
+func TestHub_Deliver(t *testing.T) {
+	hub := NewHub(nil)
+	messages, unsubscribe := hub.Subscribe()
+	defer unsubscribe()
+
+	alert := Alert{Kind: KindAnomaly, Key: "dev/anom-20240301", Severity: "CRITICAL", Message: "spike", StackName: "dev"}
+	require.NoError(t, hub.Deliver(context.Background(), alert))
+
+	var notification struct {
+		Method string `json:"method"`
+		Params struct {
+			Level  string `json:"level"`
+			Logger string `json:"logger"`
+			Data   Alert  `json:"data"`
+		} `json:"params"`
+	}
+	require.NoError(t, json.Unmarshal(<-messages, &notification))
+	assert.Equal(t, MethodMessage, notification.Method)
+	assert.Equal(t, "critical", notification.Params.Level)
+	assert.Equal(t, alertLogger, notification.Params.Logger)
+	assert.Equal(t, "dev", notification.Params.Data.StackName)
+}
+
+// TestHub_DeliverNoSubscribers verifies an alert nobody received is not
+// suppressed by the cooldown
+func TestHub_DeliverNoSubscribers(t *testing.T) {
+	hub := NewHub(nil)
+	alert := Alert{Kind: KindBudget, Key: "payments/80", Severity: "MEDIUM", Message: "over 80%"}
+
+	require.ErrorIs(t, hub.Deliver(context.Background(), alert), ErrNoSubscribers)
+
+	dispatcher := NewDispatcher([]Sink{hub}, time.Hour, nil)
+	delivered, err := dispatcher.Dispatch(context.Background(), alert)
+	require.ErrorIs(t, err, ErrNoSubscribers)
+	assert.False(t, delivered)
+
+	// A client connecting later receives the retried alert
+	messages, unsubscribe := hub.Subscribe()
+	defer unsubscribe()
+
+	delivered, err = dispatcher.Dispatch(context.Background(), alert)
+	require.NoError(t, err)
+	assert.True(t, delivered)
+	assert.Contains(t, string(<-messages), "payments/80")
+}
+
+// TestWebhookSink verifies alerts are POSTed to a local receiver
+func TestWebhookSink(t *testing.T) {
+	received := make(chan Alert, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var alert Alert
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		received <- alert
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	sink := NewWebhookSink(receiver.URL)
+	require.NoError(t, sink.Deliver(context.Background(), Alert{Kind: KindBudget, Key: "payments/100", Message: "exceeded"}))
+	assert.Equal(t, "payments/100", (<-received).Key)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	err := NewWebhookSink(failing.URL).Deliver(context.Background(), Alert{Key: "k"})
+	assert.ErrorContains(t, err, "unexpected status")
+}
+
+// TestDispatcher_ServeHTTP verifies alerts POSTed by pulumicost-core are dispatched once
+func TestDispatcher_ServeHTTP(t *testing.T) {
+	sink := &recordingSink{}
+	server := httptest.NewServer(NewDispatcher([]Sink{sink}, time.Hour, nil))
+	defer server.Close()
+
+	post := func(body string) (int, string) {
+		resp, err := http.Post(server.URL, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	status, body := post(`{"key": "stack-cost-delta", "severity": "HIGH", "message": "cost up 40%"}`)
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.JSONEq(t, `{"delivered": true}`, body)
+
+	status, body = post(`{"key": "stack-cost-delta", "severity": "HIGH", "message": "cost up 40%"}`)
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.JSONEq(t, `{"delivered": false}`, body)
+
+	status, _ = post(`{"severity": "HIGH"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	require.Len(t, sink.alerts, 1)
+	assert.Equal(t, KindCore, sink.alerts[0].Kind)
+}
+
+// TestRequireToken verifies alerts without the shared secret are rejected
+func TestRequireToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{name: "matching token", token: "s3cret", header: "s3cret", want: http.StatusAccepted},
+		{name: "missing header", token: "s3cret", want: http.StatusUnauthorized},
+		{name: "wrong token", token: "s3cret", header: "guess", want: http.StatusUnauthorized},
+		{name: "no token configured", header: "", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordingSink{}
+			server := httptest.NewServer(RequireToken(tt.token, NewDispatcher([]Sink{sink}, time.Hour, nil).ServeHTTP))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"key": "k", "message": "m"}`))
+			require.NoError(t, err)
+			if tt.header != "" {
+				req.Header.Set(TokenHeader, tt.header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want != http.StatusAccepted {
+				assert.Empty(t, sink.alerts)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Package notify delivers server-initiated MCP notifications to connected clients.
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+)
+
+// MethodToolsListChanged tells MCP clients to re-fetch tools/list
+const MethodToolsListChanged = "notifications/tools/list_changed"
+
+// subscriberBuffer is the number of undelivered notifications kept per client
+const subscriberBuffer = 16
+
+// keepaliveInterval is how often an idle stream receives an SSE comment so
+// proxies and clients do not treat it as dead
+const keepaliveInterval = 15 * time.Second
+
+// ErrNoSubscribers is returned when a notification reached no client
+var ErrNoSubscribers = errors.New("no notification subscribers")
+
+// Notification is a JSON-RPC 2.0 notification (a request without an ID)
+type Notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// Hub fans notifications out to clients subscribed over Server-Sent Events
+type Hub struct {
+	logger      *logging.Logger
+	keepalive   time.Duration
+	mu          sync.RWMutex
+	subscribers map[chan []byte]struct{}
+}
+
+// NewHub creates a new notification hub
+func NewHub(logger *logging.Logger) *Hub {
+	if logger == nil {
+		logger = logging.Default()
+	}
+	return &Hub{
+		logger:      logger,
+		keepalive:   keepaliveInterval,
+		subscribers: make(map[chan []byte]struct{}),
+	}
+}
+
+// Subscribe registers a new client and returns its message channel and an
+// unsubscribe function
+func (h *Hub) Subscribe() (<-chan []byte, func()) {
+	ch := make(chan []byte, subscriberBuffer)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers, ch)
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Notify broadcasts a notification to every subscribed client. Clients that
+// are not keeping up miss the notification rather than blocking the sender.
+func (h *Hub) Notify(method string, params any) error {
+	_, err := h.broadcast(method, params)
+	return err
+}
+
+// broadcast sends a notification to every subscribed client and returns how
+// many clients it was queued for
+func (h *Hub) broadcast(method string, params any) (int, error) {
+	data, err := json.Marshal(Notification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("marshal notification: %w", err)
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	queued := 0
+	for ch := range h.subscribers {
+		select {
+		case ch <- data:
+			queued++
+		default:
+			h.logger.Warn("dropping notification for slow client", "method", method)
+		}
+	}
+
+	h.logger.Debug("notification sent", "method", method, "subscribers", len(h.subscribers), "queued", queued)
+	return queued, nil
+}
+
+// ServeHTTP streams notifications to the client as Server-Sent Events until
+// the request is canceled. The stream is long-lived, so the server's write
+// timeout is lifted for it and idle periods are filled with keepalive comments.
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.logger.Warn("failed to clear write deadline for notification stream", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	messages, unsubscribe := h.Subscribe()
+	defer unsubscribe()
+
+	keepalive := time.NewTicker(h.keepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg := <-messages:
+			if _, err := fmt.Fprintf(w, "event: message\ndata: %s\n\n", msg); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHub_Notify verifies notifications reach every subscriber
+func TestHub_Notify(t *testing.T) {
+	hub := NewHub(nil)
+
+	first, unsubscribeFirst := hub.Subscribe()
+	defer unsubscribeFirst()
+	second, unsubscribeSecond := hub.Subscribe()
+	defer unsubscribeSecond()
+
+	require.NoError(t, hub.Notify(MethodToolsListChanged, nil))
+
+	expected := `{"jsonrpc":"2.0","method":"notifications/tools/list_changed"}`
+	assert.JSONEq(t, expected, string(<-first))
+	assert.JSONEq(t, expected, string(<-second))
+}
+
+// TestHub_Unsubscribe verifies unsubscribed clients no longer receive notifications
+func TestHub_Unsubscribe(t *testing.T) {
+	hub := NewHub(nil)
+
+	messages, unsubscribe := hub.Subscribe()
+	unsubscribe()
+	unsubscribe() // safe to call twice
+
+	require.NoError(t, hub.Notify(MethodToolsListChanged, nil))
+
+	_, open := <-messages
+	assert.False(t, open, "channel should be closed after unsubscribe")
+}
+
+// TestHub_SlowSubscriber verifies a full client buffer does not block Notify
+func TestHub_SlowSubscriber(t *testing.T) {
+	hub := NewHub(nil)
+
+	_, unsubscribe := hub.Subscribe()
+	defer unsubscribe()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < subscriberBuffer*2; i++ {
+			_ = hub.Notify(MethodToolsListChanged, nil)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Notify blocked on a slow subscriber")
+	}
+}
+
+// TestHub_ServeHTTP verifies notifications are streamed as Server-Sent Events
+func TestHub_ServeHTTP(t *testing.T) {
+	hub := NewHub(nil)
+	server := httptest.NewServer(hub)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Wait for the handler to register its subscription
+	require.Eventually(t, func() bool {
+		hub.mu.RLock()
+		defer hub.mu.RUnlock()
+		return len(hub.subscribers) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, hub.Notify(MethodToolsListChanged, nil))
+
+	reader := bufio.NewReader(resp.Body)
+	event, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event: message\n", event)
+
+	data, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(data, "data: "))
+	assert.Contains(t, data, MethodToolsListChanged)
+}
+
+// TestHub_ServeHTTP_WriteTimeout verifies the stream outlives the server's
+// write timeout and is kept alive while idle
+func TestHub_ServeHTTP_WriteTimeout(t *testing.T) {
+	hub := NewHub(nil)
+	hub.keepalive = 50 * time.Millisecond
+
+	server := httptest.NewUnstartedServer(hub)
+	server.Config.WriteTimeout = 200 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Eventually(t, func() bool {
+		hub.mu.RLock()
+		defer hub.mu.RUnlock()
+		return len(hub.subscribers) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// Outlast the write timeout before sending anything
+	time.Sleep(3 * server.Config.WriteTimeout)
+	require.NoError(t, hub.Notify(MethodToolsListChanged, nil))
+
+	reader := bufio.NewReader(resp.Body)
+	keepalives := 0
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err, "stream closed before the notification arrived")
+		if line == ": keepalive\n" {
+			keepalives++
+			continue
+		}
+		if strings.HasPrefix(line, "data: ") {
+			assert.Contains(t, line, MethodToolsListChanged)
+			break
+		}
+	}
+	assert.Positive(t, keepalives, "idle stream should receive keepalive comments")
+}
@@ -0,0 +1,317 @@
+// Package report renders plugin conformance reports for CI systems and
+// people: JUnit XML for test UIs, a versioned JSON document for tooling and
+// a Markdown summary for pull requests.
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rshade/pulumicost-mcp/gen/plugin"
+)
+
+// Format is a report output format
+type Format string
+
+// Supported formats
+const (
+	FormatJUnit    Format = "junit"
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "markdown"
+)
+
+// SchemaVersion identifies the layout of JSON reports. It changes only when
+// fields are removed or change meaning.
+const SchemaVersion = "pulumicost.conformance/v1"
+
+// defaultSuite groups test names without a "suite/" prefix
+const defaultSuite = "checks"
+
+// ParseFormat parses a format name, ignoring case
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case FormatJUnit, FormatJSON, FormatMarkdown:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid output format %q: must be junit, json or markdown", s)
+}
+
+// Render renders a report in the given format
+func Render(r *plugin.PluginValidationReport, format Format) ([]byte, error) {
+	switch format {
+	case FormatJUnit:
+		return JUnit(r)
+	case FormatJSON:
+		return JSON(r)
+	case FormatMarkdown:
+		return Markdown(r), nil
+	}
+	return nil, fmt.Errorf("invalid output format %q: must be junit, json or markdown", format)
+}
+
+// Document is the JSON report layout identified by SchemaVersion
+type Document struct {
+	SchemaVersion    string         `json:"schema_version"`
+	Plugin           string         `json:"plugin"`
+	ConformanceLevel string         `json:"conformance_level"`
+	Passed           bool           `json:"passed"`
+	Timestamp        string         `json:"timestamp,omitempty"`
+	Summary          Summary        `json:"summary"`
+	Tests            []DocumentTest `json:"tests"`
+}
+
+// Summary counts test outcomes
+type Summary struct {
+	Total      int   `json:"total"`
+	Passed     int   `json:"passed"`
+	Failed     int   `json:"failed"`
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// DocumentTest is one test outcome in a JSON report
+type DocumentTest struct {
+	Suite      string `json:"suite"`
+	Name       string `json:"name"`
+	Passed     bool   `json:"passed"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+	// ExpectedCost and ActualCost are set by cost accuracy tests
+	ExpectedCost *float64 `json:"expected_cost,omitempty"`
+	ActualCost   *float64 `json:"actual_cost,omitempty"`
+}
+
+// NewDocument converts a report to the versioned JSON layout
+func NewDocument(r *plugin.PluginValidationReport) *Document {
+	doc := &Document{
+		SchemaVersion:    SchemaVersion,
+		Plugin:           r.PluginName,
+		ConformanceLevel: r.ConformanceLevel,
+		Passed:           r.Passed,
+		Tests:            make([]DocumentTest, 0, len(r.TestResults)),
+	}
+	if r.Timestamp != nil {
+		doc.Timestamp = *r.Timestamp
+	}
+
+	for _, t := range r.TestResults {
+		suite, name := splitName(t.Name)
+		test := DocumentTest{
+			Suite:        suite,
+			Name:         name,
+			Passed:       t.Passed,
+			DurationMs:   durationMs(t),
+			ExpectedCost: t.ExpectedCost,
+			ActualCost:   t.ActualCost,
+		}
+		if t.ErrorMessage != nil {
+			test.Error = *t.ErrorMessage
+		}
+		doc.Tests = append(doc.Tests, test)
+
+		doc.Summary.Total++
+		doc.Summary.DurationMs += test.DurationMs
+		if t.Passed {
+			doc.Summary.Passed++
+		} else {
+			doc.Summary.Failed++
+		}
+	}
+
+	return doc
+}
+
+// JSON renders a report as an indented Document
+func JSON(r *plugin.PluginValidationReport) ([]byte, error) {
+	data, err := json.MarshalIndent(NewDocument(r), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("render JSON report: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+	durationMs int64
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnit renders a report as JUnit XML with one testsuite per conformance
+// suite, so CI test UIs group results the way the levels are defined
+func JUnit(r *plugin.PluginValidationReport) ([]byte, error) {
+	doc := NewDocument(r)
+	root := junitTestSuites{
+		Name:     fmt.Sprintf("%s conformance (%s)", doc.Plugin, doc.ConformanceLevel),
+		Tests:    doc.Summary.Total,
+		Failures: doc.Summary.Failed,
+		Time:     seconds(doc.Summary.DurationMs),
+	}
+
+	index := make(map[string]int)
+	for _, t := range doc.Tests {
+		i, ok := index[t.Suite]
+		if !ok {
+			i = len(root.Suites)
+			index[t.Suite] = i
+			root.Suites = append(root.Suites, junitTestSuite{
+				Name:      doc.Plugin + "/" + t.Suite,
+				Timestamp: doc.Timestamp,
+			})
+		}
+
+		suite := &root.Suites[i]
+		testCase := junitTestCase{
+			Name:      t.Name,
+			ClassName: doc.Plugin + "." + t.Suite,
+			Time:      seconds(t.DurationMs),
+		}
+		if !t.Passed {
+			testCase.Failure = &junitFailure{Message: firstLine(t.Error), Text: t.Error}
+			suite.Failures++
+		}
+		if t.ExpectedCost != nil {
+			testCase.SystemOut = fmt.Sprintf("expected_cost=%s actual_cost=%s", formatCost(t.ExpectedCost), formatCost(t.ActualCost))
+		}
+		suite.Tests++
+		suite.durationMs += t.DurationMs
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	for i := range root.Suites {
+		root.Suites[i].Time = seconds(root.Suites[i].durationMs)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return nil, fmt.Errorf("render JUnit report: %w", err)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// Markdown renders a report as a summary table followed by failure details
+func Markdown(r *plugin.PluginValidationReport) []byte {
+	doc := NewDocument(r)
+
+	result := "✅ PASSED"
+	if !doc.Passed {
+		result = "❌ FAILED"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Conformance: %s (%s) %s\n\n", escapeMarkdown(doc.Plugin), doc.ConformanceLevel, result)
+	fmt.Fprintf(&b, "%d of %d tests passed in %dms.\n\n", doc.Summary.Passed, doc.Summary.Total, doc.Summary.DurationMs)
+
+	if len(doc.Tests) > 0 {
+		b.WriteString("| Suite | Test | Result | Duration |\n")
+		b.WriteString("|-------|------|--------|----------|\n")
+		for _, t := range doc.Tests {
+			outcome := "✅ pass"
+			if !t.Passed {
+				outcome = "❌ fail"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %dms |\n", escapeMarkdown(t.Suite), escapeMarkdown(t.Name), outcome, t.DurationMs)
+		}
+	}
+
+	var accuracy []DocumentTest
+	for _, t := range doc.Tests {
+		if t.ExpectedCost != nil {
+			accuracy = append(accuracy, t)
+		}
+	}
+	if len(accuracy) > 0 {
+		b.WriteString("\n### Cost accuracy\n\n")
+		b.WriteString("| Fixture | Expected | Actual | Result |\n")
+		b.WriteString("|---------|----------|--------|--------|\n")
+		for _, t := range accuracy {
+			outcome := "✅"
+			if !t.Passed {
+				outcome = "❌"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", escapeMarkdown(strings.TrimPrefix(t.Name, "accuracy/")),
+				formatCost(t.ExpectedCost), formatCost(t.ActualCost), outcome)
+		}
+	}
+
+	if doc.Summary.Failed > 0 {
+		b.WriteString("\n### Failures\n\n")
+		for _, t := range doc.Tests {
+			if !t.Passed {
+				fmt.Fprintf(&b, "- **%s/%s**: %s\n", escapeMarkdown(t.Suite), escapeMarkdown(t.Name), escapeMarkdown(t.Error))
+			}
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// splitName separates "suite/test" names; names without a suite belong to defaultSuite
+func splitName(name string) (suite, test string) {
+	if suite, test, ok := strings.Cut(name, "/"); ok {
+		return suite, test
+	}
+	return defaultSuite, name
+}
+
+func durationMs(t *plugin.ValidationTest) int64 {
+	if t.DurationMs == nil {
+		return 0
+	}
+	return *t.DurationMs
+}
+
+// seconds formats milliseconds as the decimal seconds JUnit expects
+func seconds(ms int64) string {
+	return strconv.FormatFloat(float64(ms)/1000, 'f', 3, 64)
+}
+
+// formatCost formats an optional monthly cost; a missing cost is shown as "-"
+func formatCost(cost *float64) string {
+	if cost == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*cost, 'f', -1, 64)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// escapeMarkdown keeps table cells and list items on one line
+func escapeMarkdown(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/rshade/pulumicost-mcp/gen/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleReport() *plugin.PluginValidationReport {
+	ms := func(v int64) *int64 { return &v }
+	msg := func(s string) *string { return &s }
+	cost := func(v float64) *float64 { return &v }
+	timestamp := "2026-10-18T12:00:00Z"
+
+	return &plugin.PluginValidationReport{
+		PluginName:       "aws-fake",
+		ConformanceLevel: "STANDARD",
+		Passed:           false,
+		Timestamp:        &timestamp,
+		TestResults: []*plugin.ValidationTest{
+			{Name: "basic/startup", Passed: true, DurationMs: ms(120)},
+			{Name: "basic/health", Passed: true, DurationMs: ms(5)},
+			{Name: "standard/projected_cost", Passed: false, DurationMs: ms(40),
+				ErrorMessage: msg("aws/ec2 cost_per_month -1 | negative\nsecond line")},
+			{Name: "standard/accuracy/aws-ec2-t3-micro", Passed: true, DurationMs: ms(2), ExpectedCost: cost(7.592), ActualCost: cost(7.6)},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for input, want := range map[string]Format{"junit": FormatJUnit, " JSON ": FormatJSON, "Markdown": FormatMarkdown} {
+		got, err := ParseFormat(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := ParseFormat("pdf")
+	assert.ErrorContains(t, err, `invalid output format "pdf"`)
+}
+
+func TestJSON(t *testing.T) {
+	data, err := Render(sampleReport(), FormatJSON)
+	require.NoError(t, err)
+
+	var doc Document
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, SchemaVersion, doc.SchemaVersion)
+	assert.Equal(t, "aws-fake", doc.Plugin)
+	assert.Equal(t, Summary{Total: 4, Passed: 3, Failed: 1, DurationMs: 167}, doc.Summary)
+	require.Len(t, doc.Tests, 4)
+	assert.Equal(t, DocumentTest{Suite: "basic", Name: "startup", Passed: true, DurationMs: 120}, doc.Tests[0])
+	assert.Equal(t, "standard", doc.Tests[2].Suite)
+	assert.Contains(t, doc.Tests[2].Error, "negative")
+	assert.Equal(t, "accuracy/aws-ec2-t3-micro", doc.Tests[3].Name)
+	assert.Equal(t, 7.592, *doc.Tests[3].ExpectedCost)
+	assert.Equal(t, 7.6, *doc.Tests[3].ActualCost)
+	assert.Nil(t, doc.Tests[0].ExpectedCost)
+}
+
+func TestJUnit(t *testing.T) {
+	data, err := Render(sampleReport(), FormatJUnit)
+	require.NoError(t, err)
+
+	var root junitTestSuites
+	require.NoError(t, xml.Unmarshal(data, &root))
+	assert.Equal(t, 4, root.Tests)
+	assert.Equal(t, 1, root.Failures)
+	assert.Equal(t, "0.167", root.Time)
+
+	require.Len(t, root.Suites, 2)
+	assert.Equal(t, "aws-fake/basic", root.Suites[0].Name)
+	assert.Equal(t, 2, root.Suites[0].Tests)
+	assert.Equal(t, "0.125", root.Suites[0].Time)
+
+	failed := root.Suites[1].Cases[0]
+	assert.Equal(t, "projected_cost", failed.Name)
+	assert.Equal(t, "aws-fake.standard", failed.ClassName)
+	require.NotNil(t, failed.Failure)
+	assert.Equal(t, "aws/ec2 cost_per_month -1 | negative", failed.Failure.Message)
+	assert.Contains(t, failed.Failure.Text, "second line")
+	assert.Equal(t, "expected_cost=7.592 actual_cost=7.6", root.Suites[1].Cases[1].SystemOut)
+}
+
+func TestMarkdown(t *testing.T) {
+	data, err := Render(sampleReport(), FormatMarkdown)
+	require.NoError(t, err)
+
+	md := string(data)
+	assert.Contains(t, md, "## Conformance: aws-fake (STANDARD) ❌ FAILED")
+	assert.Contains(t, md, "3 of 4 tests passed in 167ms.")
+	assert.Contains(t, md, "| aws-ec2-t3-micro | 7.592 | 7.6 | ✅ |")
+	assert.Contains(t, md, "| basic | startup | ✅ pass | 120ms |")
+	assert.Contains(t, md, "| standard | projected_cost | ❌ fail | 40ms |")
+	assert.Contains(t, md, `- **standard/projected_cost**: aws/ec2 cost_per_month -1 \| negative second line`)
+}
+
+func TestMarkdown_Passed(t *testing.T) {
+	r := &plugin.PluginValidationReport{PluginName: "empty", ConformanceLevel: "BASIC", Passed: true}
+
+	md := string(Markdown(r))
+	assert.Contains(t, md, "✅ PASSED")
+	assert.NotContains(t, md, "Failures")
+}
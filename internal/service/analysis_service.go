@@ -2,10 +2,16 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/rshade/pulumicost-mcp/gen/analysis"
+	"github.com/rshade/pulumicost-mcp/internal/adapter"
 	"github.com/rshade/pulumicost-mcp/internal/logging"
 	"github.com/rshade/pulumicost-mcp/internal/metrics"
 	"github.com/rshade/pulumicost-mcp/internal/tracing"
@@ -14,16 +20,92 @@ import (
 
 // AnalysisService implements the analysis.Service interface
 type AnalysisService struct {
-	logger *logging.Logger
+	adapter adapter.PulumiCostAdapter
+	router  *adapter.PluginRouter
+	// costs retrieves actual costs the same way the cost tools do
+	costs *CostService
+	// budgets are the definitions from the budgets file, if one is configured
+	budgets []BudgetDefinition
+	// metricsDir is the only directory metric files are read from
+	metricsDir string
+	logger     *logging.Logger
+}
+
+// AnalysisOptions configures an Analysis Service
+type AnalysisOptions struct {
+	// MaxConcurrent bounds the plugins called at once
+	MaxConcurrent int
+	// Budgets are evaluated by list_budgets, check_all_budgets and the sentinel
+	Budgets []BudgetDefinition
+	// MetricsDir is the directory metric_file paths must lie in; empty
+	// disables metric_file
+	MetricsDir string
 }
 
 // NewAnalysisService creates a new Analysis Service instance
-func NewAnalysisService(dataSource interface{}, logger *logging.Logger) *AnalysisService {
+func NewAnalysisService(pulumiAdapter adapter.PulumiCostAdapter, logger *logging.Logger) *AnalysisService {
+	return NewAnalysisServiceWithRouter(pulumiAdapter, nil, 1, logger)
+}
+
+// NewAnalysisServiceWithRouter creates an Analysis Service that takes actual
+// costs and recommendations for each provider from the plugins router finds,
+// calling at most maxConcurrent plugins at once. A nil router leaves them to
+// pulumicost-core.
+func NewAnalysisServiceWithRouter(pulumiAdapter adapter.PulumiCostAdapter, router *adapter.PluginRouter, maxConcurrent int, logger *logging.Logger) *AnalysisService {
+	return NewAnalysisServiceWithBudgets(pulumiAdapter, router, maxConcurrent, nil, logger)
+}
+
+// NewAnalysisServiceWithBudgets creates an Analysis Service that also
+// evaluates the given budget definitions
+func NewAnalysisServiceWithBudgets(pulumiAdapter adapter.PulumiCostAdapter, router *adapter.PluginRouter, maxConcurrent int, budgets []BudgetDefinition, logger *logging.Logger) *AnalysisService {
+	return NewAnalysisServiceWithOptions(pulumiAdapter, router, AnalysisOptions{MaxConcurrent: maxConcurrent, Budgets: budgets}, logger)
+}
+
+// NewAnalysisServiceWithOptions creates an Analysis Service configured by options
+func NewAnalysisServiceWithOptions(pulumiAdapter adapter.PulumiCostAdapter, router *adapter.PluginRouter, options AnalysisOptions, logger *logging.Logger) *AnalysisService {
 	return &AnalysisService{
-		logger: logger,
+		adapter:    pulumiAdapter,
+		router:     router,
+		costs:      NewCostServiceWithRouter(pulumiAdapter, router, options.MaxConcurrent, logger),
+		budgets:    options.Budgets,
+		metricsDir: options.MetricsDir,
+		logger:     logger,
 	}
 }
 
+// recommendationLookback is the window of actual costs used to find a stack's resources
+const recommendationLookback = 30 * 24 * time.Hour
+
+// recommendationDefaults are the risk and implementation effort assumed for
+// a recommendation category when the source does not report them
+type recommendationDefaults struct {
+	risk   string
+	effort string
+}
+
+// recommendationCategories are the recommendation types the API exposes
+var recommendationCategories = map[string]recommendationDefaults{
+	"RIGHTSIZING":          {risk: "MEDIUM", effort: "LOW"},
+	"RESERVED_INSTANCES":   {risk: "MEDIUM", effort: "LOW"},
+	"SPOT_INSTANCES":       {risk: "HIGH", effort: "MEDIUM"},
+	"STORAGE_OPTIMIZATION": {risk: "LOW", effort: "LOW"},
+	"CLEANUP":              {risk: "LOW", effort: "LOW"},
+	"SCHEDULING":           {risk: "LOW", effort: "MEDIUM"},
+	"OTHER":                {risk: "MEDIUM", effort: "MEDIUM"},
+}
+
+// categoryAliases maps the singular category names some sources use to API types
+var categoryAliases = map[string]string{
+	"RESERVED_INSTANCE": "RESERVED_INSTANCES",
+	"SAVINGS_PLAN":      "RESERVED_INSTANCES",
+	"SAVINGS_PLANS":     "RESERVED_INSTANCES",
+	"COMMITTED_USE":     "RESERVED_INSTANCES",
+	"SPOT_INSTANCE":     "SPOT_INSTANCES",
+}
+
+// levelRank orders the LOW, MEDIUM and HIGH levels of confidence, risk and effort
+var levelRank = map[string]int{"LOW": 0, "MEDIUM": 1, "HIGH": 2}
+
 // GetRecommendations returns cost optimization recommendations
 func (s *AnalysisService) GetRecommendations(ctx context.Context, payload *analysis.GetRecommendationsPayload) (*analysis.GetRecommendationsResult, error) {
 	start := time.Now()
@@ -43,37 +125,33 @@ func (s *AnalysisService) GetRecommendations(ctx context.Context, payload *analy
 
 	tracing.SetAttributes(ctx, attribute.String("stack_name", payload.StackName))
 
-	recommendations := []*analysis.Recommendation{
-		{
-			ID:               "rec-001",
-			Type:             "RIGHTSIZING",
-			ResourceUrn:      "urn:pulumi:prod::myapp::aws:ec2/instance:Instance::web-1",
-			CurrentCost:      120.00,
-			ProjectedSavings: 245.50,
-			Confidence:       "HIGH",
-			Description:      "EC2 instance is over-provisioned and can be downsized from t3.large to t3.medium",
-			ActionSteps:      []string{"Review instance metrics", "Update instance type", "Monitor performance"},
-		},
-		{
-			ID:               "rec-002",
-			Type:             "RESERVED_INSTANCES",
-			ResourceUrn:      "urn:pulumi:prod::myapp::aws:rds/instance:Instance::db",
-			CurrentCost:      250.00,
-			ProjectedSavings: 180.00,
-			Confidence:       "MEDIUM",
-			Description:      "Database running 24/7 can benefit from 1-year Reserved Instance pricing",
-			ActionSteps:      []string{"Analyze usage patterns", "Purchase Reserved Instance", "Update infrastructure"},
-		},
-		{
-			ID:               "rec-003",
-			Type:             "SPOT_INSTANCES",
-			ResourceUrn:      "urn:pulumi:dev::myapp::aws:ec2/instance:Instance::dev-1",
-			CurrentCost:      80.00,
-			ProjectedSavings: 56.00,
-			Confidence:       "MEDIUM",
-			Description:      "Development workload can tolerate interruptions and save 70% on compute",
-			ActionSteps:      []string{"Implement graceful shutdown", "Configure Spot requests", "Test failure scenarios"},
-		},
+	if s.adapter == nil {
+		err := fmt.Errorf("no cost data source configured")
+		s.logger.WithService("analysis").ErrorJSON("adapter call failed", err, nil)
+		metrics.RecordError("analysis", "get_recommendations", "adapter")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+
+	resources, resourceFailures, err := s.stackResources(ctx, payload.StackName)
+	if err != nil {
+		s.logger.WithService("analysis").ErrorJSON("adapter call failed", err, nil)
+		metrics.RecordError("analysis", "get_recommendations", "adapter")
+		tracing.RecordError(ctx, err)
+		return nil, fmt.Errorf("failed to list stack resources: %w", err)
+	}
+
+	found, sourceFailures, err := s.collectRecommendations(ctx, payload.StackName, resources)
+	if err != nil {
+		s.logger.WithService("analysis").ErrorJSON("adapter call failed", err, nil)
+		metrics.RecordError("analysis", "get_recommendations", "adapter")
+		tracing.RecordError(ctx, err)
+		return nil, fmt.Errorf("failed to get recommendations: %w", err)
+	}
+
+	recommendations := make([]*analysis.Recommendation, 0, len(found))
+	for i := range found {
+		recommendations = append(recommendations, convertRecommendation(&found[i], resources))
 	}
 
 	// Apply filters
@@ -81,7 +159,7 @@ func (s *AnalysisService) GetRecommendations(ctx context.Context, payload *analy
 		filtered := []*analysis.Recommendation{}
 		typeMap := make(map[string]bool)
 		for _, t := range payload.RecommendationTypes {
-			typeMap[t] = true
+			typeMap[strings.ToUpper(t)] = true
 		}
 		for _, rec := range recommendations {
 			if typeMap[rec.Type] {
@@ -101,6 +179,30 @@ func (s *AnalysisService) GetRecommendations(ctx context.Context, payload *analy
 		recommendations = filtered
 	}
 
+	if payload.MaxRiskLevel != nil {
+		maxRisk := levelRank[strings.ToUpper(*payload.MaxRiskLevel)]
+		filtered := []*analysis.Recommendation{}
+		for _, rec := range recommendations {
+			if levelRank[rec.RiskLevel] <= maxRisk {
+				filtered = append(filtered, rec)
+			}
+		}
+		recommendations = filtered
+	}
+
+	if payload.MaxImplementationEffort != nil {
+		maxEffort := levelRank[strings.ToUpper(*payload.MaxImplementationEffort)]
+		filtered := []*analysis.Recommendation{}
+		for _, rec := range recommendations {
+			if levelRank[rec.ImplementationEffort] <= maxEffort {
+				filtered = append(filtered, rec)
+			}
+		}
+		recommendations = filtered
+	}
+
+	sortRecommendations(recommendations, payload.SortBy)
+
 	// Calculate total savings
 	totalSavings := 0.0
 	for _, rec := range recommendations {
@@ -114,18 +216,750 @@ func (s *AnalysisService) GetRecommendations(ctx context.Context, payload *analy
 		attribute.Float64("total_savings", totalSavings),
 	)
 
+	failures := slices.Concat(resourceFailures, sourceFailures)
 	s.logger.WithService("analysis").InfoJSON("recommendations generated", map[string]interface{}{
 		"stack_name":           payload.StackName,
 		"recommendation_count": len(recommendations),
 		"total_savings":        totalSavings,
+		"partial":              len(failures) > 0,
 		"duration_ms":          time.Since(start).Milliseconds(),
 	})
 
 	return &analysis.GetRecommendationsResult{
-		Recommendations: recommendations,
+		Recommendations:   recommendations,
+		Partial:           len(failures) > 0,
+		UnpricedProviders: unpricedProviders(failures),
 	}, nil
 }
 
+// stackResources returns the resources of a stack by URN, taken from its
+// actual costs over the lookback window, and the providers whose resources
+// could not be listed
+func (s *AnalysisService) stackResources(ctx context.Context, stackName string) (map[string]adapter.ResourceCost, []adapter.ProviderFailure, error) {
+	end := time.Now().UTC()
+	timeRange := adapter.TimeRange{
+		Start: end.Add(-recommendationLookback).Format(time.RFC3339),
+		End:   end.Format(time.RFC3339),
+	}
+
+	result, err := s.costs.actualCost(ctx, stackName, timeRange, "", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resources := make(map[string]adapter.ResourceCost, len(result.Result.Resources))
+	for _, res := range result.Result.Resources {
+		resources[res.Urn] = res
+	}
+	return resources, result.Failures, nil
+}
+
+// collectRecommendations asks each provider's optimization plugin for
+// recommendations on the stack, and pulumicost-core for providers no plugin
+// covers. Only recommendations for the provider's resources in the stack are
+// kept. A failing source is skipped and returned as a failure of the providers
+// it covers, unless every source fails.
+func (s *AnalysisService) collectRecommendations(ctx context.Context, stackName string, resources map[string]adapter.ResourceCost) ([]adapter.Recommendation, []adapter.ProviderFailure, error) {
+	urnsByProvider := make(map[string]map[string]bool)
+	for urn, res := range resources {
+		provider := res.ProviderName()
+		if urnsByProvider[provider] == nil {
+			urnsByProvider[provider] = make(map[string]bool)
+		}
+		urnsByProvider[provider][urn] = true
+	}
+
+	var recommendations []adapter.Recommendation
+	var failures []adapter.ProviderFailure
+	var lastErr error
+	sources, failed := 0, 0
+	covered := make(map[string]bool)
+
+	if s.router != nil {
+		providers, err := s.router.Providers(ctx, adapter.CostKindOptimization)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, provider := range providers {
+			urns := urnsByProvider[provider]
+			if len(urns) == 0 {
+				continue
+			}
+			covered[provider] = true
+			sources++
+
+			var result *adapter.RecommendationResult
+			pluginName, err := s.router.Route(ctx, provider, adapter.CostKindOptimization, func(ctx context.Context, pluginName string) error {
+				var err error
+				result, err = s.adapter.GetRecommendationsWithAdapter(ctx, stackName, pluginName)
+				return err
+			})
+			if err != nil {
+				s.logger.WithService("analysis").Warn("optimization plugins failed", "provider", provider, "error", err)
+				failures = append(failures, adapter.ProviderFailure{Provider: provider, Plugin: pluginName, Err: err})
+				failed++
+				lastErr = err
+				continue
+			}
+			s.logger.WithService("analysis").Debug("recommendations from plugin", "provider", provider, "plugin", pluginName)
+			recommendations = append(recommendations, scopeRecommendations(result.Recommendations, urns)...)
+		}
+	}
+
+	uncovered := make(map[string]bool)
+	var uncoveredProviders []string
+	for provider, urns := range urnsByProvider {
+		if covered[provider] {
+			continue
+		}
+		uncoveredProviders = append(uncoveredProviders, provider)
+		for urn := range urns {
+			uncovered[urn] = true
+		}
+	}
+	if len(uncovered) > 0 || sources == 0 {
+		sources++
+		result, err := s.adapter.GetRecommendations(ctx, stackName)
+		if err != nil {
+			s.logger.WithService("analysis").Warn("pulumicost-core recommendations failed", "error", err)
+			if len(uncoveredProviders) == 0 {
+				uncoveredProviders = []string{adapter.CoreSource}
+			}
+			for _, provider := range uncoveredProviders {
+				failures = append(failures, adapter.ProviderFailure{Provider: provider, Plugin: adapter.CoreSource, Err: err})
+			}
+			failed++
+			lastErr = err
+		} else {
+			recommendations = append(recommendations, scopeRecommendations(result.Recommendations, uncovered)...)
+		}
+	}
+
+	if failed == sources {
+		return nil, nil, lastErr
+	}
+	return recommendations, failures, nil
+}
+
+// scopeRecommendations keeps the recommendations affecting the given URNs,
+// narrowing each one's affected resources to those URNs
+func scopeRecommendations(recommendations []adapter.Recommendation, urns map[string]bool) []adapter.Recommendation {
+	var scoped []adapter.Recommendation
+	for _, rec := range recommendations {
+		var affected []string
+		for _, urn := range rec.URNs() {
+			if urns[urn] {
+				affected = append(affected, urn)
+			}
+		}
+		if len(affected) == 0 {
+			continue
+		}
+		rec.ResourceUrn = affected[0]
+		rec.ResourceUrns = affected
+		scoped = append(scoped, rec)
+	}
+	return scoped
+}
+
+// scopedFailures returns the failures that affect scope; providers outside
+// the scope do not count towards it
+func scopedFailures(failures []adapter.ProviderFailure, scope BudgetScope) []adapter.ProviderFailure {
+	var scoped []adapter.ProviderFailure
+	for _, failure := range failures {
+		if len(scope.Providers) == 0 || failure.Provider == adapter.CoreSource ||
+			slices.Contains(scope.Providers, strings.ToLower(failure.Provider)) {
+			scoped = append(scoped, failure)
+		}
+	}
+	return scoped
+}
+
+// unpricedProviders returns the providers of failed fan-out requests, sorted
+func unpricedProviders(failures []adapter.ProviderFailure) []string {
+	providers := make([]string, 0, len(failures))
+	for _, failure := range failures {
+		if !slices.Contains(providers, failure.Provider) {
+			providers = append(providers, failure.Provider)
+		}
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// urnName returns the resource name at the end of a URN, or "" for an empty URN
+func urnName(urn string) string {
+	if i := strings.LastIndex(urn, "::"); i >= 0 {
+		return urn[i+2:]
+	}
+	return urn
+}
+
+// convertRecommendation converts a scoped adapter recommendation to the API
+// type. Categories the API does not know become OTHER; a missing ID, current
+// cost, risk or effort is filled in from the stack and the category.
+func convertRecommendation(rec *adapter.Recommendation, resources map[string]adapter.ResourceCost) *analysis.Recommendation {
+	category := strings.ToUpper(rec.Type)
+	if category == "" {
+		category = strings.ToUpper(rec.Category)
+	}
+	if alias, ok := categoryAliases[category]; ok {
+		category = alias
+	}
+	defaults, ok := recommendationCategories[category]
+	if !ok {
+		category = "OTHER"
+		defaults = recommendationCategories[category]
+	}
+
+	id := rec.ID
+	if id == "" {
+		id = strings.ToLower(strings.ReplaceAll(category, "_", "-"))
+		if name := urnName(rec.ResourceUrn); name != "" {
+			id += "-" + name
+		}
+	}
+
+	currentCost := 0.0
+	if rec.CurrentCost != nil {
+		currentCost = *rec.CurrentCost
+	} else {
+		for _, urn := range rec.ResourceUrns {
+			currentCost += resources[urn].MonthlyCost
+		}
+	}
+
+	return &analysis.Recommendation{
+		ID:                   id,
+		Type:                 category,
+		ResourceUrn:          rec.ResourceUrn,
+		ResourceUrns:         rec.ResourceUrns,
+		CurrentCost:          currentCost,
+		ProjectedSavings:     rec.ProjectedSavings,
+		Confidence:           normalizeLevel(rec.Confidence, "MEDIUM"),
+		RiskLevel:            normalizeLevel(rec.RiskLevel, defaults.risk),
+		ImplementationEffort: normalizeLevel(rec.ImplementationEffort, defaults.effort),
+		Description:          rec.Description,
+		ActionSteps:          rec.ActionSteps,
+		Source:               rec.Source,
+		BreakEven:            convertBreakEven(rec.BreakEven),
+	}
+}
+
+// convertBreakEven converts break-even terms reported by a recommendation's source
+func convertBreakEven(breakEven *adapter.BreakEven) *analysis.BreakEven {
+	if breakEven == nil {
+		return nil
+	}
+	converted := &analysis.BreakEven{
+		UpfrontCost:     breakEven.UpfrontCost,
+		BreakEvenMonths: breakEven.BreakEvenMonths,
+	}
+	if breakEven.Term != "" {
+		term := breakEven.Term
+		converted.Term = &term
+	}
+	return converted
+}
+
+// normalizeLevel upper-cases a LOW, MEDIUM or HIGH level, returning fallback for anything else
+func normalizeLevel(level, fallback string) string {
+	level = strings.ToUpper(level)
+	if _, ok := levelRank[level]; !ok {
+		return fallback
+	}
+	return level
+}
+
+// sortRecommendations orders recommendations by highest savings, or by
+// lowest risk or effort and then highest savings
+func sortRecommendations(recommendations []*analysis.Recommendation, sortBy string) {
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		switch strings.ToUpper(sortBy) {
+		case "RISK":
+			if a.RiskLevel != b.RiskLevel {
+				return levelRank[a.RiskLevel] < levelRank[b.RiskLevel]
+			}
+		case "EFFORT":
+			if a.ImplementationEffort != b.ImplementationEffort {
+				return levelRank[a.ImplementationEffort] < levelRank[b.ImplementationEffort]
+			}
+		}
+		return a.ProjectedSavings > b.ProjectedSavings
+	})
+}
+
+// FindIdleResources flags resources that incur cost over a time range while
+// reporting near-zero usage, returned as CLEANUP recommendations with the
+// cost and usage that show they are idle
+func (s *AnalysisService) FindIdleResources(ctx context.Context, payload *analysis.FindIdleResourcesPayload) (*analysis.FindIdleResourcesResult, error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "AnalysisService.FindIdleResources")
+	defer span.End()
+
+	s.logger.WithService("analysis").Info("finding idle resources")
+	metrics.RecordCostQuery("idle_resources")
+
+	if payload.StackName == "" {
+		err := fmt.Errorf("stack name cannot be empty")
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "find_idle_resources", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+	if payload.TimeRange == nil {
+		err := fmt.Errorf("time range is required")
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "find_idle_resources", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+	if payload.UsageThreshold < 0 {
+		err := fmt.Errorf("%w: usage threshold cannot be negative", adapter.ErrInvalidInput)
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "find_idle_resources", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+
+	tracing.SetAttributes(ctx,
+		attribute.String("stack_name", payload.StackName),
+		attribute.String("time_range_start", payload.TimeRange.Start),
+		attribute.String("time_range_end", payload.TimeRange.End),
+	)
+
+	if s.adapter == nil {
+		err := fmt.Errorf("no cost data source configured")
+		s.logger.WithService("analysis").ErrorJSON("adapter call failed", err, nil)
+		metrics.RecordError("analysis", "find_idle_resources", "adapter")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+
+	timeRange := adapter.TimeRange{
+		Start: payload.TimeRange.Start,
+		End:   payload.TimeRange.End,
+	}
+	fanOutResult, err := s.costs.actualCost(ctx, payload.StackName, timeRange, "daily", nil)
+	if err != nil {
+		s.logger.WithService("analysis").ErrorJSON("adapter call failed", err, nil)
+		metrics.RecordError("analysis", "find_idle_resources", "adapter")
+		tracing.RecordError(ctx, err)
+		return nil, fmt.Errorf("failed to get actual costs: %w", err)
+	}
+
+	threshold := payload.UsageThreshold
+
+	result := &analysis.FindIdleResourcesResult{
+		Recommendations:   []*analysis.Recommendation{},
+		UnmeasuredUrns:    []string{},
+		Partial:           fanOutResult.Partial(),
+		UnpricedProviders: unpricedProviders(fanOutResult.Failures),
+	}
+	for i := range fanOutResult.Result.Resources {
+		res := &fanOutResult.Result.Resources[i]
+		if res.Urn == "" {
+			// Nothing to point a cleanup at
+			continue
+		}
+		cost, usage, unit, measured := res.Usage()
+		if len(res.DataPoints) == 0 {
+			cost = res.MonthlyCost
+		}
+		if cost <= 0 || cost < payload.MinimumCost {
+			continue
+		}
+		if !measured {
+			result.UnmeasuredUrns = append(result.UnmeasuredUrns, res.Urn)
+			continue
+		}
+
+		evidence := usageEvidence(res, cost, usage, unit, threshold, payload.TimeRange)
+		if usage/float64(evidence.DataPoints) > threshold {
+			continue
+		}
+
+		result.Recommendations = append(result.Recommendations, idleRecommendation(res, evidence, fanOutResult.Result.Currency))
+		result.TotalIdleCost += res.MonthlyCost
+	}
+	sortRecommendations(result.Recommendations, "SAVINGS")
+
+	// Record metrics
+	metrics.RecordRequest("analysis", "find_idle_resources", time.Since(start))
+	tracing.SetAttributes(ctx,
+		attribute.Int("idle_count", len(result.Recommendations)),
+		attribute.Float64("total_idle_cost", result.TotalIdleCost),
+	)
+
+	s.logger.WithService("analysis").InfoJSON("idle resources found", map[string]interface{}{
+		"stack_name":      payload.StackName,
+		"idle_count":      len(result.Recommendations),
+		"unmeasured":      len(result.UnmeasuredUrns),
+		"total_idle_cost": result.TotalIdleCost,
+		"duration_ms":     time.Since(start).Milliseconds(),
+	})
+
+	return result, nil
+}
+
+// usageEvidence summarizes the measured data points of a resource
+func usageEvidence(res *adapter.ResourceCost, cost, usage float64, unit string, threshold float64, timeRange *analysis.TimeRange) *analysis.UsageEvidence {
+	evidence := &analysis.UsageEvidence{
+		Cost:        cost,
+		UsageAmount: usage,
+		UsageUnit:   unit,
+		TimeRange:   timeRange,
+	}
+	for _, point := range res.DataPoints {
+		if point.UsageAmount == nil {
+			continue
+		}
+		evidence.DataPoints++
+		if *point.UsageAmount <= threshold {
+			evidence.IdleDataPoints++
+		}
+	}
+	return evidence
+}
+
+// idleRecommendation builds the CLEANUP recommendation for an idle resource.
+// Confidence grows with the number of measured periods without any usage.
+func idleRecommendation(res *adapter.ResourceCost, evidence *analysis.UsageEvidence, currency string) *analysis.Recommendation {
+	confidence := "MEDIUM"
+	switch {
+	case evidence.DataPoints < 3:
+		confidence = "LOW"
+	case evidence.UsageAmount == 0 && evidence.DataPoints >= 7:
+		confidence = "HIGH"
+	}
+
+	source := adapter.CoreSource
+	if res.Adapter != nil {
+		source = *res.Adapter
+	}
+	if currency == "" {
+		currency = "USD"
+	}
+
+	defaults := recommendationCategories["CLEANUP"]
+	name := res.Name
+	if name == "" {
+		name = urnName(res.Urn)
+	}
+
+	return &analysis.Recommendation{
+		ID:                   "cleanup-" + name,
+		Type:                 "CLEANUP",
+		ResourceUrn:          res.Urn,
+		ResourceUrns:         []string{res.Urn},
+		CurrentCost:          res.MonthlyCost,
+		ProjectedSavings:     res.MonthlyCost,
+		Confidence:           confidence,
+		RiskLevel:            defaults.risk,
+		ImplementationEffort: defaults.effort,
+		Description: fmt.Sprintf("%s cost %.2f %s with %g %s of usage across %d of %d periods at or near zero",
+			name, evidence.Cost, currency, evidence.UsageAmount, evidence.UsageUnit, evidence.IdleDataPoints, evidence.DataPoints),
+		ActionSteps: []string{
+			"Confirm nothing depends on the resource",
+			"Snapshot or back up any data it holds",
+			"Remove it from the Pulumi program and run pulumi up",
+		},
+		Source:   source,
+		Evidence: evidence,
+	}
+}
+
+// AnalyzeCommitmentCoverage reports, per provider and service, how much of
+// a stack's compute and database spend is covered by reservations, savings
+// plans or committed use, with the RESERVED_INSTANCES recommendations and
+// their break-even terms. Recommendations are best effort: if no source
+// answers, coverage is still returned.
+func (s *AnalysisService) AnalyzeCommitmentCoverage(ctx context.Context, payload *analysis.AnalyzeCommitmentCoveragePayload) (*analysis.CommitmentCoverage, error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "AnalysisService.AnalyzeCommitmentCoverage")
+	defer span.End()
+
+	s.logger.WithService("analysis").Info("analyzing commitment coverage")
+	metrics.RecordCostQuery("commitment_coverage")
+
+	if payload.StackName == "" {
+		err := fmt.Errorf("stack name cannot be empty")
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "analyze_commitment_coverage", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+	if payload.TimeRange == nil {
+		err := fmt.Errorf("time range is required")
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "analyze_commitment_coverage", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+	rangeStart, rangeEnd, err := parseTimeRange(payload.TimeRange)
+	if err != nil {
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "analyze_commitment_coverage", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+
+	tracing.SetAttributes(ctx,
+		attribute.String("stack_name", payload.StackName),
+		attribute.String("time_range_start", payload.TimeRange.Start),
+		attribute.String("time_range_end", payload.TimeRange.End),
+	)
+
+	if s.adapter == nil {
+		err := fmt.Errorf("no cost data source configured")
+		s.logger.WithService("analysis").ErrorJSON("adapter call failed", err, nil)
+		metrics.RecordError("analysis", "analyze_commitment_coverage", "adapter")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+
+	timeRange := adapter.TimeRange{
+		Start: payload.TimeRange.Start,
+		End:   payload.TimeRange.End,
+	}
+	fanOutResult, err := s.costs.actualCost(ctx, payload.StackName, timeRange, "daily", nil)
+	if err != nil {
+		s.logger.WithService("analysis").ErrorJSON("adapter call failed", err, nil)
+		metrics.RecordError("analysis", "analyze_commitment_coverage", "adapter")
+		tracing.RecordError(ctx, err)
+		return nil, fmt.Errorf("failed to get actual costs: %w", err)
+	}
+
+	groups, unknown := buildCommitmentCoverage(fanOutResult.Result, rangeStart, rangeEnd)
+
+	resources := make(map[string]adapter.ResourceCost, len(fanOutResult.Result.Resources))
+	for _, res := range fanOutResult.Result.Resources {
+		resources[res.Urn] = res
+	}
+	found, _, err := s.collectRecommendations(ctx, payload.StackName, resources)
+	if err != nil {
+		s.logger.WithService("analysis").Warn("commitment recommendations unavailable", "stack_name", payload.StackName, "error", err)
+	}
+	recommendations := make([]*analysis.Recommendation, 0, len(found))
+	for i := range found {
+		recommendations = append(recommendations, convertRecommendation(&found[i], resources))
+	}
+	sortRecommendations(recommendations, "SAVINGS")
+	attachCommitmentRecommendations(groups, recommendations)
+
+	currency := fanOutResult.Result.Currency
+	if currency == "" {
+		currency = "USD"
+	}
+	result := &analysis.CommitmentCoverage{
+		StackName:          payload.StackName,
+		TimeRange:          payload.TimeRange,
+		Currency:           currency,
+		Groups:             groups,
+		UnknownBillingUrns: unknown,
+		Partial:            fanOutResult.Partial(),
+		UnpricedProviders:  unpricedProviders(fanOutResult.Failures),
+	}
+	for _, g := range groups {
+		result.TotalSpend += g.TotalSpend
+		result.CommittedSpend += g.CommittedSpend
+		result.OnDemandSpend += g.OnDemandSpend
+		result.SteadyStateSpend += g.SteadyStateSpend
+	}
+	result.TotalSpend = roundCents(result.TotalSpend)
+	result.CommittedSpend = roundCents(result.CommittedSpend)
+	result.OnDemandSpend = roundCents(result.OnDemandSpend)
+	result.SteadyStateSpend = roundCents(result.SteadyStateSpend)
+	result.CoveragePercentage = coveragePercentage(result.CommittedSpend, result.TotalSpend)
+
+	// Record metrics
+	metrics.RecordRequest("analysis", "analyze_commitment_coverage", time.Since(start))
+	tracing.SetAttributes(ctx,
+		attribute.Int("group_count", len(groups)),
+		attribute.Float64("coverage_percentage", result.CoveragePercentage),
+	)
+
+	s.logger.WithService("analysis").InfoJSON("commitment coverage analyzed", map[string]interface{}{
+		"stack_name":          payload.StackName,
+		"group_count":         len(groups),
+		"coverage_percentage": result.CoveragePercentage,
+		"steady_state_spend":  result.SteadyStateSpend,
+		"duration_ms":         time.Since(start).Milliseconds(),
+	})
+
+	return result, nil
+}
+
+// ComputeUnitCost divides the actual cost of a scope by a business metric
+// per daily, weekly or monthly window. Only windows with metric data are
+// returned. The daily actual costs of every stack in the scope are narrowed
+// to the scope's tags and providers and then added up.
+func (s *AnalysisService) ComputeUnitCost(ctx context.Context, payload *analysis.ComputeUnitCostPayload) (*analysis.UnitCost, error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "AnalysisService.ComputeUnitCost")
+	defer span.End()
+
+	s.logger.WithService("analysis").Info("computing unit cost")
+	metrics.RecordCostQuery("unit_cost")
+
+	if payload.Scope == nil || len(payload.Scope.Stacks) == 0 || slices.Contains(payload.Scope.Stacks, "") {
+		err := fmt.Errorf("%w: scope must name at least one stack; tags and providers only narrow the named stacks", adapter.ErrInvalidInput)
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "compute_unit_cost", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+
+	granularity := strings.ToLower(payload.Granularity)
+	if granularity == "" {
+		granularity = "daily"
+	}
+	if granularity != "daily" && granularity != "weekly" && granularity != "monthly" {
+		err := fmt.Errorf("%w: granularity must be daily, weekly or monthly", adapter.ErrInvalidInput)
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "compute_unit_cost", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+
+	series, err := metricSeries(payload.MetricPoints, payload.MetricFile, s.metricsDir)
+	if err != nil {
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "compute_unit_cost", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+	windows, err := unitCostWindows(series, granularity)
+	if err != nil {
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "compute_unit_cost", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+
+	timeRange := adapter.TimeRange{
+		Start: windows[0].Window.Start,
+		End:   windows[len(windows)-1].Window.End,
+	}
+	tracing.SetAttributes(ctx,
+		attribute.StringSlice("stacks", payload.Scope.Stacks),
+		attribute.String("granularity", granularity),
+		attribute.String("time_range_start", timeRange.Start),
+		attribute.String("time_range_end", timeRange.End),
+	)
+
+	if s.adapter == nil {
+		err := fmt.Errorf("no cost data source configured")
+		s.logger.WithService("analysis").ErrorJSON("adapter call failed", err, nil)
+		metrics.RecordError("analysis", "compute_unit_cost", "adapter")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+
+	scope := BudgetScope{Stacks: payload.Scope.Stacks, Tags: payload.Scope.Tags}
+	for _, provider := range payload.Scope.Providers {
+		scope.Providers = append(scope.Providers, strings.ToLower(provider))
+	}
+	results := make([]*adapter.CostResult, 0, len(scope.Stacks))
+	var failures []adapter.ProviderFailure
+	currency := ""
+	for _, stack := range scope.Stacks {
+		fanOutResult, err := s.costs.actualCost(ctx, stack, timeRange, "daily", nil)
+		if err == nil && fanOutResult.Result.Currency != "" {
+			if currency == "" {
+				currency = fanOutResult.Result.Currency
+			} else if !strings.EqualFold(currency, fanOutResult.Result.Currency) {
+				err = fmt.Errorf("stack %s reports costs in %s, other stacks in %s", stack, fanOutResult.Result.Currency, currency)
+			}
+		}
+		if err != nil {
+			s.logger.WithService("analysis").ErrorJSON("adapter call failed", err, nil)
+			metrics.RecordError("analysis", "compute_unit_cost", "adapter")
+			tracing.RecordError(ctx, err)
+			return nil, fmt.Errorf("failed to get actual costs of %s: %w", stack, err)
+		}
+		results = append(results, scopeCosts(fanOutResult.Result, scope))
+		failures = append(failures, scopedFailures(fanOutResult.Failures, scope)...)
+	}
+
+	costs := mergeBudgetSpend(results)
+	if costs.TotalMonthly > 0 && len(costs.Breakdown.Daily) == 0 {
+		err := fmt.Errorf("cost source reported no daily costs to divide")
+		s.logger.WithService("analysis").ErrorJSON("adapter call failed", err, nil)
+		metrics.RecordError("analysis", "compute_unit_cost", "adapter")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+	applyUnitCosts(windows, costs, perUnits(payload.Per))
+
+	metricName := "units"
+	if payload.MetricName != nil && *payload.MetricName != "" {
+		metricName = *payload.MetricName
+	}
+	if currency == "" {
+		currency = "USD"
+	}
+	result := &analysis.UnitCost{
+		MetricName:        metricName,
+		Per:               perUnits(payload.Per),
+		Granularity:       granularity,
+		Currency:          currency,
+		TimeRange:         &analysis.TimeRange{Start: timeRange.Start, End: timeRange.End},
+		Points:            windows,
+		Partial:           len(failures) > 0,
+		UnpricedProviders: unpricedProviders(failures),
+	}
+	for _, window := range windows {
+		result.TotalCost += window.Cost
+		result.TotalMetric += window.MetricValue
+	}
+	result.TotalCost = roundCents(result.TotalCost)
+	result.UnitCost = unitCost(result.TotalCost, result.TotalMetric, result.Per)
+
+	// Record metrics
+	metrics.RecordRequest("analysis", "compute_unit_cost", time.Since(start))
+	tracing.SetAttributes(ctx,
+		attribute.Int("window_count", len(windows)),
+		attribute.Float64("total_cost", result.TotalCost),
+	)
+
+	s.logger.WithService("analysis").InfoJSON("unit cost computed", map[string]interface{}{
+		"stacks":       scope.Stacks,
+		"metric_name":  metricName,
+		"window_count": len(windows),
+		"total_cost":   result.TotalCost,
+		"partial":      result.Partial,
+		"duration_ms":  time.Since(start).Milliseconds(),
+	})
+
+	return result, nil
+}
+
+// perUnits returns the metric units a unit cost is for, defaulting to one
+func perUnits(per float64) float64 {
+	if per <= 0 {
+		return 1
+	}
+	return per
+}
+
+// parseTimeRange parses an RFC 3339 time range whose end is after its start
+func parseTimeRange(timeRange *analysis.TimeRange) (time.Time, time.Time, error) {
+	start, err := time.Parse(time.RFC3339, timeRange.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start time format: %w: %w", adapter.ErrInvalidInput, err)
+	}
+	end, err := time.Parse(time.RFC3339, timeRange.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end time format: %w: %w", adapter.ErrInvalidInput, err)
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: time range end must be after its start", adapter.ErrInvalidInput)
+	}
+	return start, end, nil
+}
+
 // DetectAnomalies detects unusual spending patterns
 func (s *AnalysisService) DetectAnomalies(ctx context.Context, payload *analysis.DetectAnomaliesPayload) (*analysis.DetectAnomaliesResult, error) {
 	start := time.Now()
@@ -150,38 +984,56 @@ func (s *AnalysisService) DetectAnomalies(ctx context.Context, payload *analysis
 		return nil, err
 	}
 
+	sensitivity := strings.ToUpper(payload.Sensitivity)
+	if sensitivity == "" {
+		sensitivity = "MEDIUM"
+	}
+	threshold, ok := sensitivityThresholds[sensitivity]
+	if !ok {
+		err := fmt.Errorf("%w: unknown sensitivity %q", adapter.ErrInvalidInput, payload.Sensitivity)
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "detect_anomalies", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+
+	rangeStart, rangeEnd, err := parseTimeRange(payload.TimeRange)
+	if err != nil {
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "detect_anomalies", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+
 	tracing.SetAttributes(ctx,
 		attribute.String("stack_name", payload.StackName),
-		attribute.String("sensitivity", payload.Sensitivity),
+		attribute.String("sensitivity", sensitivity),
 	)
 
-	anomalies := []*analysis.Anomaly{
-		{
-			ID:               "anom-001",
-			Timestamp:        "2024-01-15T14:30:00Z",
-			ResourceUrns:     []string{"urn:pulumi:prod::myapp::aws:ec2/instance:Instance::web-3"},
-			Severity:         "HIGH",
-			CurrentCost:      425.00,
-			BaselineCost:     150.00,
-			DeviationPercent: 183.33,
-			PotentialCauses:  []string{"Unexpected spike in EC2 instance hours", "Auto-scaling triggered", "Instance not properly terminated"},
-		},
+	if s.adapter == nil {
+		err := fmt.Errorf("no cost data source configured")
+		s.logger.WithService("analysis").ErrorJSON("adapter call failed", err, nil)
+		metrics.RecordError("analysis", "detect_anomalies", "adapter")
+		tracing.RecordError(ctx, err)
+		return nil, err
 	}
 
-	// Add more anomalies for higher sensitivity
-	if payload.Sensitivity == "HIGH" {
-		anomalies = append(anomalies, &analysis.Anomaly{
-			ID:               "anom-002",
-			Timestamp:        "2024-01-20T09:15:00Z",
-			ResourceUrns:     []string{"urn:pulumi:prod::myapp::aws:s3/bucket:Bucket::data"},
-			Severity:         "MEDIUM",
-			CurrentCost:      120.00,
-			BaselineCost:     75.00,
-			DeviationPercent: 60.00,
-			PotentialCauses:  []string{"Increased S3 storage usage", "Large file uploads", "Lifecycle policy not applied"},
-		})
+	// Fetch the baseline window before the range along with the range itself
+	timeRange := adapter.TimeRange{
+		Start: rangeStart.Add(-anomalyBaselineWindow).Format(time.RFC3339),
+		End:   rangeEnd.Format(time.RFC3339),
+	}
+	fanOutResult, err := s.costs.actualCost(ctx, payload.StackName, timeRange, "daily", nil)
+	if err != nil {
+		s.logger.WithService("analysis").ErrorJSON("adapter call failed", err, nil)
+		metrics.RecordError("analysis", "detect_anomalies", "adapter")
+		tracing.RecordError(ctx, err)
+		return nil, fmt.Errorf("failed to get actual costs: %w", err)
 	}
 
+	series := seriesFromResult(fanOutResult.Result)
+	anomalies := groupAnomalies(findDeviations(series, rangeStart, rangeEnd, threshold))
+
 	// Record metrics
 	metrics.RecordRequest("analysis", "detect_anomalies", time.Since(start))
 	tracing.SetAttributes(ctx, attribute.Int("anomaly_count", len(anomalies)))
@@ -189,117 +1041,424 @@ func (s *AnalysisService) DetectAnomalies(ctx context.Context, payload *analysis
 	s.logger.WithService("analysis").InfoJSON("anomalies detected", map[string]interface{}{
 		"stack_name":    payload.StackName,
 		"anomaly_count": len(anomalies),
-		"sensitivity":   payload.Sensitivity,
+		"series_count":  len(series),
+		"sensitivity":   sensitivity,
+		"partial":       fanOutResult.Partial(),
 		"duration_ms":   time.Since(start).Milliseconds(),
 	})
 
 	return &analysis.DetectAnomaliesResult{
-		Anomalies: anomalies,
+		Anomalies:         anomalies,
+		Partial:           fanOutResult.Partial(),
+		UnpricedProviders: unpricedProviders(fanOutResult.Failures),
 	}, nil
 }
 
-// Forecast generates cost forecasts
+// Forecast requests a cost forecast for the stack from a forecasting plugin,
+// falling back to pulumicost-core
 func (s *AnalysisService) Forecast(ctx context.Context, payload *analysis.ForecastPayload) (*analysis.Forecast2, error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "AnalysisService.Forecast")
+	defer span.End()
+
+	s.logger.WithService("analysis").Info("forecasting costs")
+	metrics.RecordCostQuery("forecast")
+
 	if payload.StackName == "" {
-		return nil, fmt.Errorf("stack name cannot be empty")
+		err := fmt.Errorf("stack name cannot be empty")
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "forecast", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
 	}
 	if payload.ForecastPeriod == nil {
-		return nil, fmt.Errorf("forecast period is required")
+		err := fmt.Errorf("forecast period is required")
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "forecast", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+	if payload.ConfidenceLevel <= 0 || payload.ConfidenceLevel >= 1 {
+		err := fmt.Errorf("%w: confidence level must be between 0 and 1 exclusive, got %v", adapter.ErrInvalidInput, payload.ConfidenceLevel)
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "forecast", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
 	}
 
-	dataPoints := []*analysis.ForecastPoint{
-		{
-			Timestamp:     "2024-02-01T00:00:00Z",
-			PredictedCost: 850.00,
-			LowerBound:    800.00,
-			UpperBound:    900.00,
-		},
-		{
-			Timestamp:     "2024-02-15T00:00:00Z",
-			PredictedCost: 875.00,
-			LowerBound:    825.00,
-			UpperBound:    925.00,
-		},
-		{
-			Timestamp:     "2024-02-29T00:00:00Z",
-			PredictedCost: 900.00,
-			LowerBound:    850.00,
-			UpperBound:    950.00,
-		},
+	periodStart, periodEnd, err := parseTimeRange(payload.ForecastPeriod)
+	if err != nil {
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "forecast", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
+	granularity := forecastGranularity(periodStart, periodEnd)
+
+	tracing.SetAttributes(ctx,
+		attribute.String("stack_name", payload.StackName),
+		attribute.String("granularity", granularity),
+	)
+
+	result, err := s.requestForecast(ctx, payload.StackName, adapter.TimeRange{
+		Start: payload.ForecastPeriod.Start,
+		End:   payload.ForecastPeriod.End,
+	}, granularity, payload.ConfidenceLevel)
+	if err != nil {
+		s.logger.WithService("analysis").ErrorJSON("adapter call failed", err, nil)
+		tracing.RecordError(ctx, err)
+		if errors.Is(err, adapter.ErrForecastUnsupported) {
+			metrics.RecordError("analysis", "forecast", "unsupported")
+			return nil, &analysis.UnsupportedError{Message: err.Error()}
+		}
+		metrics.RecordError("analysis", "forecast", "adapter")
+		return nil, fmt.Errorf("failed to get forecast: %w", err)
+	}
+
+	dataPoints := make([]*analysis.ForecastPoint, 0, len(result.DataPoints))
+	for _, point := range result.DataPoints {
+		dataPoints = append(dataPoints, &analysis.ForecastPoint{
+			Timestamp:     point.Timestamp,
+			PredictedCost: math.Max(point.PredictedCost, 0),
+			LowerBound:    math.Max(point.LowerBound, 0),
+			UpperBound:    math.Max(point.UpperBound, 0),
+		})
 	}
 
-	return &analysis.Forecast2{
+	forecast := &analysis.Forecast2{
 		StackName:       payload.StackName,
 		ForecastPeriod:  payload.ForecastPeriod,
 		DataPoints:      dataPoints,
 		ConfidenceLevel: payload.ConfidenceLevel,
-		Methodology:     "Linear regression with seasonal adjustment based on historical spending patterns",
-	}, nil
+		Methodology:     result.Methodology,
+		Granularity:     granularity,
+		Source:          result.Source,
+	}
+	if result.HistoryStart != "" && result.HistoryEnd != "" {
+		forecast.HistoryWindow = &analysis.TimeRange{Start: result.HistoryStart, End: result.HistoryEnd}
+	}
+
+	// Record metrics
+	metrics.RecordRequest("analysis", "forecast", time.Since(start))
+	tracing.SetAttributes(ctx, attribute.Int("data_point_count", len(dataPoints)))
+
+	s.logger.WithService("analysis").InfoJSON("forecast generated", map[string]interface{}{
+		"stack_name":       payload.StackName,
+		"source":           result.Source,
+		"granularity":      granularity,
+		"data_point_count": len(dataPoints),
+		"duration_ms":      time.Since(start).Milliseconds(),
+	})
+
+	return forecast, nil
+}
+
+// requestForecast asks the forecasting plugins that cover every provider of
+// the stack in turn, then pulumicost-core, for a forecast. A plugin covering
+// only some providers would forecast part of the stack as if it were all of
+// it, so such plugins are not asked. The error wraps
+// adapter.ErrForecastUnsupported unless some source that can forecast failed
+// for another reason.
+func (s *AnalysisService) requestForecast(ctx context.Context, stackName string, period adapter.TimeRange, granularity string, confidence float64) (*adapter.ForecastResult, error) {
+	if s.adapter == nil {
+		return nil, fmt.Errorf("%w: no cost data source configured", adapter.ErrForecastUnsupported)
+	}
+
+	var failure error
+	if s.router != nil {
+		forecasters, err := s.router.Providers(ctx, adapter.CostKindForecast)
+		if err != nil {
+			return nil, err
+		}
+		var providers []string
+		if len(forecasters) > 0 {
+			providers, err = s.stackProviders(ctx, stackName)
+			if err != nil {
+				if errors.Is(err, adapter.ErrInvalidInput) || ctx.Err() != nil {
+					return nil, err
+				}
+				s.logger.WithService("analysis").Warn("stack providers unknown, forecasting with pulumicost-core", "error", err)
+			}
+		}
+		if len(providers) > 0 {
+			var result *adapter.ForecastResult
+			pluginName, err := s.router.RouteAll(ctx, providers, adapter.CostKindForecast, func(ctx context.Context, pluginName string) error {
+				var err error
+				result, err = s.adapter.GetForecast(ctx, stackName, period, granularity, confidence, pluginName)
+				return err
+			})
+			if err == nil {
+				s.logger.WithService("analysis").Debug("forecast from plugin", "providers", providers, "plugin", pluginName)
+				return result, nil
+			}
+			if errors.Is(err, adapter.ErrInvalidInput) || ctx.Err() != nil {
+				return nil, err
+			}
+			s.logger.WithService("analysis").Warn("forecasting plugins failed", "providers", providers, "error", err)
+			if !errors.Is(err, adapter.ErrForecastUnsupported) && !errors.Is(err, adapter.ErrNoPluginAvailable) {
+				failure = err
+			}
+		}
+	}
+
+	result, err := s.adapter.GetForecast(ctx, stackName, period, granularity, confidence, "")
+	if err == nil {
+		return result, nil
+	}
+	if errors.Is(err, adapter.ErrForecastUnsupported) && failure != nil {
+		return nil, failure
+	}
+	return nil, err
 }
 
-// TrackBudget monitors spending against budget
+// stackProviders returns the providers of a stack's resources, taken from its
+// actual costs over the lookback window. It fails when a provider may be
+// missing because part of the stack could not be priced.
+func (s *AnalysisService) stackProviders(ctx context.Context, stackName string) ([]string, error) {
+	end := time.Now().UTC()
+	timeRange := adapter.TimeRange{
+		Start: end.Add(-recommendationLookback).Format(time.RFC3339),
+		End:   end.Format(time.RFC3339),
+	}
+
+	result, err := s.costs.actualCost(ctx, stackName, timeRange, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, res := range result.Result.Resources {
+		provider := res.ProviderName()
+		if provider == "" {
+			return nil, fmt.Errorf("resource %s has no provider", res.Urn)
+		}
+		seen[provider] = true
+	}
+	for _, failure := range result.Failures {
+		if failure.Provider == adapter.CoreSource {
+			return nil, fmt.Errorf("resources outside plugin coverage could not be read: %w", failure.Err)
+		}
+		seen[strings.ToLower(failure.Provider)] = true
+	}
+
+	providers := make([]string, 0, len(seen))
+	for provider := range seen {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	return providers, nil
+}
+
+// forecastGranularity spaces forecast points daily for up to a month,
+// weekly for up to half a year and monthly beyond that
+func forecastGranularity(start, end time.Time) string {
+	switch days := end.Sub(start).Hours() / 24; {
+	case days <= 31:
+		return "daily"
+	case days <= 183:
+		return "weekly"
+	default:
+		return "monthly"
+	}
+}
+
+// TrackBudget monitors period-to-date actual spending against a budget
 func (s *AnalysisService) TrackBudget(ctx context.Context, payload *analysis.TrackBudgetPayload) (*analysis.Budget, error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "AnalysisService.TrackBudget")
+	defer span.End()
+
+	s.logger.WithService("analysis").Info("tracking budget")
+	metrics.RecordCostQuery("budget")
+
 	if payload.StackName == "" {
-		return nil, fmt.Errorf("stack name cannot be empty")
+		err := fmt.Errorf("stack name cannot be empty")
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "track_budget", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
 	}
 	if payload.BudgetAmount <= 0 {
-		return nil, fmt.Errorf("budget amount must be positive")
+		err := fmt.Errorf("budget amount must be positive")
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "track_budget", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
 	}
 
-	// Mock current spending
-	currentSpending := 750.00
-	remaining := payload.BudgetAmount - currentSpending
-	burnRate := 25.00 // Daily burn rate
-	percentageUsed := (currentSpending / payload.BudgetAmount) * 100
+	period := strings.ToUpper(payload.Period)
+	periodStart, periodEnd, err := budgetWindow(period, start)
+	if err != nil {
+		s.logger.WithService("analysis").ErrorJSON("validation failed", err, nil)
+		metrics.RecordError("analysis", "track_budget", "validation")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
 
-	// Calculate projected end date
-	daysRemaining := int(remaining / burnRate)
-	projectedEndDate := time.Now().AddDate(0, 0, daysRemaining).Format(time.RFC3339)
+	tracing.SetAttributes(ctx,
+		attribute.String("stack_name", payload.StackName),
+		attribute.String("period", period),
+	)
 
-	// Determine status
-	status := "OK"
-	switch {
-	case percentageUsed >= 100:
-		status = "EXCEEDED"
-	case percentageUsed >= 90:
-		status = "CRITICAL"
-	case percentageUsed >= 80:
-		status = "WARNING"
-	}
-
-	// Generate alerts based on thresholds
-	var alerts []any
-	if payload.AlertThresholds != nil {
-		for _, threshold := range payload.AlertThresholds {
-			if percentageUsed >= threshold {
-				severity := "INFO"
-				switch {
-				case percentageUsed >= 100:
-					severity = "CRITICAL"
-				case percentageUsed >= 90:
-					severity = "HIGH"
-				case percentageUsed >= 80:
-					severity = "MEDIUM"
-				}
+	if s.adapter == nil {
+		err := fmt.Errorf("no cost data source configured")
+		s.logger.WithService("analysis").ErrorJSON("adapter call failed", err, nil)
+		metrics.RecordError("analysis", "track_budget", "adapter")
+		tracing.RecordError(ctx, err)
+		return nil, err
+	}
 
-				alerts = append(alerts, map[string]interface{}{
-					"threshold":     threshold,
-					"current_spend": currentSpending,
-					"severity":      severity,
-					"message":       fmt.Sprintf("Budget utilization at %.1f%% (threshold: %.1f%%)", percentageUsed, threshold),
-					"timestamp":     time.Now().Format(time.RFC3339),
-				})
-			}
+	timeRange := adapter.TimeRange{
+		Start: periodStart.Format(time.RFC3339),
+		End:   start.UTC().Format(time.RFC3339),
+	}
+	fanOutResult, err := s.costs.actualCost(ctx, payload.StackName, timeRange, "daily", nil)
+	if err != nil {
+		s.logger.WithService("analysis").ErrorJSON("adapter call failed", err, nil)
+		metrics.RecordError("analysis", "track_budget", "adapter")
+		tracing.RecordError(ctx, err)
+		return nil, fmt.Errorf("failed to get actual costs: %w", err)
+	}
+
+	budget := evaluateBudget(payload.BudgetAmount, period, payload.AlertThresholds, fanOutResult.Result, periodStart, periodEnd, start)
+	budget.Partial = fanOutResult.Partial()
+	budget.UnpricedProviders = unpricedProviders(fanOutResult.Failures)
+
+	// Record metrics
+	metrics.RecordRequest("analysis", "track_budget", time.Since(start))
+	tracing.SetAttributes(ctx, attribute.String("status", budget.Status))
+
+	s.logger.WithService("analysis").InfoJSON("budget tracked", map[string]interface{}{
+		"stack_name":       payload.StackName,
+		"period":           period,
+		"current_spending": budget.CurrentSpending,
+		"status":           budget.Status,
+		"alert_count":      len(budget.Alerts),
+		"partial":          budget.Partial,
+		"duration_ms":      time.Since(start).Milliseconds(),
+	})
+
+	return budget, nil
+}
+
+// ListBudgets lists the budgets defined in the budgets file
+func (s *AnalysisService) ListBudgets(ctx context.Context, payload *analysis.ListBudgetsPayload) (*analysis.ListBudgetsResult, error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "AnalysisService.ListBudgets")
+	defer span.End()
+
+	budgets := make([]*analysis.BudgetDefinition, 0, len(s.budgets))
+	for _, def := range s.budgets {
+		if payload.StackName != nil && !def.covers(*payload.StackName) {
+			continue
+		}
+		budgets = append(budgets, convertBudgetDefinition(def))
+	}
+
+	// Record metrics
+	metrics.RecordRequest("analysis", "list_budgets", time.Since(start))
+	tracing.SetAttributes(ctx, attribute.Int("budget_count", len(budgets)))
+
+	return &analysis.ListBudgetsResult{
+		Budgets: budgets,
+	}, nil
+}
+
+// CheckAllBudgets evaluates every defined budget against its period-to-date
+// actual spending. A budget that cannot be evaluated reports the error
+// instead of failing the whole check.
+func (s *AnalysisService) CheckAllBudgets(ctx context.Context, payload *analysis.CheckAllBudgetsPayload) (*analysis.CheckAllBudgetsResult, error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "AnalysisService.CheckAllBudgets")
+	defer span.End()
+
+	s.logger.WithService("analysis").Info("checking all budgets")
+	metrics.RecordCostQuery("budget")
+
+	checks := make([]*analysis.BudgetCheck, 0, len(s.budgets))
+	statusCounts := make(map[string]int)
+	for _, def := range s.budgets {
+		if payload.StackName != nil && !def.covers(*payload.StackName) {
+			continue
+		}
+
+		check := &analysis.BudgetCheck{Definition: convertBudgetDefinition(def)}
+		budget, err := s.evaluateDefinedBudget(ctx, def, start)
+		if err != nil {
+			s.logger.WithService("analysis").Warn("budget could not be evaluated", "budget", def.Name, "error", err)
+			message := err.Error()
+			check.Error = &message
+			statusCounts["ERROR"]++
+		} else {
+			check.Budget = budget
+			statusCounts[budget.Status]++
 		}
+		checks = append(checks, check)
 	}
 
-	return &analysis.Budget{
-		BudgetAmount:     payload.BudgetAmount,
-		CurrentSpending:  currentSpending,
-		Remaining:        remaining,
-		BurnRate:         &burnRate,
-		ProjectedEndDate: &projectedEndDate,
-		Status:           status,
-		Alerts:           alerts,
+	// Most used budgets first, those that could not be evaluated last
+	sort.SliceStable(checks, func(i, j int) bool {
+		if (checks[i].Budget == nil) != (checks[j].Budget == nil) {
+			return checks[i].Budget != nil
+		}
+		if checks[i].Budget == nil {
+			return false
+		}
+		return checks[i].Budget.CurrentSpending/checks[i].Budget.BudgetAmount >
+			checks[j].Budget.CurrentSpending/checks[j].Budget.BudgetAmount
+	})
+
+	// Record metrics
+	metrics.RecordRequest("analysis", "check_all_budgets", time.Since(start))
+	tracing.SetAttributes(ctx, attribute.Int("budget_count", len(checks)))
+
+	s.logger.WithService("analysis").InfoJSON("budgets checked", map[string]interface{}{
+		"budget_count":  len(checks),
+		"status_counts": statusCounts,
+		"duration_ms":   time.Since(start).Milliseconds(),
+	})
+
+	return &analysis.CheckAllBudgetsResult{
+		Checks:       checks,
+		StatusCounts: statusCounts,
 	}, nil
 }
+
+// evaluateDefinedBudget sums the period-to-date actual spending the budget's
+// scope covers across its stacks and compares it against the budget
+func (s *AnalysisService) evaluateDefinedBudget(ctx context.Context, def BudgetDefinition, now time.Time) (*analysis.Budget, error) {
+	if s.adapter == nil {
+		return nil, fmt.Errorf("no cost data source configured")
+	}
+
+	periodStart, periodEnd, err := budgetWindow(def.Period, now)
+	if err != nil {
+		return nil, err
+	}
+	timeRange := adapter.TimeRange{
+		Start: periodStart.Format(time.RFC3339),
+		End:   now.UTC().Format(time.RFC3339),
+	}
+
+	results := make([]*adapter.CostResult, 0, len(def.Scope.Stacks))
+	var failures []adapter.ProviderFailure
+	for _, stack := range def.Scope.Stacks {
+		fanOutResult, err := s.costs.actualCost(ctx, stack, timeRange, "daily", nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get actual costs of %s: %w", stack, err)
+		}
+		result := fanOutResult.Result
+		if result.Currency != "" && !strings.EqualFold(result.Currency, def.Currency) {
+			return nil, fmt.Errorf("stack %s reports costs in %s, budget is in %s", stack, result.Currency, def.Currency)
+		}
+		results = append(results, scopeCosts(result, def.Scope))
+		failures = append(failures, scopedFailures(fanOutResult.Failures, def.Scope)...)
+	}
+
+	budget := evaluateBudget(def.Amount, def.Period, def.AlertThresholds, mergeBudgetSpend(results), periodStart, periodEnd, now)
+	currency := def.Currency
+	budget.Currency = &currency
+	budget.Partial = len(failures) > 0
+	budget.UnpricedProviders = unpricedProviders(failures)
+	return budget, nil
+}
@@ -2,16 +2,24 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/rshade/pulumicost-mcp/gen/analysis"
+	"github.com/rshade/pulumicost-mcp/internal/adapter"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 // TestGetRecommendations tests getting cost optimization recommendations
 func TestGetRecommendations(t *testing.T) {
-	service := NewAnalysisService(nil, nil)
+	mockAdapter := adapter.NewPulumiCostAdapter("../adapter/testdata/mock_pulumicost.sh")
+	service := NewAnalysisService(mockAdapter, nil)
 	ctx := context.Background()
 
 	payload := &analysis.GetRecommendationsPayload{
@@ -22,22 +30,50 @@ func TestGetRecommendations(t *testing.T) {
 
 	require.NoError(t, err)
 	require.NotNil(t, result)
-	assert.NotEmpty(t, result.Recommendations)
+	require.Len(t, result.Recommendations, 3, "recommendations outside the stack are dropped")
+	assert.Equal(t, []string{"reserved-instances-db", "scheduling-web-server", "rightsize-web-server"},
+		recommendationIDs(result.Recommendations), "sorted by savings")
 
-	// Verify recommendation structure
-	rec := result.Recommendations[0]
-	assert.NotEmpty(t, rec.ID)
-	assert.NotEmpty(t, rec.Type)
-	assert.Greater(t, rec.ProjectedSavings, 0.0)
+	// Missing fields are filled in from the stack's resources and the category
+	db := result.Recommendations[0]
+	assert.Equal(t, "RESERVED_INSTANCES", db.Type)
+	assert.Equal(t, "urn:pulumi:dev::myapp::aws:rds/instance:Instance::db", db.ResourceUrn)
+	assert.Equal(t, []string{db.ResourceUrn}, db.ResourceUrns)
+	assert.Equal(t, 32.0, db.CurrentCost)
+	assert.Equal(t, "MEDIUM", db.Confidence)
+	assert.Equal(t, "MEDIUM", db.RiskLevel)
+	assert.Equal(t, "LOW", db.ImplementationEffort)
+	assert.Equal(t, adapter.CoreSource, db.Source)
+	require.NotNil(t, db.BreakEven, "break-even terms are passed through")
+	assert.Equal(t, 7.5, db.BreakEven.BreakEvenMonths)
+	assert.Equal(t, "1y", *db.BreakEven.Term)
+
+	// Affected resources are narrowed to the stack
+	scheduling := result.Recommendations[1]
+	assert.Equal(t, "SCHEDULING", scheduling.Type)
+	assert.Equal(t, []string{
+		"urn:pulumi:dev::myapp::aws:ec2/instance:Instance::web-server",
+		"urn:pulumi:dev::myapp::aws:rds/instance:Instance::db",
+	}, scheduling.ResourceUrns)
+	assert.Equal(t, 42.5, scheduling.CurrentCost)
+	assert.Equal(t, "LOW", scheduling.RiskLevel)
+	assert.Equal(t, "MEDIUM", scheduling.ImplementationEffort)
+
+	rec := result.Recommendations[2]
+	assert.Equal(t, "RIGHTSIZING", rec.Type)
+	assert.Equal(t, "HIGH", rec.Confidence)
+	assert.Equal(t, 10.5, rec.CurrentCost)
 	assert.NotEmpty(t, rec.Description)
+	assert.Equal(t, adapter.CoreSource, rec.Source)
 }
 
 // TestGetRecommendations_WithFilters tests filtering recommendations
 func TestGetRecommendations_WithFilters(t *testing.T) {
-	service := NewAnalysisService(nil, nil)
+	mockAdapter := adapter.NewPulumiCostAdapter("../adapter/testdata/mock_pulumicost.sh")
+	service := NewAnalysisService(mockAdapter, nil)
 	ctx := context.Background()
 
-	minSavings := 100.0
+	minSavings := 5.0
 	payload := &analysis.GetRecommendationsPayload{
 		StackName:           "my-stack",
 		RecommendationTypes: []string{"RIGHTSIZING"},
@@ -47,19 +83,440 @@ func TestGetRecommendations_WithFilters(t *testing.T) {
 	result, err := service.GetRecommendations(ctx, payload)
 
 	require.NoError(t, err)
-	assert.NotEmpty(t, result.Recommendations)
+	require.Len(t, result.Recommendations, 1)
+	assert.Equal(t, "RIGHTSIZING", result.Recommendations[0].Type)
+
+	minSavings = 100.0
+	result, err = service.GetRecommendations(ctx, payload)
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Recommendations)
+}
+
+// TestGetRecommendations_RiskAndEffort tests filtering and sorting by risk and implementation effort
+func TestGetRecommendations_RiskAndEffort(t *testing.T) {
+	mockAdapter := adapter.NewPulumiCostAdapter("../adapter/testdata/mock_pulumicost.sh")
+	service := NewAnalysisService(mockAdapter, nil)
+	ctx := context.Background()
+	level := func(s string) *string { return &s }
+
+	tests := map[string]struct {
+		payload *analysis.GetRecommendationsPayload
+		want    []string
+	}{
+		"sort by risk": {
+			payload: &analysis.GetRecommendationsPayload{SortBy: "RISK"},
+			want:    []string{"scheduling-web-server", "reserved-instances-db", "rightsize-web-server"},
+		},
+		"sort by effort": {
+			payload: &analysis.GetRecommendationsPayload{SortBy: "EFFORT"},
+			want:    []string{"reserved-instances-db", "rightsize-web-server", "scheduling-web-server"},
+		},
+		"low risk": {
+			payload: &analysis.GetRecommendationsPayload{MaxRiskLevel: level("LOW")},
+			want:    []string{"scheduling-web-server"},
+		},
+		"quick wins": {
+			payload: &analysis.GetRecommendationsPayload{MaxRiskLevel: level("MEDIUM"), MaxImplementationEffort: level("LOW")},
+			want:    []string{"reserved-instances-db", "rightsize-web-server"},
+		},
+		"new categories": {
+			payload: &analysis.GetRecommendationsPayload{RecommendationTypes: []string{"SCHEDULING", "CLEANUP"}},
+			want:    []string{"scheduling-web-server"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tt.payload.StackName = "my-stack"
+			result, err := service.GetRecommendations(ctx, tt.payload)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, recommendationIDs(result.Recommendations))
+		})
+	}
+}
+
+func recommendationIDs(recommendations []*analysis.Recommendation) []string {
+	ids := make([]string, len(recommendations))
+	for i, rec := range recommendations {
+		ids[i] = rec.ID
+	}
+	return ids
+}
+
+// TestGetRecommendations_RoutesToPlugin verifies providers with an optimization plugin are served by it
+func TestGetRecommendations_RoutesToPlugin(t *testing.T) {
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "aws-optimizer"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "aws-optimizer", "plugin.json"), []byte(`{
+		"name": "aws-optimizer",
+		"version": "1.0.0",
+		"providers": "aws",
+		"capabilities": {"supports_optimization": true}
+	}`), 0644))
+
+	mockAdapter := adapter.NewPulumiCostAdapter("../adapter/testdata/mock_pulumicost.sh")
+	router := adapter.NewPluginRouter(adapter.NewPluginAdapter(pluginDir, nil), nil, nil)
+	service := NewAnalysisServiceWithRouter(mockAdapter, router, 1, nil)
+
+	result, err := service.GetRecommendations(context.Background(), &analysis.GetRecommendationsPayload{
+		StackName: "my-stack",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Recommendations, 3)
+	for _, rec := range result.Recommendations {
+		assert.Equal(t, "aws-optimizer", rec.Source)
+	}
+	assert.False(t, result.Partial)
+	assert.Empty(t, result.UnpricedProviders)
+}
+
+// TestGetRecommendations_Partial verifies providers whose resources or
+// recommendations could not be retrieved are reported
+func TestGetRecommendations_Partial(t *testing.T) {
+	t.Run("resources", func(t *testing.T) {
+		core, router := failingPluginSource(t)
+		result, err := NewAnalysisServiceWithRouter(core, router, 2, nil).GetRecommendations(context.Background(), &analysis.GetRecommendationsPayload{
+			StackName: "my-stack",
+		})
+
+		require.NoError(t, err)
+		assert.True(t, result.Partial)
+		assert.Equal(t, []string{"kubernetes"}, result.UnpricedProviders)
+		assert.NotEmpty(t, result.Recommendations, "aws is still served by pulumicost-core")
+	})
+
+	t.Run("source", func(t *testing.T) {
+		pluginDir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "k8s-optimizer"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "k8s-optimizer", "plugin.json"), []byte(`{
+			"name": "k8s-optimizer",
+			"version": "1.0.0",
+			"providers": "kubernetes",
+			"capabilities": {"supports_optimization": true}
+		}`), 0644))
+
+		mock, err := filepath.Abs("../adapter/testdata/mock_pulumicost.sh")
+		require.NoError(t, err)
+		corePath := filepath.Join(t.TempDir(), "pulumicost")
+		require.NoError(t, os.WriteFile(corePath, []byte(`#!/bin/bash
+if [[ " $* " == *" --adapter k8s-optimizer "* ]]; then
+  echo "optimizer unreachable" >&2
+  exit 1
+fi
+if [ "$1" = "recommend" ]; then
+  exec `+mock+` "$@"
+fi
+cat <<'EOF'
+{"currency": "USD", "resources": [
+  {"urn": "urn:pulumi:dev::myapp::aws:ec2/instance:Instance::web-server", "provider": "aws", "monthly_cost": 10.5},
+  {"urn": "urn:pulumi:dev::myapp::kubernetes:apps/v1:Deployment::api", "provider": "kubernetes", "monthly_cost": 20}
+]}
+EOF
+`), 0755))
+
+		router := adapter.NewPluginRouter(adapter.NewPluginAdapter(pluginDir, nil), nil, nil)
+		result, err := NewAnalysisServiceWithRouter(adapter.NewPulumiCostAdapter(corePath), router, 1, nil).GetRecommendations(context.Background(), &analysis.GetRecommendationsPayload{
+			StackName: "my-stack",
+		})
+
+		require.NoError(t, err)
+		assert.True(t, result.Partial)
+		assert.Equal(t, []string{"kubernetes"}, result.UnpricedProviders)
+		require.NotEmpty(t, result.Recommendations)
+		for _, rec := range result.Recommendations {
+			assert.Contains(t, rec.ResourceUrn, "web-server")
+		}
+	})
+}
+
+// TestGetRecommendations_NoDataSource verifies recommendations require a cost data source
+func TestGetRecommendations_NoDataSource(t *testing.T) {
+	service := NewAnalysisService(nil, nil)
+
+	_, err := service.GetRecommendations(context.Background(), &analysis.GetRecommendationsPayload{
+		StackName: "my-stack",
+	})
+
+	assert.ErrorContains(t, err, "no cost data source configured")
+}
+
+// TestFindIdleResources verifies resources with cost and near-zero usage become cleanup recommendations
+func TestFindIdleResources(t *testing.T) {
+	mockAdapter := adapter.NewPulumiCostAdapter("../adapter/testdata/mock_pulumicost.sh")
+	service := NewAnalysisService(mockAdapter, nil)
+	ctx := context.Background()
+
+	timeRange := &analysis.TimeRange{Start: "2024-01-01T00:00:00Z", End: "2024-01-04T00:00:00Z"}
+	result, err := service.FindIdleResources(ctx, &analysis.FindIdleResourcesPayload{
+		StackName: "my-stack",
+		TimeRange: timeRange,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Recommendations, 1, "web-server is in use")
+	assert.Empty(t, result.UnmeasuredUrns)
+	assert.Equal(t, 32.0, result.TotalIdleCost)
+
+	rec := result.Recommendations[0]
+	assert.Equal(t, "cleanup-db", rec.ID)
+	assert.Equal(t, "CLEANUP", rec.Type)
+	assert.Equal(t, "urn:pulumi:dev::myapp::aws:rds/instance:Instance::db", rec.ResourceUrn)
+	assert.Equal(t, 32.0, rec.ProjectedSavings)
+	assert.Equal(t, "MEDIUM", rec.Confidence, "three idle days")
+	assert.Equal(t, "LOW", rec.RiskLevel)
+	assert.Equal(t, adapter.CoreSource, rec.Source)
+
+	require.NotNil(t, rec.Evidence)
+	assert.InDelta(t, 3.15, rec.Evidence.Cost, 1e-9)
+	assert.Equal(t, 0.0, rec.Evidence.UsageAmount)
+	assert.Equal(t, "Connections", rec.Evidence.UsageUnit)
+	assert.Equal(t, 3, rec.Evidence.DataPoints)
+	assert.Equal(t, 3, rec.Evidence.IdleDataPoints)
+	assert.Equal(t, timeRange, rec.Evidence.TimeRange)
+
+	// A high enough threshold treats web-server's 24 hours a day as idle
+	result, err = service.FindIdleResources(ctx, &analysis.FindIdleResourcesPayload{
+		StackName:      "my-stack",
+		TimeRange:      timeRange,
+		UsageThreshold: 24,
+		MinimumCost:    2,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cleanup-db"}, recommendationIDs(result.Recommendations), "web-server cost 1.05 over the range is below the minimum")
+}
+
+// TestConvertRecommendation_EmptyURN verifies recommendations and resources
+// without a URN do not break ID generation
+func TestConvertRecommendation_EmptyURN(t *testing.T) {
+	assert.Equal(t, "web-server", urnName("urn:pulumi:dev::myapp::aws:ec2/instance:Instance::web-server"))
+	assert.Equal(t, "", urnName(""))
+
+	rec := convertRecommendation(&adapter.Recommendation{Type: "RIGHTSIZING"}, nil)
+	assert.Equal(t, "rightsizing", rec.ID)
+
+	output := `{"currency": "USD", "resources": [
+		{"urn": "", "provider": "aws", "monthly_cost": 10,
+		 "data_points": [{"timestamp": "2024-01-01T00:00:00Z", "cost": 5, "usage_amount": 0, "usage_unit": "Hrs"}]}
+	]}`
+	result, err := NewAnalysisService(adapter.NewPulumiCostAdapter(writeCoreScript(t, output)), nil).FindIdleResources(context.Background(), &analysis.FindIdleResourcesPayload{
+		StackName: "my-stack",
+		TimeRange: &analysis.TimeRange{Start: "2024-01-01T00:00:00Z", End: "2024-01-02T00:00:00Z"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.Recommendations)
+}
+
+// TestFindIdleResources_Partial verifies providers whose actual costs fail are reported
+func TestFindIdleResources_Partial(t *testing.T) {
+	core, router := failingPluginSource(t)
+	service := NewAnalysisServiceWithRouter(core, router, 2, nil)
+
+	result, err := service.FindIdleResources(context.Background(), &analysis.FindIdleResourcesPayload{
+		StackName: "my-stack",
+		TimeRange: &analysis.TimeRange{Start: "2024-01-01T00:00:00Z", End: "2024-01-04T00:00:00Z"},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Partial)
+	assert.Equal(t, []string{"kubernetes"}, result.UnpricedProviders)
+	assert.Equal(t, []string{"cleanup-db"}, recommendationIDs(result.Recommendations), "aws is still checked")
+}
+
+// failingPluginSource returns a pulumicost-core stand-in and a router whose
+// kubernetes plugin fails every actual cost request. Other providers are
+// priced by the mock core.
+func failingPluginSource(t *testing.T) (adapter.PulumiCostAdapter, *adapter.PluginRouter) {
+	t.Helper()
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "kubecost"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "kubecost", "plugin.json"), []byte(`{
+		"name": "kubecost",
+		"version": "1.0.0",
+		"providers": "kubernetes",
+		"capabilities": {"supports_actual_cost": true}
+	}`), 0644))
+
+	mock, err := filepath.Abs("../adapter/testdata/mock_pulumicost.sh")
+	require.NoError(t, err)
+	corePath := filepath.Join(t.TempDir(), "pulumicost")
+	require.NoError(t, os.WriteFile(corePath, []byte(`#!/bin/bash
+if [[ " $* " == *" --adapter "* ]]; then
+  echo "plugin unreachable" >&2
+  exit 1
+fi
+exec `+mock+` "$@"
+`), 0755))
+
+	return adapter.NewPulumiCostAdapter(corePath), adapter.NewPluginRouter(adapter.NewPluginAdapter(pluginDir, nil), nil, nil)
+}
+
+// TestFindIdleResources_Validation verifies the stack name and time range are required
+func TestFindIdleResources_Validation(t *testing.T) {
+	mockAdapter := adapter.NewPulumiCostAdapter("../adapter/testdata/mock_pulumicost.sh")
+	service := NewAnalysisService(mockAdapter, nil)
+
+	_, err := service.FindIdleResources(context.Background(), &analysis.FindIdleResourcesPayload{StackName: "my-stack"})
+	assert.ErrorContains(t, err, "time range is required")
+
+	_, err = service.FindIdleResources(context.Background(), &analysis.FindIdleResourcesPayload{
+		StackName: "my-stack",
+		TimeRange: &analysis.TimeRange{Start: "yesterday", End: "2024-01-04T00:00:00Z"},
+	})
+	assert.ErrorIs(t, err, adapter.ErrInvalidInput)
+
+	_, err = service.FindIdleResources(context.Background(), &analysis.FindIdleResourcesPayload{
+		StackName:      "my-stack",
+		TimeRange:      &analysis.TimeRange{Start: "2024-01-01T00:00:00Z", End: "2024-01-04T00:00:00Z"},
+		UsageThreshold: -1,
+	})
+	assert.ErrorIs(t, err, adapter.ErrInvalidInput)
+}
+
+// TestFindIdleResources_ZeroThreshold verifies a zero threshold flags only
+// resources with no usage at all, rather than falling back to a default
+func TestFindIdleResources_ZeroThreshold(t *testing.T) {
+	output := `{"currency": "USD", "resources": [
+		{"urn": "urn:pulumi:dev::myapp::aws:ec2/instance:Instance::quiet", "provider": "aws", "monthly_cost": 10,
+		 "data_points": [{"timestamp": "2024-01-01T00:00:00Z", "cost": 5, "usage_amount": 0.005, "usage_unit": "Hrs"}]},
+		{"urn": "urn:pulumi:dev::myapp::aws:ec2/instance:Instance::off", "provider": "aws", "monthly_cost": 10,
+		 "data_points": [{"timestamp": "2024-01-01T00:00:00Z", "cost": 5, "usage_amount": 0, "usage_unit": "Hrs"}]}
+	]}`
+	service := NewAnalysisService(adapter.NewPulumiCostAdapter(writeCoreScript(t, output)), nil)
+
+	result, err := service.FindIdleResources(context.Background(), &analysis.FindIdleResourcesPayload{
+		StackName: "my-stack",
+		TimeRange: &analysis.TimeRange{Start: "2024-01-01T00:00:00Z", End: "2024-01-02T00:00:00Z"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cleanup-off"}, recommendationIDs(result.Recommendations), "0.005 hours is above a zero threshold")
+}
+
+// TestAnalyzeCommitmentCoverage verifies committed and on-demand spend is
+// grouped by service with its reservation recommendations
+func TestAnalyzeCommitmentCoverage(t *testing.T) {
+	mockAdapter := adapter.NewPulumiCostAdapter("../adapter/testdata/mock_pulumicost.sh")
+	service := NewAnalysisService(mockAdapter, nil)
+
+	result, err := service.AnalyzeCommitmentCoverage(context.Background(), &analysis.AnalyzeCommitmentCoveragePayload{
+		StackName: "my-stack",
+		TimeRange: &analysis.TimeRange{Start: "2024-01-01T00:00:00Z", End: "2024-01-04T00:00:00Z"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "USD", result.Currency)
+	assert.Equal(t, 4.2, result.TotalSpend)
+	assert.Equal(t, 1.05, result.CommittedSpend)
+	assert.Equal(t, 3.15, result.OnDemandSpend)
+	assert.Equal(t, 3.15, result.SteadyStateSpend)
+	assert.Equal(t, 25.0, result.CoveragePercentage)
+	assert.Empty(t, result.UnknownBillingUrns)
+	assert.False(t, result.Partial)
+
+	require.Len(t, result.Groups, 2)
+	rds := result.Groups[0]
+	assert.Equal(t, "aws", rds.Provider)
+	assert.Equal(t, "rds", rds.Service)
+	assert.Equal(t, "DATABASE", rds.Category)
+	assert.Equal(t, 3.15, rds.SteadyStateSpend, "db ran on demand every day")
+	assert.Zero(t, rds.CoveragePercentage)
+	assert.Equal(t, []string{"reserved-instances-db"}, recommendationIDs(rds.Recommendations))
+	require.NotNil(t, rds.Recommendations[0].BreakEven)
+	assert.Equal(t, 7.5, rds.Recommendations[0].BreakEven.BreakEvenMonths)
+
+	ec2 := result.Groups[1]
+	assert.Equal(t, "ec2", ec2.Service)
+	assert.Equal(t, "COMPUTE", ec2.Category)
+	assert.Equal(t, 100.0, ec2.CoveragePercentage, "web-server is reserved")
+	assert.Zero(t, ec2.SteadyStateSpend)
+	assert.Empty(t, ec2.Recommendations, "rightsizing is not a commitment")
+}
+
+// TestAnalyzeCommitmentCoverage_Partial verifies providers whose actual costs fail are reported
+func TestAnalyzeCommitmentCoverage_Partial(t *testing.T) {
+	core, router := failingPluginSource(t)
+	service := NewAnalysisServiceWithRouter(core, router, 2, nil)
+
+	result, err := service.AnalyzeCommitmentCoverage(context.Background(), &analysis.AnalyzeCommitmentCoveragePayload{
+		StackName: "my-stack",
+		TimeRange: &analysis.TimeRange{Start: "2024-01-01T00:00:00Z", End: "2024-01-04T00:00:00Z"},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Partial)
+	assert.Equal(t, []string{"kubernetes"}, result.UnpricedProviders)
+	assert.Equal(t, 4.2, result.TotalSpend, "aws is still covered")
+}
+
+// TestBuildCommitmentCoverage verifies spot capacity, unknown billing modes
+// and days without on-demand spend
+func TestBuildCommitmentCoverage(t *testing.T) {
+	points := func(costs ...float64) []adapter.ActualCostDataPoint {
+		var dataPoints []adapter.ActualCostDataPoint
+		for i, cost := range costs {
+			dataPoints = append(dataPoints, adapter.ActualCostDataPoint{
+				Timestamp: time.Date(2024, 1, 1+i, 0, 0, 0, 0, time.UTC).Format(time.RFC3339),
+				Cost:      cost,
+			})
+		}
+		return dataPoints
+	}
+	spot, onDemand := "Spot", "OnDemand"
+	result := &adapter.CostResult{Currency: "USD", Resources: []adapter.ResourceCost{
+		{Urn: "vm-a", Type: "gcp:compute/instance:Instance", BillingMode: &onDemand, DataPoints: points(2, 3, 4)},
+		{Urn: "vm-b", Type: "gcp:compute/instance:Instance", DataPoints: points(1, 0, 1)},
+		{Urn: "vm-spot", Type: "gcp:compute/instance:Instance", BillingMode: &spot, DataPoints: points(9, 9, 9)},
+		{Urn: "bucket", Type: "gcp:storage/bucket:Bucket", DataPoints: points(5, 5, 5)},
+		{Urn: "db", Type: "gcp:sql/databaseInstance:DatabaseInstance", BillingMode: &onDemand, DataPoints: points(6, 6)},
+	}}
+
+	groups, unknown := buildCommitmentCoverage(result,
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, []string{"vm-b"}, unknown)
+	require.Len(t, groups, 2)
+
+	compute := groups[0]
+	assert.Equal(t, "compute", compute.Service)
+	assert.Equal(t, []string{"vm-a", "vm-b"}, compute.ResourceUrns, "spot capacity cannot be committed")
+	assert.Equal(t, 11.0, compute.TotalSpend)
+	assert.Equal(t, 9.0, compute.SteadyStateSpend, "lowest day is 3")
+
+	sql := groups[1]
+	assert.Equal(t, "DATABASE", sql.Category)
+	assert.Equal(t, 12.0, sql.OnDemandSpend)
+	assert.Zero(t, sql.SteadyStateSpend, "no spend on the third day")
+}
+
+// TestAnalyzeCommitmentCoverage_Validation verifies the stack name and time range are required
+func TestAnalyzeCommitmentCoverage_Validation(t *testing.T) {
+	mockAdapter := adapter.NewPulumiCostAdapter("../adapter/testdata/mock_pulumicost.sh")
+	service := NewAnalysisService(mockAdapter, nil)
+
+	_, err := service.AnalyzeCommitmentCoverage(context.Background(), &analysis.AnalyzeCommitmentCoveragePayload{StackName: "my-stack"})
+	assert.ErrorContains(t, err, "time range is required")
+
+	_, err = service.AnalyzeCommitmentCoverage(context.Background(), &analysis.AnalyzeCommitmentCoveragePayload{
+		StackName: "my-stack",
+		TimeRange: &analysis.TimeRange{Start: "2024-01-04T00:00:00Z", End: "2024-01-01T00:00:00Z"},
+	})
+	assert.ErrorIs(t, err, adapter.ErrInvalidInput)
 }
 
 // TestDetectAnomalies tests anomaly detection
 func TestDetectAnomalies(t *testing.T) {
-	service := NewAnalysisService(nil, nil)
+	service := NewAnalysisService(adapter.NewPulumiCostAdapter(writeCoreScript(t, anomalySeries(t))), nil)
 	ctx := context.Background()
 
 	payload := &analysis.DetectAnomaliesPayload{
 		StackName: "my-stack",
 		TimeRange: &analysis.TimeRange{
-			Start: "2024-01-01T00:00:00Z",
-			End:   "2024-01-31T23:59:59Z",
+			Start: "2024-01-11T00:00:00Z",
+			End:   "2024-01-12T23:59:59Z",
 		},
 		Sensitivity: "MEDIUM",
 	}
@@ -67,22 +524,133 @@ func TestDetectAnomalies(t *testing.T) {
 	result, err := service.DetectAnomalies(ctx, payload)
 
 	require.NoError(t, err)
-	require.NotNil(t, result)
-	assert.NotNil(t, result.Anomalies)
+	require.Len(t, result.Anomalies, 2)
+	assert.False(t, result.Partial)
+	assert.Empty(t, result.UnpricedProviders)
+
+	spike := result.Anomalies[0]
+	assert.Equal(t, "anom-20240111", spike.ID)
+	assert.Equal(t, "2024-01-11T00:00:00Z", spike.Timestamp)
+	assert.Equal(t, []string{webURN}, spike.ResourceUrns)
+	assert.Equal(t, "CRITICAL", spike.Severity)
+	assert.Equal(t, 30.0, spike.CurrentCost)
+	assert.Equal(t, 10.0, spike.BaselineCost)
+	assert.Equal(t, 200.0, spike.DeviationPercent)
+	require.Len(t, spike.PotentialCauses, 1)
+	assert.Contains(t, spike.PotentialCauses[0], "Daily cost of web-server rose to 30.00")
+	assert.Contains(t, spike.PotentialCauses[0], "usage went from 24 to 72 Hrs")
+
+	drop := result.Anomalies[1]
+	assert.Equal(t, []string{cacheURN}, drop.ResourceUrns)
+	assert.Equal(t, "HIGH", drop.Severity)
+	assert.Equal(t, -100.0, drop.DeviationPercent)
+	assert.Contains(t, drop.PotentialCauses[0], "may have been stopped or removed")
+}
+
+// TestDetectAnomalies_Partial verifies providers whose actual costs fail are reported
+func TestDetectAnomalies_Partial(t *testing.T) {
+	core, router := failingPluginSource(t)
+	service := NewAnalysisServiceWithRouter(core, router, 2, nil)
+
+	result, err := service.DetectAnomalies(context.Background(), &analysis.DetectAnomaliesPayload{
+		StackName:   "my-stack",
+		TimeRange:   &analysis.TimeRange{Start: "2024-01-02T00:00:00Z", End: "2024-01-04T00:00:00Z"},
+		Sensitivity: "MEDIUM",
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Partial)
+	assert.Equal(t, []string{"kubernetes"}, result.UnpricedProviders)
 }
 
-// TestForecast tests cost forecasting
+// TestDetectAnomalies_Sensitivity verifies sensitivity sets the deviation threshold
+func TestDetectAnomalies_Sensitivity(t *testing.T) {
+	service := NewAnalysisService(adapter.NewPulumiCostAdapter(writeCoreScript(t, anomalySeries(t))), nil)
+	timeRange := &analysis.TimeRange{Start: "2024-01-11T00:00:00Z", End: "2024-01-12T23:59:59Z"}
+
+	// db moves 1.6 standard deviations on the day of the spike
+	result, err := service.DetectAnomalies(context.Background(), &analysis.DetectAnomaliesPayload{
+		StackName: "my-stack", TimeRange: timeRange, Sensitivity: "HIGH",
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Anomalies, 2)
+	assert.Equal(t, []string{webURN, dbURN}, result.Anomalies[0].ResourceUrns, "ordered by contribution")
+	assert.Equal(t, 35.25, result.Anomalies[0].CurrentCost)
+
+	result, err = service.DetectAnomalies(context.Background(), &analysis.DetectAnomaliesPayload{
+		StackName: "my-stack", TimeRange: timeRange, Sensitivity: "LOW",
+	})
+	require.NoError(t, err)
+	assert.Len(t, result.Anomalies, 2, "both anomalies exceed 3 standard deviations")
+
+	_, err = service.DetectAnomalies(context.Background(), &analysis.DetectAnomaliesPayload{
+		StackName: "my-stack", TimeRange: &analysis.TimeRange{Start: timeRange.End, End: timeRange.Start},
+	})
+	assert.ErrorIs(t, err, adapter.ErrInvalidInput)
+}
+
+const (
+	webURN   = "urn:pulumi:dev::myapp::aws:ec2/instance:Instance::web-server"
+	dbURN    = "urn:pulumi:dev::myapp::aws:rds/instance:Instance::db"
+	cacheURN = "urn:pulumi:dev::myapp::aws:elasticache/cluster:Cluster::cache"
+)
+
+// anomalySeries is twelve days of actual costs: web-server spikes on the
+// 11th, db moves slightly the same day and cache drops to zero on the 12th
+func anomalySeries(t *testing.T) string {
+	t.Helper()
+	series := func(urn, name, unit string, costs []float64, usage []float64) adapter.ResourceCost {
+		res := adapter.ResourceCost{Urn: urn, Name: name, Type: "aws:x", MonthlyCost: costs[len(costs)-1] * 30}
+		for i, cost := range costs {
+			point := adapter.ActualCostDataPoint{
+				Timestamp: time.Date(2024, 1, i+1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339),
+				Cost:      cost,
+				Currency:  "USD",
+			}
+			if usage != nil {
+				point.UsageAmount = &usage[i]
+				point.UsageUnit = unit
+			}
+			res.DataPoints = append(res.DataPoints, point)
+		}
+		return res
+	}
+
+	data, err := json.Marshal(adapter.CostResult{Currency: "USD", Resources: []adapter.ResourceCost{
+		series(webURN, "web-server", "Hrs",
+			[]float64{10, 10.2, 9.8, 10, 10.1, 9.9, 10, 10.2, 9.8, 10, 30, 10},
+			[]float64{24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 72, 24}),
+		series(dbURN, "db", "",
+			[]float64{5, 5.2, 4.8, 5, 5.2, 4.8, 5, 5.2, 4.8, 5, 5.25, 5}, nil),
+		series(cacheURN, "cache", "",
+			[]float64{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 0}, nil),
+	}})
+	require.NoError(t, err)
+	return string(data)
+}
+
+// writeCoreScript writes a pulumicost-core stand-in that prints output for any command
+func writeCoreScript(t *testing.T, output string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pulumicost")
+	script := "#!/bin/bash\ncat <<'EOF'\n" + output + "\nEOF\n"
+	require.NoError(t, os.WriteFile(path, []byte(script), 0755))
+	return path
+}
+
+// TestForecast tests cost forecasting through pulumicost-core
 func TestForecast(t *testing.T) {
-	service := NewAnalysisService(nil, nil)
+	mockAdapter := adapter.NewPulumiCostAdapter("../adapter/testdata/mock_pulumicost.sh")
+	service := NewAnalysisService(mockAdapter, nil)
 	ctx := context.Background()
 
 	payload := &analysis.ForecastPayload{
 		StackName: "my-stack",
 		ForecastPeriod: &analysis.TimeRange{
-			Start: "2024-02-01T00:00:00Z",
-			End:   "2024-02-29T23:59:59Z",
+			Start: "2024-04-01T00:00:00Z",
+			End:   "2024-04-04T00:00:00Z",
 		},
-		ConfidenceLevel: 0.95,
+		ConfidenceLevel: 0.8,
 	}
 
 	result, err := service.Forecast(ctx, payload)
@@ -90,13 +658,159 @@ func TestForecast(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, result)
 	assert.Equal(t, "my-stack", result.StackName)
-	assert.NotEmpty(t, result.DataPoints)
-	assert.Greater(t, result.DataPoints[0].PredictedCost, 0.0)
+	assert.Equal(t, "daily", result.Granularity)
+	assert.Equal(t, 0.8, result.ConfidenceLevel)
+	assert.Contains(t, result.Methodology, "daily points at 0.8 confidence")
+	assert.Equal(t, adapter.CoreSource, result.Source)
+	require.NotNil(t, result.HistoryWindow)
+	assert.Equal(t, "2024-01-01T00:00:00Z", result.HistoryWindow.Start)
+	require.Len(t, result.DataPoints, 3)
+	assert.Equal(t, 1.4, result.DataPoints[0].PredictedCost)
+	assert.Equal(t, 0.0, result.DataPoints[2].LowerBound, "negative bound clamped")
+}
+
+// TestForecast_Granularity verifies point spacing follows the period length
+func TestForecast_Granularity(t *testing.T) {
+	start := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "daily", forecastGranularity(start, start.AddDate(0, 0, 31)))
+	assert.Equal(t, "weekly", forecastGranularity(start, start.AddDate(0, 3, 0)))
+	assert.Equal(t, "monthly", forecastGranularity(start, start.AddDate(1, 0, 0)))
+}
+
+// TestForecast_RoutesToPlugin verifies a forecasting plugin is asked before pulumicost-core
+func TestForecast_RoutesToPlugin(t *testing.T) {
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "aws-forecaster"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "aws-forecaster", "plugin.json"), []byte(`{
+		"name": "aws-forecaster",
+		"version": "1.0.0",
+		"providers": "aws",
+		"capabilities": {"supports_forecast": true}
+	}`), 0644))
+
+	mockAdapter := adapter.NewPulumiCostAdapter("../adapter/testdata/mock_pulumicost.sh")
+	router := adapter.NewPluginRouter(adapter.NewPluginAdapter(pluginDir, nil), nil, nil)
+	service := NewAnalysisServiceWithRouter(mockAdapter, router, 1, nil)
+
+	result, err := service.Forecast(context.Background(), &analysis.ForecastPayload{
+		StackName:       "my-stack",
+		ForecastPeriod:  &analysis.TimeRange{Start: "2024-04-01T00:00:00Z", End: "2025-04-01T00:00:00Z"},
+		ConfidenceLevel: 0.95,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "aws-forecaster", result.Source)
+	assert.Equal(t, "monthly", result.Granularity)
+}
+
+// TestForecast_PartialCoverage verifies a forecasting plugin is only used when it
+// covers every provider of the stack
+func TestForecast_PartialCoverage(t *testing.T) {
+	writePlugin := func(dir, name, providers, capabilities string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, name), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name, "plugin.json"), []byte(fmt.Sprintf(`{
+			"name": %q, "version": "1.0.0", "providers": %q, "capabilities": {%s}
+		}`, name, providers, capabilities)), 0644))
+	}
+
+	mock, err := filepath.Abs("../adapter/testdata/mock_pulumicost.sh")
+	require.NoError(t, err)
+	corePath := filepath.Join(t.TempDir(), "pulumicost")
+	require.NoError(t, os.WriteFile(corePath, []byte(`#!/bin/bash
+if [[ " $* " == *" --adapter kubecost "* ]]; then
+  cat <<'EOF'
+{"currency": "USD", "resources": [{"urn": "urn:pulumi:dev::myapp::kubernetes:apps/v1:Deployment::api", "provider": "kubernetes", "monthly_cost": 20}]}
+EOF
+  exit 0
+fi
+if [[ " $* " == *" --adapter aws-forecaster "* ]]; then
+  echo "aws-forecaster only sees aws" >&2
+  exit 1
+fi
+exec `+mock+` "$@"
+`), 0755))
+
+	payload := &analysis.ForecastPayload{
+		StackName:       "my-stack",
+		ForecastPeriod:  &analysis.TimeRange{Start: "2024-04-01T00:00:00Z", End: "2024-04-04T00:00:00Z"},
+		ConfidenceLevel: 0.9,
+	}
+
+	pluginDir := t.TempDir()
+	writePlugin(pluginDir, "kubecost", "kubernetes", `"supports_actual_cost": true`)
+	writePlugin(pluginDir, "aws-forecaster", "aws", `"supports_forecast": true`)
+	router := adapter.NewPluginRouter(adapter.NewPluginAdapter(pluginDir, nil), nil, nil)
+
+	result, err := NewAnalysisServiceWithRouter(adapter.NewPulumiCostAdapter(corePath), router, 1, nil).Forecast(context.Background(), payload)
+	require.NoError(t, err)
+	assert.Equal(t, adapter.CoreSource, result.Source, "aws-forecaster does not cover kubernetes")
+
+	writePlugin(pluginDir, "fleet-forecaster", "aws,kubernetes", `"supports_forecast": true`)
+	router = adapter.NewPluginRouter(adapter.NewPluginAdapter(pluginDir, nil), nil, nil)
+
+	result, err = NewAnalysisServiceWithRouter(adapter.NewPulumiCostAdapter(corePath), router, 1, nil).Forecast(context.Background(), payload)
+	require.NoError(t, err)
+	assert.Equal(t, "fleet-forecaster", result.Source)
+}
+
+// TestForecast_Unsupported verifies no invented forecast is returned without a capable source
+func TestForecast_Unsupported(t *testing.T) {
+	payload := &analysis.ForecastPayload{
+		StackName:       "legacy",
+		ForecastPeriod:  &analysis.TimeRange{Start: "2024-04-01T00:00:00Z", End: "2024-05-01T00:00:00Z"},
+		ConfidenceLevel: 0.95,
+	}
+
+	var unsupported *analysis.UnsupportedError
+	_, err := NewAnalysisService(adapter.NewPulumiCostAdapter("../adapter/testdata/mock_pulumicost.sh"), nil).Forecast(context.Background(), payload)
+	assert.ErrorAs(t, err, &unsupported)
+
+	payload.StackName = "my-stack"
+	_, err = NewAnalysisService(nil, nil).Forecast(context.Background(), payload)
+	assert.ErrorAs(t, err, &unsupported)
+
+	_, err = NewAnalysisService(adapter.NewPulumiCostAdapter(writeCoreScript(t, "not json")), nil).Forecast(context.Background(), payload)
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &unsupported), "a failing source is not an unsupported one")
+}
+
+// TestForecast_Validation verifies the forecast period and confidence level are checked
+func TestForecast_Validation(t *testing.T) {
+	service := NewAnalysisService(adapter.NewPulumiCostAdapter("../adapter/testdata/mock_pulumicost.sh"), nil)
+	ctx := context.Background()
+
+	_, err := service.Forecast(ctx, &analysis.ForecastPayload{
+		StackName:       "my-stack",
+		ForecastPeriod:  &analysis.TimeRange{Start: "2024-05-01T00:00:00Z", End: "2024-04-01T00:00:00Z"},
+		ConfidenceLevel: 0.95,
+	})
+	assert.ErrorIs(t, err, adapter.ErrInvalidInput)
+
+	_, err = service.Forecast(ctx, &analysis.ForecastPayload{
+		StackName:       "my-stack",
+		ForecastPeriod:  &analysis.TimeRange{Start: "2024-04-01T00:00:00Z", End: "2024-05-01T00:00:00Z"},
+		ConfidenceLevel: 1,
+	})
+	assert.ErrorIs(t, err, adapter.ErrInvalidInput)
 }
 
-// TestTrackBudget tests budget tracking
+// budgetSpend is period-to-date actual cost output with a daily breakdown
+const budgetSpend = `{
+  "total_monthly": 300.00,
+  "currency": "USD",
+  "resources": [],
+  "breakdown": {
+    "daily": [
+      {"date": "2024-03-01", "amount": 80.00},
+      {"date": "2024-03-02", "amount": 100.00},
+      {"date": "2024-03-03", "amount": 120.00}
+    ]
+  }
+}`
+
+// TestTrackBudget tests budget tracking against actual spending
 func TestTrackBudget(t *testing.T) {
-	service := NewAnalysisService(nil, nil)
+	service := NewAnalysisService(adapter.NewPulumiCostAdapter(writeCoreScript(t, budgetSpend)), nil)
 	ctx := context.Background()
 
 	payload := &analysis.TrackBudgetPayload{
@@ -110,24 +824,454 @@ func TestTrackBudget(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, result)
 	assert.Equal(t, 1000.0, result.BudgetAmount)
-	assert.Greater(t, result.CurrentSpending, 0.0)
-	assert.NotEmpty(t, result.Status)
+	assert.Equal(t, 300.0, result.CurrentSpending)
+	assert.Equal(t, 700.0, result.Remaining)
+	assert.Equal(t, 100.0, *result.BurnRate)
+	assert.Equal(t, "USD", *result.Currency)
+	assert.Equal(t, "MONTHLY", result.Period)
+	assert.Equal(t, "OK", result.Status)
+	assert.False(t, result.Partial)
+
+	windowStart, _, err := budgetWindow("MONTHLY", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, windowStart.Format(time.RFC3339), result.PeriodWindow.Start)
+}
+
+// TestTrackBudget_Partial verifies providers whose actual costs fail are reported
+func TestTrackBudget_Partial(t *testing.T) {
+	core, router := failingPluginSource(t)
+	service := NewAnalysisServiceWithRouter(core, router, 2, nil)
+
+	result, err := service.TrackBudget(context.Background(), &analysis.TrackBudgetPayload{
+		StackName:    "my-stack",
+		BudgetAmount: 1000.0,
+		Period:       "MONTHLY",
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Partial)
+	assert.Equal(t, []string{"kubernetes"}, result.UnpricedProviders)
 }
 
 // TestTrackBudget_WithAlerts tests budget with alert thresholds
 func TestTrackBudget_WithAlerts(t *testing.T) {
-	service := NewAnalysisService(nil, nil)
+	service := NewAnalysisService(adapter.NewPulumiCostAdapter(writeCoreScript(t, budgetSpend)), nil)
 	ctx := context.Background()
 
 	payload := &analysis.TrackBudgetPayload{
 		StackName:       "my-stack",
 		BudgetAmount:    1000.0,
 		Period:          "MONTHLY",
-		AlertThresholds: []float64{50.0, 80.0, 100.0},
+		AlertThresholds: []float64{25.0, 50.0, 80.0},
 	}
 
 	result, err := service.TrackBudget(ctx, payload)
 
 	require.NoError(t, err)
-	assert.NotNil(t, result.Alerts)
+	require.Len(t, result.Alerts, 1)
+	assert.Equal(t, 25.0, result.Alerts[0].Threshold)
+	assert.Equal(t, 30.0, result.Alerts[0].PercentageUsed)
+	assert.Equal(t, "INFO", result.Alerts[0].Severity)
+}
+
+// TestTrackBudget_Validation verifies the period and data source are checked
+func TestTrackBudget_Validation(t *testing.T) {
+	ctx := context.Background()
+	payload := &analysis.TrackBudgetPayload{StackName: "my-stack", BudgetAmount: 1000.0, Period: "HOURLY"}
+
+	_, err := NewAnalysisService(adapter.NewPulumiCostAdapter(writeCoreScript(t, budgetSpend)), nil).TrackBudget(ctx, payload)
+	assert.ErrorIs(t, err, adapter.ErrInvalidInput)
+
+	payload.Period = "MONTHLY"
+	_, err = NewAnalysisService(nil, nil).TrackBudget(ctx, payload)
+	assert.Error(t, err)
+}
+
+// TestBudgetWindow verifies each period's bounds in UTC
+func TestBudgetWindow(t *testing.T) {
+	now := time.Date(2024, 3, 14, 15, 30, 0, 0, time.UTC) // a Thursday
+
+	tests := []struct {
+		period     string
+		start, end string
+	}{
+		{"DAILY", "2024-03-14T00:00:00Z", "2024-03-15T00:00:00Z"},
+		{"WEEKLY", "2024-03-11T00:00:00Z", "2024-03-18T00:00:00Z"},
+		{"MONTHLY", "2024-03-01T00:00:00Z", "2024-04-01T00:00:00Z"},
+		{"YEARLY", "2024-01-01T00:00:00Z", "2025-01-01T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		start, end, err := budgetWindow(tt.period, now)
+		require.NoError(t, err, tt.period)
+		assert.Equal(t, tt.start, start.Format(time.RFC3339), tt.period)
+		assert.Equal(t, tt.end, end.Format(time.RFC3339), tt.period)
+	}
+}
+
+// TestEvaluateBudget verifies burn rate, projection and exhaustion come from the daily breakdown
+func TestEvaluateBudget(t *testing.T) {
+	var result adapter.CostResult
+	require.NoError(t, json.Unmarshal([]byte(budgetSpend), &result))
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	now := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	budget := evaluateBudget(1000, "MONTHLY", nil, &result, start, end, now)
+	assert.Equal(t, 100.0, *budget.BurnRate)
+	assert.Equal(t, 3100.0, *budget.ProjectedSpending)
+	require.NotNil(t, budget.ProjectedEndDate)
+	assert.Equal(t, "2024-03-11T00:00:00Z", *budget.ProjectedEndDate)
+	assert.Empty(t, budget.Alerts)
+
+	budget = evaluateBudget(250, "MONTHLY", []float64{50, 100}, &result, start, end, now)
+	assert.Equal(t, "EXCEEDED", budget.Status)
+	assert.Equal(t, "2024-03-03T00:00:00Z", *budget.ProjectedEndDate, "day the cumulative spend crossed the budget")
+	require.Len(t, budget.Alerts, 2)
+	assert.Equal(t, "CRITICAL", budget.Alerts[1].Severity)
+
+	budget = evaluateBudget(100000, "MONTHLY", nil, &result, start, end, now)
+	assert.Nil(t, budget.ProjectedEndDate, "budget lasts beyond the period")
+}
+
+// teamSpend is period-to-date actual cost output of a stack shared by two teams
+const teamSpend = `{
+  "total_monthly": 60.00,
+  "currency": "USD",
+  "resources": [
+    {
+      "urn": "urn:pulumi:dev::shop::aws:ec2/instance:Instance::payments",
+      "name": "payments",
+      "type": "aws:ec2/instance:Instance",
+      "monthly_cost": 40.00,
+      "tags": {"team": "payments"},
+      "data_points": [
+        {"timestamp": "2024-03-01T00:00:00Z", "cost": 20.00},
+        {"timestamp": "2024-03-02T00:00:00Z", "cost": 20.00}
+      ]
+    },
+    {
+      "urn": "urn:pulumi:dev::shop::gcp:compute/instance:Instance::search",
+      "name": "search",
+      "type": "gcp:compute/instance:Instance",
+      "monthly_cost": 20.00,
+      "tags": {"team": "search"},
+      "data_points": [
+        {"timestamp": "2024-03-01T00:00:00Z", "cost": 10.00},
+        {"timestamp": "2024-03-02T00:00:00Z", "cost": 10.00}
+      ]
+    }
+  ]
+}`
+
+// writeBudgetsFile writes a budgets file and returns its path
+func writeBudgetsFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "budgets.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// TestLoadBudgets verifies budget definitions are validated and normalized
+func TestLoadBudgets(t *testing.T) {
+	budgets, err := LoadBudgets(writeBudgetsFile(t, `
+budgets:
+  - name: payments
+    amount: 100
+    period: monthly
+    alert_thresholds: [50, 100]
+    scope:
+      stacks: [shop-dev, shop-prod]
+      tags: {team: payments}
+      providers: [AWS]
+`))
+	require.NoError(t, err)
+	require.Len(t, budgets, 1)
+	assert.Equal(t, "MONTHLY", budgets[0].Period)
+	assert.Equal(t, "USD", budgets[0].Currency)
+	assert.Equal(t, []string{"aws"}, budgets[0].Scope.Providers)
+
+	invalid := map[string]string{
+		"no stacks":     "budgets: [{name: a, amount: 1, period: MONTHLY}]",
+		"no amount":     "budgets: [{name: a, period: MONTHLY, scope: {stacks: [s]}}]",
+		"bad period":    "budgets: [{name: a, amount: 1, period: HOURLY, scope: {stacks: [s]}}]",
+		"bad threshold": "budgets: [{name: a, amount: 1, period: DAILY, alert_thresholds: [120], scope: {stacks: [s]}}]",
+		"duplicate": `budgets:
+  - {name: a, amount: 1, period: DAILY, scope: {stacks: [s]}}
+  - {name: a, amount: 2, period: DAILY, scope: {stacks: [s]}}`,
+	}
+	for name, content := range invalid {
+		_, err := LoadBudgets(writeBudgetsFile(t, content))
+		assert.Error(t, err, name)
+	}
+}
+
+// teamBudgets are budgets scoped by tag and by provider over the same stack
+func teamBudgets(t *testing.T) []BudgetDefinition {
+	t.Helper()
+	budgets, err := LoadBudgets(writeBudgetsFile(t, `
+budgets:
+  - name: payments
+    amount: 50
+    period: MONTHLY
+    alert_thresholds: [75]
+    scope:
+      stacks: [shop-dev]
+      tags: {team: payments}
+  - name: gcp
+    amount: 1000
+    period: MONTHLY
+    scope:
+      stacks: [shop-dev]
+      providers: [gcp]
+  - name: shop
+    amount: 500
+    currency: EUR
+    period: MONTHLY
+    scope:
+      stacks: [shop-dev]
+  - name: other
+    amount: 10
+    period: DAILY
+    scope:
+      stacks: [other-dev]
+`))
+	require.NoError(t, err)
+	return budgets
+}
+
+// TestListBudgets verifies budgets can be listed for a stack
+func TestListBudgets(t *testing.T) {
+	service := NewAnalysisServiceWithBudgets(nil, nil, 1, teamBudgets(t), nil)
+	ctx := context.Background()
+
+	result, err := service.ListBudgets(ctx, &analysis.ListBudgetsPayload{})
+	require.NoError(t, err)
+	assert.Len(t, result.Budgets, 4)
+
+	stack := "other-dev"
+	result, err = service.ListBudgets(ctx, &analysis.ListBudgetsPayload{StackName: &stack})
+	require.NoError(t, err)
+	require.Len(t, result.Budgets, 1)
+	assert.Equal(t, "other", result.Budgets[0].Name)
+	assert.Equal(t, "DAILY", result.Budgets[0].Period)
+}
+
+// TestCheckAllBudgets verifies each budget counts only the spending its scope covers
+func TestCheckAllBudgets(t *testing.T) {
+	mockAdapter := adapter.NewPulumiCostAdapter(writeCoreScript(t, teamSpend))
+	service := NewAnalysisServiceWithBudgets(mockAdapter, nil, 1, teamBudgets(t), nil)
+
+	stack := "shop-dev"
+	result, err := service.CheckAllBudgets(context.Background(), &analysis.CheckAllBudgetsPayload{StackName: &stack})
+
+	require.NoError(t, err)
+	require.Len(t, result.Checks, 3)
+
+	payments := result.Checks[0]
+	assert.Equal(t, "payments", payments.Definition.Name)
+	assert.Equal(t, 40.0, payments.Budget.CurrentSpending)
+	assert.Equal(t, 20.0, *payments.Budget.BurnRate)
+	assert.Equal(t, "WARNING", payments.Budget.Status)
+	require.Len(t, payments.Budget.Alerts, 1)
+
+	gcp := result.Checks[1]
+	assert.Equal(t, "gcp", gcp.Definition.Name)
+	assert.Equal(t, 20.0, gcp.Budget.CurrentSpending)
+	assert.Equal(t, "OK", gcp.Budget.Status)
+
+	shop := result.Checks[2]
+	assert.Equal(t, "shop", shop.Definition.Name)
+	assert.Nil(t, shop.Budget)
+	require.NotNil(t, shop.Error)
+	assert.Contains(t, *shop.Error, "EUR")
+
+	assert.Equal(t, map[string]int{"WARNING": 1, "OK": 1, "ERROR": 1}, result.StatusCounts)
+}
+
+// TestCheckAllBudgets_Partial verifies a budget is partial only when a
+// provider in its scope could not be priced
+func TestCheckAllBudgets_Partial(t *testing.T) {
+	budgets, err := LoadBudgets(writeBudgetsFile(t, `
+budgets:
+  - name: all
+    amount: 1000
+    period: MONTHLY
+    scope: {stacks: [my-stack]}
+  - name: aws
+    amount: 1000
+    period: MONTHLY
+    scope: {stacks: [my-stack], providers: [aws]}
+`))
+	require.NoError(t, err)
+	core, router := failingPluginSource(t)
+	service := NewAnalysisServiceWithBudgets(core, router, 2, budgets, nil)
+
+	result, err := service.CheckAllBudgets(context.Background(), &analysis.CheckAllBudgetsPayload{})
+
+	require.NoError(t, err)
+	partial := make(map[string][]string)
+	for _, check := range result.Checks {
+		require.NotNil(t, check.Budget, check.Definition.Name)
+		if check.Budget.Partial {
+			partial[check.Definition.Name] = check.Budget.UnpricedProviders
+		}
+	}
+	assert.Equal(t, map[string][]string{"all": {"kubernetes"}}, partial)
+}
+
+// TestComputeUnitCost verifies the scoped daily cost is divided by the metric per window
+func TestComputeUnitCost(t *testing.T) {
+	service := NewAnalysisService(adapter.NewPulumiCostAdapter(writeCoreScript(t, teamSpend)), nil)
+	metricName := "requests"
+
+	result, err := service.ComputeUnitCost(context.Background(), &analysis.ComputeUnitCostPayload{
+		Scope:      &analysis.BudgetScope{Stacks: []string{"shop-dev"}, Tags: map[string]string{"team": "payments"}},
+		MetricName: &metricName,
+		MetricPoints: []*analysis.MetricPoint{
+			{Timestamp: "2024-03-01T09:00:00Z", Value: 1500},
+			{Timestamp: "2024-03-01T17:00:00Z", Value: 500},
+			{Timestamp: "2024-03-02", Value: 4000},
+			{Timestamp: "2024-03-03", Value: 0},
+		},
+		Per: 1000,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "requests", result.MetricName)
+	assert.Equal(t, "daily", result.Granularity)
+	assert.Equal(t, "USD", result.Currency)
+	assert.Equal(t, &analysis.TimeRange{Start: "2024-03-01T00:00:00Z", End: "2024-03-04T00:00:00Z"}, result.TimeRange)
+
+	require.Len(t, result.Points, 3)
+	assert.Equal(t, "2024-03-01T00:00:00Z", result.Points[0].Window.Start)
+	assert.Equal(t, 20.0, result.Points[0].Cost, "only the payments team")
+	assert.Equal(t, 2000.0, result.Points[0].MetricValue)
+	require.NotNil(t, result.Points[0].UnitCost)
+	assert.Equal(t, 10.0, *result.Points[0].UnitCost)
+	assert.Equal(t, 5.0, *result.Points[1].UnitCost)
+	assert.Zero(t, result.Points[2].Cost)
+	assert.Nil(t, result.Points[2].UnitCost, "no metric to divide by")
+
+	assert.Equal(t, 40.0, result.TotalCost)
+	assert.Equal(t, 6000.0, result.TotalMetric)
+	require.NotNil(t, result.UnitCost)
+	assert.InDelta(t, 6.67, *result.UnitCost, 0.01)
+}
+
+// TestComputeUnitCost_CSV verifies a metric file is read and summed per week
+func TestComputeUnitCost_CSV(t *testing.T) {
+	metricsDir := t.TempDir()
+	service := NewAnalysisServiceWithOptions(adapter.NewPulumiCostAdapter(writeCoreScript(t, teamSpend)), nil, AnalysisOptions{MetricsDir: metricsDir}, nil)
+	path := filepath.Join(metricsDir, "tenants.csv")
+	require.NoError(t, os.WriteFile(path, []byte("date,tenants\n2024-03-01,100\n2024-03-02,300\n"), 0644))
+
+	result, err := service.ComputeUnitCost(context.Background(), &analysis.ComputeUnitCostPayload{
+		Scope:       &analysis.BudgetScope{Stacks: []string{"shop-dev"}},
+		MetricFile:  &path,
+		Granularity: "weekly",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "units", result.MetricName)
+	assert.Equal(t, 1.0, result.Per)
+	require.Len(t, result.Points, 1)
+	assert.Equal(t, &analysis.TimeRange{Start: "2024-02-26T00:00:00Z", End: "2024-03-04T00:00:00Z"}, result.Points[0].Window, "weeks start on Monday")
+	assert.Equal(t, 60.0, result.Points[0].Cost)
+	assert.Equal(t, 400.0, result.Points[0].MetricValue)
+	assert.InDelta(t, 0.15, *result.UnitCost, 1e-9)
+}
+
+// TestComputeUnitCost_Partial verifies providers in the scope that could not be priced are reported
+func TestComputeUnitCost_Partial(t *testing.T) {
+	core, router := failingPluginSource(t)
+	service := NewAnalysisServiceWithRouter(core, router, 2, nil)
+	points := []*analysis.MetricPoint{{Timestamp: "2024-01-01", Value: 10}}
+
+	result, err := service.ComputeUnitCost(context.Background(), &analysis.ComputeUnitCostPayload{
+		Scope:        &analysis.BudgetScope{Stacks: []string{"my-stack"}},
+		MetricPoints: points,
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Partial)
+	assert.Equal(t, []string{"kubernetes"}, result.UnpricedProviders)
+
+	result, err = service.ComputeUnitCost(context.Background(), &analysis.ComputeUnitCostPayload{
+		Scope:        &analysis.BudgetScope{Stacks: []string{"my-stack"}, Providers: []string{"aws"}},
+		MetricPoints: points,
+	})
+	require.NoError(t, err)
+	assert.False(t, result.Partial, "kubernetes is outside the scope")
+}
+
+// TestComputeUnitCost_Validation verifies the scope and metric series are checked
+func TestComputeUnitCost_Validation(t *testing.T) {
+	metricsDir := t.TempDir()
+	service := NewAnalysisServiceWithOptions(adapter.NewPulumiCostAdapter(writeCoreScript(t, teamSpend)), nil, AnalysisOptions{MetricsDir: metricsDir}, nil)
+	scope := &analysis.BudgetScope{Stacks: []string{"shop-dev"}}
+	points := []*analysis.MetricPoint{{Timestamp: "2024-03-01", Value: 1}}
+	missing := filepath.Join(metricsDir, "missing.csv")
+	badCSV := filepath.Join(metricsDir, "bad.csv")
+	require.NoError(t, os.WriteFile(badCSV, []byte("2024-03-01,1\n2024-03-02,many\n"), 0644))
+
+	tests := []struct {
+		name    string
+		payload *analysis.ComputeUnitCostPayload
+		want    string
+	}{
+		{"no scope", &analysis.ComputeUnitCostPayload{MetricPoints: points}, "at least one stack"},
+		{"tags only", &analysis.ComputeUnitCostPayload{Scope: &analysis.BudgetScope{Tags: map[string]string{"team": "payments"}}, MetricPoints: points}, "tags and providers only narrow the named stacks"},
+		{"no metric", &analysis.ComputeUnitCostPayload{Scope: scope}, "metric_points or metric_file is required"},
+		{"both metrics", &analysis.ComputeUnitCostPayload{Scope: scope, MetricPoints: points, MetricFile: &missing}, "not both"},
+		{"negative value", &analysis.ComputeUnitCostPayload{Scope: scope, MetricPoints: []*analysis.MetricPoint{{Timestamp: "2024-03-01", Value: -1}}}, "negative"},
+		{"bad timestamp", &analysis.ComputeUnitCostPayload{Scope: scope, MetricPoints: []*analysis.MetricPoint{{Timestamp: "March", Value: 1}}}, "invalid timestamp"},
+		{"missing file", &analysis.ComputeUnitCostPayload{Scope: scope, MetricFile: &missing}, "open metric file"},
+		{"bad file value", &analysis.ComputeUnitCostPayload{Scope: scope, MetricFile: &badCSV}, "line 2"},
+		{"granularity", &analysis.ComputeUnitCostPayload{Scope: scope, MetricPoints: points, Granularity: "hourly"}, "granularity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ComputeUnitCost(context.Background(), tt.payload)
+			assert.ErrorIs(t, err, adapter.ErrInvalidInput)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+// TestComputeUnitCost_MetricsDir verifies metric files are only read from the
+// metrics directory and their contents are not echoed in errors
+func TestComputeUnitCost_MetricsDir(t *testing.T) {
+	costs := adapter.NewPulumiCostAdapter(writeCoreScript(t, teamSpend))
+	scope := &analysis.BudgetScope{Stacks: []string{"shop-dev"}}
+	metricsDir := t.TempDir()
+	outside := t.TempDir()
+
+	secret := filepath.Join(outside, "secret.csv")
+	require.NoError(t, os.WriteFile(secret, []byte("2024-03-01,1\n2024-03-02,hunter2\n"), 0644))
+	require.NoError(t, os.Symlink(secret, filepath.Join(metricsDir, "link.csv")))
+	inside := filepath.Join(metricsDir, "secret.csv")
+	require.NoError(t, os.WriteFile(inside, []byte("2024-03-01,1\nhunter2,2\n"), 0644))
+
+	unitCost := func(service *AnalysisService, file string) error {
+		_, err := service.ComputeUnitCost(context.Background(), &analysis.ComputeUnitCostPayload{Scope: scope, MetricFile: &file})
+		return err
+	}
+
+	err := unitCost(NewAnalysisService(costs, nil), inside)
+	assert.ErrorIs(t, err, adapter.ErrInvalidInput)
+	assert.ErrorContains(t, err, "unit_cost.metrics_dir")
+
+	service := NewAnalysisServiceWithOptions(costs, nil, AnalysisOptions{MetricsDir: metricsDir}, nil)
+	for _, file := range []string{secret, "../" + filepath.Base(outside) + "/secret.csv", "link.csv"} {
+		err := unitCost(service, file)
+		assert.ErrorIs(t, err, adapter.ErrInvalidInput, file)
+		assert.NotContains(t, err.Error(), "hunter2", file)
+	}
+	assert.ErrorContains(t, unitCost(service, secret), "inside the metrics directory")
+
+	for _, file := range []string{inside, "secret.csv"} {
+		err := unitCost(service, file)
+		assert.ErrorContains(t, err, "line 2", file)
+		assert.NotContains(t, err.Error(), "hunter2", file)
+	}
 }
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/gen/analysis"
+	"github.com/rshade/pulumicost-mcp/internal/adapter"
+)
+
+// Anomaly detection parameters, see specs/001-mcp-server/data-model.md
+const (
+	// anomalyBaselineWindow is how far back the baseline of a day reaches
+	anomalyBaselineWindow = 30 * 24 * time.Hour
+	// minBaselinePoints is the history a series needs before its days are judged
+	minBaselinePoints = 3
+	// minStdDevFraction keeps a perfectly flat baseline from turning every
+	// cent of change into an anomaly
+	minStdDevFraction = 0.01
+)
+
+// sensitivityThresholds maps detection sensitivity to the number of standard
+// deviations a daily cost must move away from its baseline
+var sensitivityThresholds = map[string]float64{
+	"LOW":    3,
+	"MEDIUM": 2,
+	"HIGH":   1,
+}
+
+// costSeries is the daily cost of a resource, or of the whole stack when urn is empty
+type costSeries struct {
+	urn    string
+	name   string
+	points []seriesPoint
+}
+
+type seriesPoint struct {
+	day   time.Time
+	cost  float64
+	usage *float64
+	unit  string
+}
+
+// deviation is a day on which a series left its baseline
+type deviation struct {
+	series   *costSeries
+	point    seriesPoint
+	baseline float64
+	sigma    float64
+	// baselineUsage is the mean usage of the baseline, if every point reported it in the same unit
+	baselineUsage *float64
+}
+
+// seriesFromResult builds one daily series per resource that reports data
+// points, or a single stack-wide series from the daily breakdown otherwise
+func seriesFromResult(result *adapter.CostResult) []*costSeries {
+	var series []*costSeries
+	for _, res := range result.Resources {
+		if len(res.DataPoints) == 0 {
+			continue
+		}
+		s := &costSeries{urn: res.Urn, name: res.Name}
+		for _, point := range res.DataPoints {
+			day, err := time.Parse(time.RFC3339, point.Timestamp)
+			if err != nil {
+				continue
+			}
+			s.points = append(s.points, seriesPoint{day: day, cost: point.Cost, usage: point.UsageAmount, unit: point.UsageUnit})
+		}
+		series = append(series, s)
+	}
+
+	if len(series) == 0 && result.Breakdown != nil {
+		s := &costSeries{name: "stack"}
+		for _, daily := range result.Breakdown.Daily {
+			day, err := time.Parse("2006-01-02", daily.Date)
+			if err != nil {
+				continue
+			}
+			s.points = append(s.points, seriesPoint{day: day, cost: daily.Amount})
+		}
+		series = append(series, s)
+	}
+
+	for _, s := range series {
+		sort.Slice(s.points, func(i, j int) bool { return s.points[i].day.Before(s.points[j].day) })
+	}
+	return series
+}
+
+// findDeviations compares each day from start to end against the mean and
+// standard deviation of the series' preceding baseline window
+func findDeviations(series []*costSeries, start, end time.Time, threshold float64) []deviation {
+	var deviations []deviation
+	for _, s := range series {
+		for i, point := range s.points {
+			if point.day.Before(start) || point.day.After(end) {
+				continue
+			}
+
+			var baseline []seriesPoint
+			for _, prior := range s.points[:i] {
+				if !prior.day.Before(point.day.Add(-anomalyBaselineWindow)) {
+					baseline = append(baseline, prior)
+				}
+			}
+			if len(baseline) < minBaselinePoints {
+				continue
+			}
+
+			mean, stdDev := meanStdDev(baseline)
+			stdDev = math.Max(stdDev, math.Max(mean*minStdDevFraction, minStdDevFraction))
+			sigma := (point.cost - mean) / stdDev
+			if math.Abs(sigma) < threshold {
+				continue
+			}
+
+			deviations = append(deviations, deviation{
+				series:        s,
+				point:         point,
+				baseline:      mean,
+				sigma:         sigma,
+				baselineUsage: meanUsage(baseline, point.unit),
+			})
+		}
+	}
+	return deviations
+}
+
+func meanStdDev(points []seriesPoint) (mean, stdDev float64) {
+	for _, p := range points {
+		mean += p.cost
+	}
+	mean /= float64(len(points))
+	for _, p := range points {
+		stdDev += (p.cost - mean) * (p.cost - mean)
+	}
+	return mean, math.Sqrt(stdDev / float64(len(points)))
+}
+
+func meanUsage(points []seriesPoint, unit string) *float64 {
+	total := 0.0
+	for _, p := range points {
+		if p.usage == nil || p.unit != unit {
+			return nil
+		}
+		total += *p.usage
+	}
+	mean := total / float64(len(points))
+	return &mean
+}
+
+// groupAnomalies reports one anomaly per day, listing the resources that
+// deviated that day by the size of their contribution
+func groupAnomalies(deviations []deviation) []*analysis.Anomaly {
+	byDay := make(map[time.Time][]deviation)
+	var days []time.Time
+	for _, d := range deviations {
+		if _, ok := byDay[d.point.day]; !ok {
+			days = append(days, d.point.day)
+		}
+		byDay[d.point.day] = append(byDay[d.point.day], d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	anomalies := make([]*analysis.Anomaly, 0, len(days))
+	for _, day := range days {
+		group := byDay[day]
+		sort.SliceStable(group, func(i, j int) bool {
+			return math.Abs(group[i].point.cost-group[i].baseline) > math.Abs(group[j].point.cost-group[j].baseline)
+		})
+
+		anomaly := &analysis.Anomaly{
+			ID:           "anom-" + day.UTC().Format("20060102"),
+			Timestamp:    day.UTC().Format(time.RFC3339),
+			ResourceUrns: []string{},
+		}
+		maxSigma := 0.0
+		for _, d := range group {
+			if d.series.urn != "" {
+				anomaly.ResourceUrns = append(anomaly.ResourceUrns, d.series.urn)
+			}
+			anomaly.CurrentCost += d.point.cost
+			anomaly.BaselineCost += d.baseline
+			maxSigma = math.Max(maxSigma, math.Abs(d.sigma))
+			anomaly.PotentialCauses = append(anomaly.PotentialCauses, d.cause())
+		}
+		anomaly.CurrentCost = roundCents(anomaly.CurrentCost)
+		anomaly.BaselineCost = roundCents(anomaly.BaselineCost)
+		if anomaly.BaselineCost > 0 {
+			anomaly.DeviationPercent = math.Round((anomaly.CurrentCost-anomaly.BaselineCost)/anomaly.BaselineCost*10000) / 100
+		}
+		anomaly.Severity = anomalySeverity(maxSigma, math.Abs(anomaly.DeviationPercent), anomaly.BaselineCost == 0)
+
+		anomalies = append(anomalies, anomaly)
+	}
+	return anomalies
+}
+
+// anomalySeverity grades a deviation by its distance from the baseline in
+// standard deviations and by the relative change in cost
+func anomalySeverity(sigma, percent float64, noBaseline bool) string {
+	switch {
+	case sigma > 3 && (percent > 100 || noBaseline):
+		return "CRITICAL"
+	case sigma >= 2 && percent >= 50:
+		return "HIGH"
+	case sigma >= 1 && percent >= 25:
+		return "MEDIUM"
+	default:
+		return "LOW"
+	}
+}
+
+// cause explains a deviation from the resource's cost and usage
+func (d deviation) cause() string {
+	name := d.series.name
+	direction, verb := "above", "rose"
+	if d.point.cost < d.baseline {
+		direction, verb = "below", "fell"
+	}
+
+	cause := fmt.Sprintf("Daily cost of %s %s to %.2f, %.1fσ %s its %.2f baseline", name, verb, d.point.cost, math.Abs(d.sigma), direction, d.baseline)
+	if d.point.usage != nil && d.baselineUsage != nil {
+		if *d.point.usage == *d.baselineUsage {
+			cause += fmt.Sprintf("; usage unchanged at %.4g %s, suggesting a pricing or rate change", *d.point.usage, d.point.unit)
+		} else {
+			cause += fmt.Sprintf("; usage went from %.4g to %.4g %s", *d.baselineUsage, *d.point.usage, d.point.unit)
+		}
+	}
+	if d.point.cost < d.baseline && d.point.cost == 0 {
+		cause += "; the resource may have been stopped or removed"
+	}
+	return cause
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
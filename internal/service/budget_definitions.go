@@ -0,0 +1,189 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/gen/analysis"
+	"github.com/rshade/pulumicost-mcp/internal/adapter"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultBudgetCurrency is assumed for budgets that do not name a currency
+const defaultBudgetCurrency = "USD"
+
+// BudgetDefinition is a team or project budget from the budgets file
+type BudgetDefinition struct {
+	Name            string      `yaml:"name"`
+	Description     string      `yaml:"description"`
+	Amount          float64     `yaml:"amount"`
+	Currency        string      `yaml:"currency"`
+	Period          string      `yaml:"period"`
+	AlertThresholds []float64   `yaml:"alert_thresholds"`
+	Scope           BudgetScope `yaml:"scope"`
+}
+
+// BudgetScope selects the spending a budget covers: every resource of its
+// stacks, narrowed to those carrying all Tags and belonging to Providers
+type BudgetScope struct {
+	Stacks    []string          `yaml:"stacks"`
+	Tags      map[string]string `yaml:"tags"`
+	Providers []string          `yaml:"providers"`
+}
+
+// budgetsFile is the layout of the budgets file
+type budgetsFile struct {
+	Budgets []BudgetDefinition `yaml:"budgets"`
+}
+
+// LoadBudgets reads and validates the budget definitions in a YAML file
+func LoadBudgets(path string) ([]BudgetDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read budgets file: %w", err)
+	}
+
+	var file budgetsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse budgets file: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range file.Budgets {
+		def := &file.Budgets[i]
+		if err := def.normalize(); err != nil {
+			return nil, fmt.Errorf("budget %d (%q): %w", i, def.Name, err)
+		}
+		if seen[def.Name] {
+			return nil, fmt.Errorf("budget %q is defined more than once", def.Name)
+		}
+		seen[def.Name] = true
+	}
+	return file.Budgets, nil
+}
+
+// normalize validates a definition and fills in its defaults
+func (d *BudgetDefinition) normalize() error {
+	d.Period = strings.ToUpper(d.Period)
+	d.Currency = strings.ToUpper(d.Currency)
+	if d.Currency == "" {
+		d.Currency = defaultBudgetCurrency
+	}
+	for i, provider := range d.Scope.Providers {
+		d.Scope.Providers[i] = strings.ToLower(provider)
+	}
+
+	switch {
+	case d.Name == "":
+		return fmt.Errorf("name is required")
+	case d.Amount <= 0:
+		return fmt.Errorf("amount must be positive")
+	case len(d.Scope.Stacks) == 0:
+		return fmt.Errorf("scope.stacks must name at least one stack")
+	}
+	if _, _, err := budgetWindow(d.Period, time.Now()); err != nil {
+		return err
+	}
+	for _, threshold := range d.AlertThresholds {
+		if threshold < 0 || threshold > 100 {
+			return fmt.Errorf("alert threshold %v must be between 0 and 100", threshold)
+		}
+	}
+	return nil
+}
+
+// covers reports whether the budget includes the stack
+func (d *BudgetDefinition) covers(stackName string) bool {
+	for _, stack := range d.Scope.Stacks {
+		if stack == stackName {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeCosts keeps the resources of a stack's actual costs that the scope
+// covers. Without tag or provider filters the result is returned unchanged.
+func scopeCosts(result *adapter.CostResult, scope BudgetScope) *adapter.CostResult {
+	if len(scope.Tags) == 0 && len(scope.Providers) == 0 {
+		return result
+	}
+
+	scoped := &adapter.CostResult{Currency: result.Currency}
+	for _, res := range result.Resources {
+		if !scope.matches(res) {
+			continue
+		}
+		scoped.Resources = append(scoped.Resources, res)
+		scoped.TotalMonthly += res.MonthlyCost
+	}
+	return scoped
+}
+
+// matches reports whether a resource carries every scope tag and belongs to
+// one of the scope providers
+func (s BudgetScope) matches(res adapter.ResourceCost) bool {
+	for key, value := range s.Tags {
+		if res.Tags[key] != value {
+			return false
+		}
+	}
+	if len(s.Providers) == 0 {
+		return true
+	}
+	provider := res.ProviderName()
+	for _, p := range s.Providers {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeBudgetSpend combines the scoped actual costs of several stacks into a
+// single total with a daily breakdown
+func mergeBudgetSpend(results []*adapter.CostResult) *adapter.CostResult {
+	merged := &adapter.CostResult{Breakdown: &adapter.CostBreakdown{}}
+	byDate := make(map[string]float64)
+	for _, result := range results {
+		merged.TotalMonthly += result.TotalMonthly
+		if merged.Currency == "" {
+			merged.Currency = result.Currency
+		}
+		for _, d := range dailySpend(result) {
+			byDate[d.day.Format("2006-01-02")] += d.cost
+		}
+	}
+
+	for date, amount := range byDate {
+		merged.Breakdown.Daily = append(merged.Breakdown.Daily, adapter.DailyCost{Date: date, Amount: amount})
+	}
+	sort.Slice(merged.Breakdown.Daily, func(i, j int) bool {
+		return merged.Breakdown.Daily[i].Date < merged.Breakdown.Daily[j].Date
+	})
+	return merged
+}
+
+// convertBudgetDefinition converts a budget definition to the API type
+func convertBudgetDefinition(def BudgetDefinition) *analysis.BudgetDefinition {
+	converted := &analysis.BudgetDefinition{
+		Name:            def.Name,
+		Amount:          def.Amount,
+		Currency:        def.Currency,
+		Period:          def.Period,
+		AlertThresholds: def.AlertThresholds,
+		Scope: &analysis.BudgetScope{
+			Stacks:    def.Scope.Stacks,
+			Tags:      def.Scope.Tags,
+			Providers: def.Scope.Providers,
+		},
+	}
+	if def.Description != "" {
+		description := def.Description
+		converted.Description = &description
+	}
+	return converted
+}
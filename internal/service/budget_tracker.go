@@ -0,0 +1,162 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/gen/analysis"
+	"github.com/rshade/pulumicost-mcp/internal/adapter"
+)
+
+// budgetWindow returns the UTC bounds of the budget period containing now.
+// Weeks start on Monday.
+func budgetWindow(period string, now time.Time) (start, end time.Time, err error) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch period {
+	case "DAILY":
+		return today, today.AddDate(0, 0, 1), nil
+	case "WEEKLY":
+		start = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7), nil
+	case "MONTHLY":
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0), nil
+	case "YEARLY":
+		start = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(1, 0, 0), nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: unknown budget period %q", adapter.ErrInvalidInput, period)
+	}
+}
+
+// dailySpend sums the period-to-date actual costs by day, oldest first
+func dailySpend(result *adapter.CostResult) []seriesPoint {
+	byDay := make(map[time.Time]float64)
+	for _, s := range seriesFromResult(result) {
+		for _, point := range s.points {
+			byDay[point.day.UTC().Truncate(24*time.Hour)] += point.cost
+		}
+	}
+
+	days := make([]seriesPoint, 0, len(byDay))
+	for d, cost := range byDay {
+		days = append(days, seriesPoint{day: d, cost: cost})
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].day.Before(days[j].day) })
+	return days
+}
+
+// evaluateBudget compares period-to-date spending against a budget. The burn
+// rate is the average of the daily breakdown, or the spending spread over the
+// elapsed days when the source reports no breakdown.
+func evaluateBudget(amount float64, period string, thresholds []float64, result *adapter.CostResult, start, end, now time.Time) *analysis.Budget {
+	spending := roundCents(result.TotalMonthly)
+	days := dailySpend(result)
+
+	burnRate := 0.0
+	if len(days) > 0 {
+		for _, d := range days {
+			burnRate += d.cost
+		}
+		burnRate /= float64(len(days))
+	} else if elapsed := now.Sub(start).Hours() / 24; elapsed > 0 {
+		burnRate = spending / math.Max(elapsed, 1)
+	}
+	burnRate = roundCents(burnRate)
+
+	remaining := roundCents(amount - spending)
+	projected := roundCents(spending + burnRate*end.Sub(now).Hours()/24)
+	percentageUsed := 0.0
+	if amount > 0 {
+		percentageUsed = spending / amount * 100
+	}
+
+	budget := &analysis.Budget{
+		BudgetAmount:      amount,
+		Period:            period,
+		PeriodWindow:      &analysis.TimeRange{Start: start.Format(time.RFC3339), End: end.Format(time.RFC3339)},
+		CurrentSpending:   spending,
+		Remaining:         remaining,
+		BurnRate:          &burnRate,
+		ProjectedSpending: &projected,
+		Status:            budgetStatus(percentageUsed),
+		Alerts:            []*analysis.BudgetAlert{},
+	}
+	if result.Currency != "" {
+		currency := result.Currency
+		budget.Currency = &currency
+	}
+	if exhausted, ok := exhaustionDate(amount, spending, burnRate, days, end, now); ok {
+		date := exhausted.Format(time.RFC3339)
+		budget.ProjectedEndDate = &date
+	}
+
+	for _, threshold := range thresholds {
+		if percentageUsed < threshold {
+			continue
+		}
+		budget.Alerts = append(budget.Alerts, &analysis.BudgetAlert{
+			Threshold:      threshold,
+			CurrentSpend:   spending,
+			PercentageUsed: math.Round(percentageUsed*100) / 100,
+			Severity:       alertSeverity(percentageUsed),
+			Message:        fmt.Sprintf("Budget utilization at %.1f%% (threshold: %.1f%%)", percentageUsed, threshold),
+			Timestamp:      now.UTC().Format(time.RFC3339),
+		})
+	}
+	return budget
+}
+
+// exhaustionDate finds the day the budget ran out, or projects when it will
+// at the burn rate. ok is false if it lasts beyond the end of the period.
+func exhaustionDate(amount, spending, burnRate float64, days []seriesPoint, end, now time.Time) (time.Time, bool) {
+	if spending >= amount {
+		cumulative := 0.0
+		for _, d := range days {
+			cumulative += d.cost
+			if cumulative >= amount {
+				return d.day, true
+			}
+		}
+		return now.UTC(), true
+	}
+	if burnRate <= 0 {
+		return time.Time{}, false
+	}
+
+	exhausted := now.UTC().Add(time.Duration((amount - spending) / burnRate * float64(24*time.Hour)))
+	if !exhausted.Before(end) {
+		return time.Time{}, false
+	}
+	return exhausted.Truncate(time.Second), true
+}
+
+func budgetStatus(percentageUsed float64) string {
+	switch {
+	case percentageUsed >= 100:
+		return "EXCEEDED"
+	case percentageUsed >= 90:
+		return "CRITICAL"
+	case percentageUsed >= 80:
+		return "WARNING"
+	default:
+		return "OK"
+	}
+}
+
+func alertSeverity(percentageUsed float64) string {
+	switch {
+	case percentageUsed >= 100:
+		return "CRITICAL"
+	case percentageUsed >= 90:
+		return "HIGH"
+	case percentageUsed >= 80:
+		return "MEDIUM"
+	default:
+		return "INFO"
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/gen/analysis"
+	"github.com/rshade/pulumicost-mcp/internal/adapter"
+)
+
+// commitmentCategories are the resource types reservations, savings plans
+// and committed use discounts apply to, keyed by package, module and
+// resource name such as "aws:ec2:instance"
+var commitmentCategories = map[string]string{
+	"aws:ec2:instance":                            "COMPUTE",
+	"aws:ecs:service":                             "COMPUTE",
+	"aws:lambda:function":                         "COMPUTE",
+	"aws:rds:instance":                            "DATABASE",
+	"aws:rds:cluster":                             "DATABASE",
+	"aws:elasticache:cluster":                     "DATABASE",
+	"aws:elasticache:replicationgroup":            "DATABASE",
+	"aws:redshift:cluster":                        "DATABASE",
+	"azure:compute:virtualmachine":                "COMPUTE",
+	"azure:compute:linuxvirtualmachine":           "COMPUTE",
+	"azure:compute:windowsvirtualmachine":         "COMPUTE",
+	"azure:compute:virtualmachinescaleset":        "COMPUTE",
+	"azure:compute:linuxvirtualmachinescaleset":   "COMPUTE",
+	"azure:mssql:database":                        "DATABASE",
+	"azure:postgresql:flexibleserver":             "DATABASE",
+	"azure:mysql:flexibleserver":                  "DATABASE",
+	"azure:cosmosdb:account":                      "DATABASE",
+	"azure-native:compute:virtualmachine":         "COMPUTE",
+	"azure-native:compute:virtualmachinescaleset": "COMPUTE",
+	"azure-native:sql:database":                   "DATABASE",
+	"azure-native:dbforpostgresql:server":         "DATABASE",
+	"azure-native:dbformysql:server":              "DATABASE",
+	"gcp:compute:instance":                        "COMPUTE",
+	"gcp:container:nodepool":                      "COMPUTE",
+	"gcp:sql:databaseinstance":                    "DATABASE",
+}
+
+// commitmentService returns the service and category of a resource type such
+// as "aws:rds/instance:Instance", and false if commitments do not apply to it
+func commitmentService(resType string) (service, category string, ok bool) {
+	parts := strings.Split(strings.ToLower(resType), ":")
+	if len(parts) != 3 {
+		return "", "", false
+	}
+	service, _, _ = strings.Cut(parts[1], "/")
+	category, ok = commitmentCategories[parts[0]+":"+service+":"+parts[2]]
+	return service, category, ok
+}
+
+// Billing classes of a resource's spend
+const (
+	billingOnDemand  = "on_demand"
+	billingCommitted = "committed"
+	billingSpot      = "spot"
+)
+
+// billingClass classifies a billing mode reported by the cost source.
+// known is false when the source reported none.
+func billingClass(mode *string) (class string, known bool) {
+	if mode == nil || *mode == "" {
+		return billingOnDemand, false
+	}
+	normalized := strings.ToLower(*mode)
+	switch {
+	case strings.Contains(normalized, "reserved"),
+		strings.Contains(normalized, "savings"),
+		strings.Contains(normalized, "commit"):
+		return billingCommitted, true
+	case strings.Contains(normalized, "spot"), strings.Contains(normalized, "preemptible"):
+		return billingSpot, true
+	default:
+		return billingOnDemand, true
+	}
+}
+
+// commitmentGroup accumulates the spend of one provider's service
+type commitmentGroup struct {
+	group *analysis.CommitmentGroup
+	// onDemandByDay is the group's on-demand spend per day
+	onDemandByDay map[time.Time]float64
+}
+
+// buildCommitmentCoverage groups a stack's compute and database spend by
+// provider and service. Spot spend is left out since commitments cannot
+// cover it. The steady-state spend is the lowest daily on-demand spend over
+// the days of the time range; a day without spend makes it zero.
+func buildCommitmentCoverage(result *adapter.CostResult, start, end time.Time) ([]*analysis.CommitmentGroup, []string) {
+	groups := make(map[string]*commitmentGroup)
+	unknown := []string{}
+
+	for i := range result.Resources {
+		res := &result.Resources[i]
+		service, category, ok := commitmentService(res.Type)
+		if !ok {
+			continue
+		}
+		class, known := billingClass(res.BillingMode)
+		if class == billingSpot {
+			continue
+		}
+		if !known {
+			unknown = append(unknown, res.Urn)
+		}
+
+		provider := res.ProviderName()
+		key := provider + "/" + service
+		g, ok := groups[key]
+		if !ok {
+			g = &commitmentGroup{
+				group: &analysis.CommitmentGroup{
+					Provider:        provider,
+					Service:         service,
+					Category:        category,
+					ResourceUrns:    []string{},
+					Recommendations: []*analysis.Recommendation{},
+				},
+				onDemandByDay: make(map[time.Time]float64),
+			}
+			groups[key] = g
+		}
+		g.group.ResourceUrns = append(g.group.ResourceUrns, res.Urn)
+
+		spend := res.MonthlyCost
+		if len(res.DataPoints) > 0 {
+			spend = 0
+			for _, point := range res.DataPoints {
+				spend += point.Cost
+				if class != billingOnDemand {
+					continue
+				}
+				day, err := time.Parse(time.RFC3339, point.Timestamp)
+				if err != nil {
+					continue
+				}
+				g.onDemandByDay[day.UTC().Truncate(24*time.Hour)] += point.Cost
+			}
+		}
+
+		g.group.TotalSpend += spend
+		if class == billingCommitted {
+			g.group.CommittedSpend += spend
+		} else {
+			g.group.OnDemandSpend += spend
+		}
+	}
+
+	days := int(math.Ceil(end.Sub(start).Hours() / 24))
+	covered := make([]*analysis.CommitmentGroup, 0, len(groups))
+	for _, g := range groups {
+		if days > 0 && len(g.onDemandByDay) >= days {
+			floor := math.Inf(1)
+			for _, cost := range g.onDemandByDay {
+				floor = math.Min(floor, cost)
+			}
+			g.group.SteadyStateSpend = roundCents(floor * float64(days))
+		}
+		g.group.TotalSpend = roundCents(g.group.TotalSpend)
+		g.group.CommittedSpend = roundCents(g.group.CommittedSpend)
+		g.group.OnDemandSpend = roundCents(g.group.OnDemandSpend)
+		g.group.CoveragePercentage = coveragePercentage(g.group.CommittedSpend, g.group.TotalSpend)
+		covered = append(covered, g.group)
+	}
+
+	sort.Slice(covered, func(i, j int) bool {
+		if covered[i].SteadyStateSpend != covered[j].SteadyStateSpend {
+			return covered[i].SteadyStateSpend > covered[j].SteadyStateSpend
+		}
+		if covered[i].Provider != covered[j].Provider {
+			return covered[i].Provider < covered[j].Provider
+		}
+		return covered[i].Service < covered[j].Service
+	})
+	return covered, unknown
+}
+
+// coveragePercentage returns committed as a percentage of total
+func coveragePercentage(committed, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Round(committed/total*10000) / 100
+}
+
+// attachCommitmentRecommendations adds each RESERVED_INSTANCES recommendation
+// to the group holding its primary resource
+func attachCommitmentRecommendations(groups []*analysis.CommitmentGroup, recommendations []*analysis.Recommendation) {
+	byURN := make(map[string]*analysis.CommitmentGroup)
+	for _, g := range groups {
+		for _, urn := range g.ResourceUrns {
+			byURN[urn] = g
+		}
+	}
+	for _, rec := range recommendations {
+		if rec.Type != "RESERVED_INSTANCES" {
+			continue
+		}
+		if g, ok := byURN[rec.ResourceUrn]; ok {
+			g.Recommendations = append(g.Recommendations, rec)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/rshade/pulumicost-mcp/internal/adapter"
@@ -16,15 +17,29 @@ import (
 // CostService implements the cost.Service interface
 type CostService struct {
 	adapter adapter.PulumiCostAdapter
+	router  *adapter.PluginRouter
+	fanOut  *adapter.FanOutExecutor
 	logger  *logging.Logger
 }
 
 // NewCostService creates a new Cost Service instance
 func NewCostService(pulumiAdapter adapter.PulumiCostAdapter, logger *logging.Logger) *CostService {
-	return &CostService{
+	return NewCostServiceWithRouter(pulumiAdapter, nil, 1, logger)
+}
+
+// NewCostServiceWithRouter creates a Cost Service that prices each provider
+// through the plugin chosen by router, calling at most maxConcurrent plugins
+// at once. A nil router leaves plugin selection to pulumicost-core.
+func NewCostServiceWithRouter(pulumiAdapter adapter.PulumiCostAdapter, router *adapter.PluginRouter, maxConcurrent int, logger *logging.Logger) *CostService {
+	s := &CostService{
 		adapter: pulumiAdapter,
+		router:  router,
 		logger:  logger,
 	}
+	if router != nil {
+		s.fanOut = adapter.NewFanOutExecutor(router, maxConcurrent, logger)
+	}
+	return s
 }
 
 // AnalyzeProjected calculates projected costs from Pulumi preview JSON
@@ -62,14 +77,7 @@ func (s *CostService) AnalyzeProjected(ctx context.Context, payload *cost.Analyz
 	}
 
 	// Call adapter
-	var adapterResult *adapter.CostResult
-	var err error
-
-	if filters != nil {
-		adapterResult, err = s.adapter.GetProjectedCostWithFilters(ctx, payload.PulumiJSON, filters)
-	} else {
-		adapterResult, err = s.adapter.GetProjectedCost(ctx, payload.PulumiJSON)
-	}
+	fanOutResult, err := s.projectedCost(ctx, payload.PulumiJSON, filters)
 
 	if err != nil {
 		s.logger.WithService("cost").ErrorJSON("adapter call failed", err, nil)
@@ -79,7 +87,8 @@ func (s *CostService) AnalyzeProjected(ctx context.Context, payload *cost.Analyz
 	}
 
 	// Convert adapter result to Goa result type
-	result := convertToCostResult(adapterResult)
+	result := convertToCostResult(fanOutResult.Result)
+	applyProviderFailures(result, fanOutResult.Failures)
 
 	// Record metrics
 	metrics.RecordRequest("cost", "analyze_projected", time.Since(start))
@@ -137,14 +146,11 @@ func (s *CostService) GetActual(ctx context.Context, payload *cost.GetActualPayl
 	}
 
 	// Call adapter
-	var adapterResult *adapter.CostResult
-	var err error
-
-	if payload.Granularity != nil {
-		adapterResult, err = s.adapter.GetActualCostWithGranularity(ctx, payload.StackName, timeRange, *payload.Granularity)
-	} else {
-		adapterResult, err = s.adapter.GetActualCost(ctx, payload.StackName, timeRange)
+	var provider *string
+	if payload.Filters != nil {
+		provider = payload.Filters.Provider
 	}
+	fanOutResult, err := s.actualCost(ctx, payload.StackName, timeRange, stringValue(payload.Granularity), provider)
 
 	if err != nil {
 		s.logger.WithService("cost").ErrorJSON("adapter call failed", err, nil)
@@ -154,7 +160,8 @@ func (s *CostService) GetActual(ctx context.Context, payload *cost.GetActualPayl
 	}
 
 	// Convert adapter result to Goa result type
-	result := convertToCostResult(adapterResult)
+	result := convertToCostResult(fanOutResult.Result)
+	applyProviderFailures(result, fanOutResult.Failures)
 
 	// Record metrics
 	metrics.RecordRequest("cost", "get_actual", time.Since(start))
@@ -322,6 +329,119 @@ func (s *CostService) AnalyzeStack(ctx context.Context, payload *cost.AnalyzeSta
 
 // Helper functions
 
+// projectedCost prices a preview. With a router configured, the preview is
+// split by provider and each provider is priced by its own plugin, or by
+// pulumicost-core when no plugin covers it; a provider filter narrows this
+// to a single provider.
+func (s *CostService) projectedCost(ctx context.Context, pulumiJSON string, filters *adapter.ResourceFilters) (*adapter.FanOutResult, error) {
+	if s.fanOut == nil {
+		result, err := s.adapter.GetProjectedCostWithFilters(ctx, pulumiJSON, filters)
+		if err != nil {
+			return nil, err
+		}
+		return &adapter.FanOutResult{Result: result}, nil
+	}
+
+	previews, err := adapter.SplitPreviewByProvider(pulumiJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []adapter.ProviderRequest
+	if filters != nil && filters.Provider != nil {
+		var urns []string
+		for _, preview := range previews {
+			if strings.EqualFold(preview.Provider, *filters.Provider) {
+				urns = preview.URNs
+			}
+		}
+		requests = append(requests, projectedRequest(s.adapter, *filters.Provider, pulumiJSON, urns, filters))
+	} else {
+		for _, preview := range previews {
+			requests = append(requests, projectedRequest(s.adapter, preview.Provider, preview.JSON, preview.URNs, filters))
+		}
+	}
+
+	if len(requests) == 0 {
+		// Nothing attributable to a provider; let pulumicost-core decide
+		result, err := s.adapter.GetProjectedCostWithFilters(ctx, pulumiJSON, filters)
+		if err != nil {
+			return nil, err
+		}
+		return &adapter.FanOutResult{Result: result}, nil
+	}
+
+	return s.fanOut.Execute(ctx, adapter.CostKindProjected, requests)
+}
+
+// projectedRequest builds the fan-out request pricing one provider's preview
+func projectedRequest(pulumiAdapter adapter.PulumiCostAdapter, provider, pulumiJSON string, urns []string, filters *adapter.ResourceFilters) adapter.ProviderRequest {
+	return adapter.ProviderRequest{
+		Provider: provider,
+		URNs:     urns,
+		Call: func(ctx context.Context, pluginName string) (*adapter.CostResult, error) {
+			return pulumiAdapter.GetProjectedCostWithAdapter(ctx, pulumiJSON, filters, pluginName)
+		},
+		Core: func(ctx context.Context) (*adapter.CostResult, error) {
+			return pulumiAdapter.GetProjectedCostWithFilters(ctx, pulumiJSON, filters)
+		},
+	}
+}
+
+// actualCost retrieves historical costs. With a router configured, every
+// provider covered by an installed plugin is queried through its own plugin
+// and pulumicost-core reports the others; a provider filter narrows this to
+// a single provider.
+func (s *CostService) actualCost(ctx context.Context, stackName string, timeRange adapter.TimeRange, granularity string, provider *string) (*adapter.FanOutResult, error) {
+	if s.fanOut == nil {
+		result, err := s.adapter.GetActualCostWithGranularity(ctx, stackName, timeRange, granularity)
+		if err != nil {
+			return nil, err
+		}
+		return &adapter.FanOutResult{Result: result}, nil
+	}
+
+	var providers []string
+	if provider != nil {
+		providers = append(providers, *provider)
+	} else {
+		discovered, err := s.router.Providers(ctx, adapter.CostKindActual)
+		if err != nil {
+			return nil, err
+		}
+		providers = discovered
+	}
+
+	if len(providers) == 0 {
+		// No installed plugin reports actual costs; let pulumicost-core decide
+		result, err := s.adapter.GetActualCostWithGranularity(ctx, stackName, timeRange, granularity)
+		if err != nil {
+			return nil, err
+		}
+		return &adapter.FanOutResult{Result: result}, nil
+	}
+
+	core := func(ctx context.Context) (*adapter.CostResult, error) {
+		return s.adapter.GetActualCostWithGranularity(ctx, stackName, timeRange, granularity)
+	}
+	requests := make([]adapter.ProviderRequest, 0, len(providers)+1)
+	for _, p := range providers {
+		requests = append(requests, adapter.ProviderRequest{
+			Provider: p,
+			Call: func(ctx context.Context, pluginName string) (*adapter.CostResult, error) {
+				return s.adapter.GetActualCostWithAdapter(ctx, stackName, timeRange, granularity, pluginName)
+			},
+			Core: core,
+		})
+	}
+	if provider == nil {
+		// pulumicost-core reports the providers no plugin covers
+		requests = append(requests, adapter.ProviderRequest{Core: core})
+	}
+
+	return s.fanOut.Execute(ctx, adapter.CostKindActual, requests)
+}
+
 // convertToCostResult converts adapter.CostResult to cost.CostResult
 func convertToCostResult(adapterResult *adapter.CostResult) *cost.CostResult {
 	if adapterResult == nil {
@@ -339,6 +459,8 @@ func convertToCostResult(adapterResult *adapter.CostResult) *cost.CostResult {
 			MonthlyCost: res.MonthlyCost,
 			HourlyCost:  res.HourlyCost,
 			Currency:    adapterResult.Currency,
+			Adapter:     res.Adapter,
+			BillingMode: res.BillingMode,
 		}
 	}
 
@@ -367,6 +489,28 @@ func convertToCostResult(adapterResult *adapter.CostResult) *cost.CostResult {
 	}
 }
 
+// applyProviderFailures marks a result as partial and lists the providers
+// that could not be priced
+func applyProviderFailures(result *cost.CostResult, failures []adapter.ProviderFailure) {
+	if result == nil || len(failures) == 0 {
+		return
+	}
+
+	result.Partial = true
+	for _, failure := range failures {
+		pf := &cost.ProviderFailure{
+			Provider:     failure.Provider,
+			Error:        failure.Err.Error(),
+			UnpricedUrns: failure.UnpricedURNs,
+		}
+		if failure.Plugin != "" {
+			plugin := failure.Plugin
+			pf.Plugin = &plugin
+		}
+		result.FailedProviders = append(result.FailedProviders, pf)
+	}
+}
+
 // stringValue returns the string value or empty string if nil
 func stringValue(s *string) string {
 	if s == nil {
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/rshade/pulumicost-mcp/internal/adapter"
@@ -79,6 +81,107 @@ func TestAnalyzeProjected_WithFilters(t *testing.T) {
 	assert.NotNil(t, result)
 }
 
+// TestAnalyzeProjected_RoutesToPlugin verifies provider-scoped requests report the serving plugin
+func TestAnalyzeProjected_RoutesToPlugin(t *testing.T) {
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "aws-cur"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "aws-cur", "plugin.json"), []byte(`{
+		"name": "aws-cur",
+		"version": "1.0.0",
+		"providers": "aws",
+		"capabilities": {"supports_projected_cost": true}
+	}`), 0644))
+
+	mockAdapter := adapter.NewPulumiCostAdapter("../adapter/testdata/mock_pulumicost.sh")
+	router := adapter.NewPluginRouter(adapter.NewPluginAdapter(pluginDir, nil), nil, nil)
+	service := NewCostServiceWithRouter(mockAdapter, router, 1, nil)
+
+	provider := "aws"
+	result, err := service.AnalyzeProjected(context.Background(), &cost.AnalyzeProjectedPayload{
+		PulumiJSON: `{"resources": []}`,
+		Filters:    &cost.ResourceFilter{Provider: &provider},
+	})
+
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Resources)
+	for _, res := range result.Resources {
+		require.NotNil(t, res.Adapter)
+		assert.Equal(t, "aws-cur", *res.Adapter)
+	}
+
+	// No plugin covers gcp, so pulumicost-core prices it
+	provider = "gcp"
+	result, err = service.AnalyzeProjected(context.Background(), &cost.AnalyzeProjectedPayload{
+		PulumiJSON: `{"resources": []}`,
+		Filters:    &cost.ResourceFilter{Provider: &provider},
+	})
+	require.NoError(t, err)
+	assert.False(t, result.Partial)
+	assert.Empty(t, result.Resources, "the mock stack has no gcp resources")
+}
+
+// TestAnalyzeProjected_NoPlugins verifies a router without plugins leaves pricing to pulumicost-core
+func TestAnalyzeProjected_NoPlugins(t *testing.T) {
+	mockAdapter := adapter.NewPulumiCostAdapter("../adapter/testdata/mock_pulumicost.sh")
+	router := adapter.NewPluginRouter(adapter.NewPluginAdapter(t.TempDir(), nil), nil, nil)
+	service := NewCostServiceWithRouter(mockAdapter, router, 2, nil)
+
+	result, err := service.AnalyzeProjected(context.Background(), &cost.AnalyzeProjectedPayload{
+		PulumiJSON: `{"resources": [
+			{"urn": "urn:pulumi:dev::myapp::aws:ec2/instance:Instance::web-server", "type": "aws:ec2/instance:Instance"}
+		]}`,
+	})
+
+	require.NoError(t, err)
+	assert.False(t, result.Partial)
+	assert.Equal(t, 42.5, result.TotalMonthly)
+	require.Len(t, result.Resources, 2)
+	assert.Nil(t, result.Resources[0].Adapter, "priced by pulumicost-core")
+}
+
+// TestAnalyzeProjected_PartialResult verifies unpriced providers are reported instead of failing
+func TestAnalyzeProjected_PartialResult(t *testing.T) {
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "aws-cur"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "aws-cur", "plugin.json"), []byte(`{
+		"name": "aws-cur",
+		"version": "1.0.0",
+		"providers": "aws",
+		"capabilities": {"supports_projected_cost": true}
+	}`), 0644))
+
+	// The aws-cur plugin fails; gcp has no plugin and is priced by pulumicost-core
+	corePath := filepath.Join(t.TempDir(), "pulumicost")
+	require.NoError(t, os.WriteFile(corePath, []byte(`#!/bin/bash
+cat > /dev/null
+if [[ " $* " == *" --adapter "* ]]; then
+  echo "plugin unreachable" >&2
+  exit 1
+fi
+echo '{"total_monthly": 7, "currency": "USD", "resources": [
+  {"urn": "urn:pulumi:dev::myapp::gcp:compute/instance:Instance::worker", "name": "worker", "type": "gcp:compute/instance:Instance", "provider": "gcp", "monthly_cost": 7}
+]}'
+`), 0755))
+
+	router := adapter.NewPluginRouter(adapter.NewPluginAdapter(pluginDir, nil), nil, nil)
+	service := NewCostServiceWithRouter(adapter.NewPulumiCostAdapter(corePath), router, 2, nil)
+
+	result, err := service.AnalyzeProjected(context.Background(), &cost.AnalyzeProjectedPayload{
+		PulumiJSON: `{"resources": [
+			{"urn": "urn:pulumi:dev::myapp::aws:ec2/instance:Instance::web-server", "type": "aws:ec2/instance:Instance"},
+			{"urn": "urn:pulumi:dev::myapp::gcp:compute/instance:Instance::worker", "type": "gcp:compute/instance:Instance"}
+		]}`,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Partial)
+	require.Len(t, result.Resources, 1)
+	assert.Equal(t, "worker", result.Resources[0].Name)
+	require.Len(t, result.FailedProviders, 1)
+	assert.Equal(t, "aws", result.FailedProviders[0].Provider)
+	assert.Equal(t, []string{"urn:pulumi:dev::myapp::aws:ec2/instance:Instance::web-server"}, result.FailedProviders[0].UnpricedUrns)
+}
+
 // T024: TestGetActual - RED test for FR-002
 func TestGetActual(t *testing.T) {
 	// Arrange
@@ -103,7 +206,36 @@ func TestGetActual(t *testing.T) {
 	require.NotNil(t, result)
 	assert.Greater(t, result.TotalMonthly, 0.0)
 	assert.Equal(t, "USD", result.Currency)
-	assert.NotEmpty(t, result.Resources)
+	require.NotEmpty(t, result.Resources)
+	require.NotNil(t, result.Resources[0].BillingMode, "billing mode is passed through")
+	assert.Equal(t, "reserved", *result.Resources[0].BillingMode)
+}
+
+// TestGetActual_UncoveredProviders verifies providers without an actual cost
+// plugin are reported by pulumicost-core next to the plugin's provider
+func TestGetActual_UncoveredProviders(t *testing.T) {
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "kubecost"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "kubecost", "plugin.json"), []byte(`{
+		"name": "kubecost",
+		"version": "1.0.0",
+		"providers": "kubernetes",
+		"capabilities": {"supports_actual_cost": true}
+	}`), 0644))
+
+	mockAdapter := adapter.NewPulumiCostAdapter("../adapter/testdata/mock_pulumicost.sh")
+	router := adapter.NewPluginRouter(adapter.NewPluginAdapter(pluginDir, nil), nil, nil)
+	service := NewCostServiceWithRouter(mockAdapter, router, 2, nil)
+
+	result, err := service.GetActual(context.Background(), &cost.GetActualPayload{
+		StackName: "myapp-dev",
+		TimeRange: &cost.TimeRange{Start: "2024-01-01T00:00:00Z", End: "2024-01-04T00:00:00Z"},
+	})
+
+	require.NoError(t, err)
+	assert.False(t, result.Partial)
+	assert.Len(t, result.Resources, 2, "aws resources come from pulumicost-core")
+	assert.Equal(t, 42.5, result.TotalMonthly)
 }
 
 // T024: TestGetActual_InvalidTimeRange
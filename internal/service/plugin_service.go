@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/rshade/pulumicost-mcp/internal/adapter"
 	"github.com/rshade/pulumicost-mcp/internal/logging"
 	"github.com/rshade/pulumicost-mcp/internal/metrics"
+	"github.com/rshade/pulumicost-mcp/internal/report"
 	"github.com/rshade/pulumicost-mcp/internal/tracing"
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -17,14 +19,28 @@ import (
 type PluginService struct {
 	pluginAdapter *adapter.PluginAdapter
 	specAdapter   *adapter.SpecAdapter
+	installer     *adapter.PluginInstaller // nil unless plugin management is allowed
 	logger        *logging.Logger
 }
 
 // NewPluginService creates a new Plugin Service instance
 func NewPluginService(pluginDir string, logger *logging.Logger) *PluginService {
+	return NewPluginServiceFromAdapter(adapter.NewPluginAdapter(pluginDir, logger), logger)
+}
+
+// NewPluginServiceFromAdapter creates a Plugin Service that shares an existing
+// plugin adapter, so connection state is common with watchers and other services
+func NewPluginServiceFromAdapter(pluginAdapter *adapter.PluginAdapter, logger *logging.Logger) *PluginService {
+	return NewPluginServiceWithInstaller(pluginAdapter, nil, logger)
+}
+
+// NewPluginServiceWithInstaller creates a Plugin Service that can install,
+// upgrade and remove plugins. A nil installer disables those operations.
+func NewPluginServiceWithInstaller(pluginAdapter *adapter.PluginAdapter, installer *adapter.PluginInstaller, logger *logging.Logger) *PluginService {
 	return &PluginService{
-		pluginAdapter: adapter.NewPluginAdapter(pluginDir, logger),
-		specAdapter:   adapter.NewSpecAdapter(logger),
+		pluginAdapter: pluginAdapter,
+		specAdapter:   adapter.NewSpecAdapterWithPlugins(pluginAdapter, logger),
+		installer:     installer,
 		logger:        logger,
 	}
 }
@@ -49,14 +65,7 @@ func (s *PluginService) List(ctx context.Context, payload *plugin.ListPayload) (
 	// If health check requested, check each plugin
 	if payload.IncludeHealth {
 		for _, p := range plugins {
-			status, latency, _ := s.pluginAdapter.HealthCheck(ctx, p)
-			lastCheck := time.Now().Format(time.RFC3339)
-			latencyMs := latency
-			p.HealthStatus = &plugin.HealthStatus{
-				Status:    status,
-				LastCheck: &lastCheck,
-				LatencyMs: &latencyMs,
-			}
+			p.HealthStatus = s.checkHealth(ctx, p)
 		}
 	}
 
@@ -77,126 +86,129 @@ func (s *PluginService) List(ctx context.Context, payload *plugin.ListPayload) (
 
 // GetInfo returns detailed information about a specific plugin
 func (s *PluginService) GetInfo(ctx context.Context, payload *plugin.GetInfoPayload) (*plugin.GetInfoResult, error) {
+	ctx, span := tracing.Start(ctx, "PluginService.GetInfo")
+	defer span.End()
+
 	// Validate plugin name
 	if payload.PluginName == "" {
 		return nil, fmt.Errorf("plugin name cannot be empty")
 	}
 
-	// Mock plugin database
-	mockPlugins := map[string]*plugin.GetInfoResult{
-		"aws-cost-source": {
-			Name:        "aws-cost-source",
-			Version:     "v1.0.0",
-			Description: stringPtr("AWS Cost and Usage Report data source"),
-			Capabilities: &plugin.PluginCapabilities{
-				SupportsProjected: false,
-				SupportsActual:    true,
-				SupportsProviders: []string{"aws"},
-			},
-			HealthStatus: &plugin.HealthStatus{
-				Status:    "healthy",
-				LastCheck: stringPtr(time.Now().Add(-5 * time.Minute).Format(time.RFC3339)),
-				LatencyMs: int64Ptr(12),
-			},
-			GrpcAddress: stringPtr("localhost:50051"),
-			Configuration: map[string]any{
-				"region":       "us-east-1",
-				"bucket":       "my-cur-bucket",
-				"report_name":  "cost-usage-report",
-				"poll_interval": "1h",
-			},
-		},
-		"azure-cost-source": {
-			Name:        "azure-cost-source",
-			Version:     "v1.0.0",
-			Description: stringPtr("Azure Cost Management data source"),
-			Capabilities: &plugin.PluginCapabilities{
-				SupportsProjected: false,
-				SupportsActual:    true,
-				SupportsProviders: []string{"azure"},
-			},
-			HealthStatus: &plugin.HealthStatus{
-				Status:    "healthy",
-				LastCheck: stringPtr(time.Now().Add(-3 * time.Minute).Format(time.RFC3339)),
-				LatencyMs: int64Ptr(18),
-			},
-			GrpcAddress: stringPtr("localhost:50052"),
-			Configuration: map[string]any{
-				"subscription_id": "12345678-1234-1234-1234-123456789012",
-				"poll_interval":  "30m",
-			},
-		},
-		"infracost-plugin": {
-			Name:        "infracost-plugin",
-			Version:     "v0.10.0",
-			Description: stringPtr("Infracost-based projected cost estimation"),
-			Capabilities: &plugin.PluginCapabilities{
-				SupportsProjected: true,
-				SupportsActual:    false,
-				SupportsProviders: []string{"aws", "azure", "gcp"},
-			},
-			HealthStatus: &plugin.HealthStatus{
-				Status:    "healthy",
-				LastCheck: stringPtr(time.Now().Add(-1 * time.Minute).Format(time.RFC3339)),
-				LatencyMs: int64Ptr(25),
-			},
-			GrpcAddress: stringPtr("localhost:50053"),
-			Configuration: map[string]any{
-				"api_key":       "***",
-				"cache_enabled": true,
-				"currency":      "USD",
-			},
-		},
-	}
-
-	result, exists := mockPlugins[payload.PluginName]
-	if !exists {
-		return nil, &plugin.NotFoundError{
-			Message:  fmt.Sprintf("plugin '%s' not found", payload.PluginName),
-			Resource: &payload.PluginName,
+	tracing.SetAttributes(ctx, attribute.String("plugin_name", payload.PluginName))
+
+	details, err := s.pluginAdapter.DescribePlugin(ctx, payload.PluginName)
+	if err != nil {
+		if errors.Is(err, adapter.ErrPluginNotFound) {
+			return nil, &plugin.NotFoundError{
+				Message:  fmt.Sprintf("plugin '%s' not found", payload.PluginName),
+				Resource: &payload.PluginName,
+			}
 		}
+		s.logger.WithService("plugin").Error("failed to describe plugin", "plugin", payload.PluginName, "error", err)
+		return nil, fmt.Errorf("describe plugin: %w", err)
+	}
+
+	result := &plugin.GetInfoResult{
+		Name:          details.Plugin.Name,
+		Version:       details.Plugin.Version,
+		Description:   details.Plugin.Description,
+		Capabilities:  details.Plugin.Capabilities,
+		HealthStatus:  details.Plugin.HealthStatus,
+		Configuration: details.Configuration,
+		Integrity:     convertIntegrity(details.Integrity),
 	}
+	if details.GRPCAddress != "" {
+		result.GrpcAddress = stringPtr(details.GRPCAddress)
+	}
+
+	tracing.SetAttributes(ctx, attribute.String("integrity_status", string(details.Integrity.Status)))
 
 	return result, nil
 }
 
-// Validate runs conformance tests on a plugin
+// convertIntegrity maps an adapter integrity result to the API type
+func convertIntegrity(r adapter.IntegrityResult) *plugin.PluginIntegrity {
+	integrity := &plugin.PluginIntegrity{Status: string(r.Status)}
+	if r.KeyID != "" {
+		integrity.KeyID = stringPtr(r.KeyID)
+	}
+	if r.Reason != "" {
+		integrity.Reason = stringPtr(r.Reason)
+	}
+	return integrity
+}
+
+// Validate launches the plugin directory or binary at plugin_path, or uses an
+// installed plugin of that name, and runs conformance tests on it
 func (s *PluginService) Validate(ctx context.Context, payload *plugin.ValidatePayload) (*plugin.PluginValidationReport, error) {
 	// Validate inputs
 	if payload.PluginPath == "" {
 		return nil, fmt.Errorf("plugin path cannot be empty")
 	}
 
-	s.logger.WithService("plugin").Info("validating plugin",
-		"path", payload.PluginPath,
-		"level", payload.ConformanceLevel)
+	// An omitted level takes the design default
+	levelName := payload.ConformanceLevel
+	if levelName == "" {
+		levelName = adapter.ConformanceStandard.String()
+	}
+	level, err := adapter.ParseConformanceLevel(levelName)
+	if err != nil {
+		field := "conformance_level"
+		return nil, &plugin.ValidationError{Message: err.Error(), Field: &field, Value: &payload.ConformanceLevel}
+	}
+
+	// Reject an unknown output format before launching anything
+	var format report.Format
+	if payload.OutputFormat != nil {
+		format, err = report.ParseFormat(*payload.OutputFormat)
+		if err != nil {
+			field := "output_format"
+			return nil, &plugin.ValidationError{Message: err.Error(), Field: &field, Value: payload.OutputFormat}
+		}
+	}
 
-	// Create plugin object for validation
-	p := &plugin.Plugin{
-		Name:    payload.PluginPath, // Use path as name for now
-		Version: "unknown",
+	var fixtures []adapter.CostFixture
+	if payload.FixturesPath != nil && *payload.FixturesPath != "" {
+		fixtures, err = adapter.LoadCostFixtures(*payload.FixturesPath)
+		if err != nil {
+			field := "fixtures_path"
+			return nil, &plugin.ValidationError{Message: err.Error(), Field: &field, Value: payload.FixturesPath}
+		}
 	}
 
-	// Use spec adapter to validate plugin
-	report, err := s.specAdapter.ValidatePlugin(ctx, p, payload.ConformanceLevel)
+	s.logger.WithService("plugin").Info("validating plugin",
+		"path", payload.PluginPath,
+		"level", level)
+
+	// Launch the plugin at the path and run the suites against it
+	result, err := s.specAdapter.ValidatePath(ctx, payload.PluginPath, level, fixtures)
 	if err != nil {
 		s.logger.WithService("plugin").Warn("plugin validation encountered error",
 			"plugin", payload.PluginPath,
 			"error", err)
-
-		// If it's an invalid conformance level error, return error without report
-		if report != nil && !report.Passed && err.Error() == "invalid conformance level: "+payload.ConformanceLevel {
-			return nil, err
+		if errors.Is(err, adapter.ErrInvalidInput) {
+			field := "plugin_path"
+			return nil, &plugin.ValidationError{Message: err.Error(), Field: &field, Value: &payload.PluginPath}
 		}
-
-		// Otherwise return report even if there was an error (partial validation)
-		if report != nil {
-			return report, nil
+		if errors.Is(err, adapter.ErrLaunchForbidden) {
+			metrics.RecordError("plugin", "validate", "forbidden")
+			return nil, &plugin.ForbiddenError{
+				Message: err.Error() + "; set plugins.validation.allow_launch and plugins.validation.roots to launch plugins by path",
+			}
 		}
 		return nil, fmt.Errorf("validate plugin: %w", err)
 	}
 
-	return report, nil
+	if payload.OutputFormat != nil {
+		rendered, err := report.Render(result, format)
+		if err != nil {
+			return nil, fmt.Errorf("render validation report: %w", err)
+		}
+		text := string(rendered)
+		result.Rendered = &text
+	}
+
+	return result, nil
 }
 
 // HealthCheck checks plugin health and connectivity
@@ -218,33 +230,12 @@ func (s *PluginService) HealthCheck(ctx context.Context, payload *plugin.HealthC
 
 	tracing.SetAttributes(ctx, attribute.String("plugin_name", payload.PluginName))
 
-	// Mock plugin health status database
-	mockHealthStatus := map[string]*plugin.HealthStatus{
-		"aws-cost-source": {
-			Status:    "healthy",
-			LastCheck: stringPtr(time.Now().Format(time.RFC3339)),
-			LatencyMs: int64Ptr(12),
-		},
-		"azure-cost-source": {
-			Status:    "healthy",
-			LastCheck: stringPtr(time.Now().Format(time.RFC3339)),
-			LatencyMs: int64Ptr(18),
-		},
-		"infracost-plugin": {
-			Status:    "healthy",
-			LastCheck: stringPtr(time.Now().Format(time.RFC3339)),
-			LatencyMs: int64Ptr(25),
-		},
-		"slow-plugin": {
-			Status:       "degraded",
-			LastCheck:    stringPtr(time.Now().Format(time.RFC3339)),
-			LatencyMs:    int64Ptr(500),
-			ErrorMessage: stringPtr("High latency detected"),
-		},
-	}
-
-	status, exists := mockHealthStatus[payload.PluginName]
-	if !exists {
+	if _, err := s.pluginAdapter.DescribePlugin(ctx, payload.PluginName); err != nil {
+		if !errors.Is(err, adapter.ErrPluginNotFound) {
+			metrics.RecordError("plugin", "health_check", "internal")
+			tracing.RecordError(ctx, err)
+			return nil, fmt.Errorf("describe plugin: %w", err)
+		}
 		err := &plugin.NotFoundError{
 			Message:  fmt.Sprintf("plugin '%s' not found", payload.PluginName),
 			Resource: &payload.PluginName,
@@ -257,6 +248,8 @@ func (s *PluginService) HealthCheck(ctx context.Context, payload *plugin.HealthC
 		return nil, err
 	}
 
+	status := s.checkHealth(ctx, &plugin.Plugin{Name: payload.PluginName})
+
 	// Record plugin health metrics
 	pluginStatus := "success"
 	if status.Status != "healthy" {
@@ -282,6 +275,137 @@ func (s *PluginService) HealthCheck(ctx context.Context, payload *plugin.HealthC
 	return status, nil
 }
 
+// Install unpacks, validates and activates a new plugin
+func (s *PluginService) Install(ctx context.Context, payload *plugin.InstallPayload) (*plugin.PluginOperationResult, error) {
+	src := adapter.InstallSource{
+		ArchivePath: derefString(payload.ArchivePath),
+		SHA256:      derefString(payload.Sha256),
+		Name:        derefString(payload.PluginName),
+		Version:     derefString(payload.Version),
+	}
+
+	return s.managePlugin(ctx, "install", src.Name, func(installer *adapter.PluginInstaller) (*adapter.InstallResult, string, error) {
+		result, err := installer.Install(ctx, src)
+		return result, "installed", err
+	})
+}
+
+// Upgrade replaces an installed plugin or rolls it back to the kept version
+func (s *PluginService) Upgrade(ctx context.Context, payload *plugin.UpgradePayload) (*plugin.PluginOperationResult, error) {
+	if payload.PluginName == "" {
+		return nil, &plugin.ValidationError{Message: "plugin name cannot be empty"}
+	}
+
+	return s.managePlugin(ctx, "upgrade", payload.PluginName, func(installer *adapter.PluginInstaller) (*adapter.InstallResult, string, error) {
+		if payload.Rollback {
+			result, err := installer.Rollback(ctx, payload.PluginName)
+			return result, "rolled_back", err
+		}
+		result, err := installer.Upgrade(ctx, payload.PluginName, adapter.InstallSource{
+			ArchivePath: derefString(payload.ArchivePath),
+			SHA256:      derefString(payload.Sha256),
+			Version:     derefString(payload.Version),
+		})
+		return result, "upgraded", err
+	})
+}
+
+// Remove deactivates an installed plugin, keeping it for rollback
+func (s *PluginService) Remove(ctx context.Context, payload *plugin.RemovePayload) (*plugin.PluginOperationResult, error) {
+	if payload.PluginName == "" {
+		return nil, &plugin.ValidationError{Message: "plugin name cannot be empty"}
+	}
+
+	return s.managePlugin(ctx, "remove", payload.PluginName, func(installer *adapter.PluginInstaller) (*adapter.InstallResult, string, error) {
+		result, err := installer.Remove(ctx, payload.PluginName)
+		return result, "removed", err
+	})
+}
+
+// managePlugin runs a plugin management operation with the shared gating,
+// error mapping, metrics and logging
+func (s *PluginService) managePlugin(ctx context.Context, method, pluginName string, op func(*adapter.PluginInstaller) (*adapter.InstallResult, string, error)) (*plugin.PluginOperationResult, error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "PluginService."+method)
+	defer span.End()
+
+	if s.installer == nil {
+		metrics.RecordError("plugin", method, "forbidden")
+		return nil, &plugin.ForbiddenError{
+			Message: "plugin management is disabled; set plugins.management.allow_mutation to enable it",
+		}
+	}
+
+	tracing.SetAttributes(ctx, attribute.String("plugin_name", pluginName))
+
+	result, action, err := op(s.installer)
+	if err != nil {
+		s.logger.WithService("plugin").ErrorJSON("plugin "+method+" failed", err, map[string]interface{}{
+			"plugin": pluginName,
+		})
+		tracing.RecordError(ctx, err)
+
+		switch {
+		case errors.Is(err, adapter.ErrPluginNotFound):
+			metrics.RecordError("plugin", method, "not_found")
+			return nil, &plugin.NotFoundError{Message: err.Error(), Resource: stringPtr(pluginName)}
+		case errors.Is(err, adapter.ErrInvalidInput), errors.Is(err, adapter.ErrPluginExists):
+			metrics.RecordError("plugin", method, "validation")
+			return nil, &plugin.ValidationError{Message: err.Error()}
+		default:
+			metrics.RecordError("plugin", method, "internal")
+			return nil, fmt.Errorf("%s plugin: %w", method, err)
+		}
+	}
+
+	metrics.RecordRequest("plugin", method, time.Since(start))
+	s.logger.WithService("plugin").InfoJSON("plugin "+action, map[string]interface{}{
+		"plugin":           result.Name,
+		"version":          result.Version,
+		"previous_version": result.PreviousVersion,
+		"duration_ms":      time.Since(start).Milliseconds(),
+	})
+
+	return convertOperationResult(result, action), nil
+}
+
+// convertOperationResult maps an installer result to the API type
+func convertOperationResult(r *adapter.InstallResult, action string) *plugin.PluginOperationResult {
+	result := &plugin.PluginOperationResult{
+		PluginName: r.Name,
+		Action:     action,
+		Validation: r.Validation,
+	}
+	if r.Version != "" {
+		result.Version = stringPtr(r.Version)
+	}
+	if r.PreviousVersion != "" {
+		result.PreviousVersion = stringPtr(r.PreviousVersion)
+	}
+	if r.RollbackPath != "" {
+		result.RollbackPath = stringPtr(r.RollbackPath)
+	}
+	if r.Integrity.Status != "" {
+		result.Integrity = convertIntegrity(r.Integrity)
+	}
+	return result
+}
+
+// checkHealth runs a plugin health check, reporting failures in the status
+// rather than as errors
+func (s *PluginService) checkHealth(ctx context.Context, p *plugin.Plugin) *plugin.HealthStatus {
+	status, latency, err := s.pluginAdapter.HealthCheck(ctx, p)
+	result := &plugin.HealthStatus{
+		Status:    status,
+		LastCheck: stringPtr(time.Now().Format(time.RFC3339)),
+		LatencyMs: int64Ptr(latency),
+	}
+	if err != nil {
+		result.ErrorMessage = stringPtr(err.Error())
+	}
+	return result
+}
+
 // Helper functions
 func int64Ptr(i int64) *int64 {
 	return &i
@@ -290,3 +414,10 @@ func int64Ptr(i int64) *int64 {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/rshade/pulumicost-mcp/gen/plugin"
+	"github.com/rshade/pulumicost-mcp/internal/adapter"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -65,7 +68,17 @@ func TestList_WithHealthCheck(t *testing.T) {
 
 // TestGetInfo tests getting detailed plugin information
 func TestGetInfo(t *testing.T) {
-	service := NewPluginService("/tmp/plugins", nil)
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "aws-cost-source"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "aws-cost-source", "plugin.json"), []byte(`{
+		"name": "aws-cost-source",
+		"version": "v1.0.0",
+		"providers": "aws",
+		"grpc_address": "localhost:50051",
+		"capabilities": {"supports_actual_cost": true}
+	}`), 0644))
+
+	service := NewPluginService(pluginDir, nil)
 	ctx := context.Background()
 
 	payload := &plugin.GetInfoPayload{
@@ -81,6 +94,43 @@ func TestGetInfo(t *testing.T) {
 	assert.NotNil(t, result.Capabilities)
 	assert.True(t, result.Capabilities.SupportsActual)
 	assert.Contains(t, result.Capabilities.SupportsProviders, "aws")
+	require.NotNil(t, result.Integrity)
+	assert.Equal(t, "not_checked", result.Integrity.Status)
+}
+
+// TestGetInfo_Configuration tests merged configuration is reported with secrets redacted
+func TestGetInfo_Configuration(t *testing.T) {
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "aws-cost-source"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "aws-cost-source", "plugin.json"), []byte(`{
+		"name": "aws-cost-source",
+		"version": "v1.0.0",
+		"providers": "aws",
+		"grpc_address": "localhost:50051",
+		"capabilities": {"supports_actual_cost": true},
+		"config_schema": {
+			"type": "object",
+			"required": ["region", "api_key"],
+			"properties": {
+				"region": {"type": "string"},
+				"api_key": {"type": "string", "secret": true}
+			}
+		},
+		"configuration": {"region": "us-east-1"}
+	}`), 0644))
+
+	pluginAdapter := adapter.NewPluginAdapterWithOptions(pluginDir, adapter.PluginAdapterOptions{
+		Configuration: map[string]map[string]any{
+			"aws-cost-source": {"api_key": "s3cr3t"},
+		},
+	}, nil)
+	service := NewPluginServiceFromAdapter(pluginAdapter, nil)
+
+	result, err := service.GetInfo(context.Background(), &plugin.GetInfoPayload{PluginName: "aws-cost-source"})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"region": "us-east-1", "api_key": "***"}, result.Configuration)
+	assert.Nil(t, result.HealthStatus)
 }
 
 // TestGetInfo_NotFound tests getting info for non-existent plugin
@@ -116,31 +166,111 @@ func TestGetInfo_EmptyName(t *testing.T) {
 
 // TestValidate tests plugin conformance validation
 func TestValidate(t *testing.T) {
-	service := NewPluginService("/tmp/plugins", nil)
+	pluginDir := filepath.Join(t.TempDir(), "broken-plugin")
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "plugin.json"), []byte(`{
+		"name": "broken-plugin",
+		"version": "1.0.0",
+		"providers": "aws",
+		"capabilities": {"supports_projected_cost": true}
+	}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "bin", "broken-plugin"), []byte("#!/bin/sh\nexit 1\n"), 0755))
+
+	service := NewPluginServiceFromAdapter(adapter.NewPluginAdapterWithOptions(t.TempDir(), adapter.PluginAdapterOptions{
+		Launch: adapter.LaunchOptions{Allow: true, Roots: []string{filepath.Dir(pluginDir)}},
+	}, nil), nil)
 	ctx := context.Background()
 
 	payload := &plugin.ValidatePayload{
-		PluginPath:       "/path/to/plugin",
-		ConformanceLevel: "basic", // Use lowercase - spec adapter expects lowercase
+		PluginPath:       pluginDir,
+		ConformanceLevel: "basic", // Levels are case-insensitive
 	}
 
 	result, err := service.Validate(ctx, payload)
 
-	// Validation may return error if plugin doesn't exist (expected)
-	// But result should still be populated
+	// The plugin is launched and exits at once, which fails validation
+	require.NoError(t, err)
 	require.NotNil(t, result)
-	assert.Equal(t, "/path/to/plugin", result.PluginName)
-	assert.Equal(t, "basic", result.ConformanceLevel)
-
-	// Error is expected since plugin doesn't actually exist
-	if err == nil {
-		t.Log("Validation succeeded (plugin must exist)")
-	} else {
-		t.Logf("Validation returned expected error: %v", err)
-		assert.False(t, result.Passed, "should not pass validation without running plugin")
+	assert.Equal(t, "broken-plugin", result.PluginName)
+	assert.Equal(t, "BASIC", result.ConformanceLevel)
+	assert.False(t, result.Passed, "should not pass validation when the plugin exits")
+	require.NotEmpty(t, result.TestResults)
+	assert.Equal(t, "basic/startup", result.TestResults[0].Name)
+	assert.Nil(t, result.Rendered, "reports are only rendered on request")
+
+	format := "JUnit"
+	payload.OutputFormat = &format
+	result, err = service.Validate(ctx, payload)
+	require.NoError(t, err)
+	require.NotNil(t, result.Rendered)
+	assert.Contains(t, *result.Rendered, `<testcase name="startup" classname="broken-plugin.basic"`)
+}
+
+// TestValidate_LaunchForbidden verifies plugins are only launched by path
+// when allowed, from allowed roots and, in enforce mode, when verified
+func TestValidate_LaunchForbidden(t *testing.T) {
+	root := t.TempDir()
+	pluginDir := filepath.Join(root, "unsigned-plugin")
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "plugin.json"), []byte(`{"name": "unsigned-plugin", "version": "1.0.0"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "bin", "unsigned-plugin"), []byte("#!/bin/sh\nexit 1\n"), 0755))
+
+	tests := map[string]adapter.PluginAdapterOptions{
+		"disabled":      {},
+		"outside roots": {Launch: adapter.LaunchOptions{Allow: true, Roots: []string{t.TempDir()}}},
+		"unverified": {
+			Launch:    adapter.LaunchOptions{Allow: true, Roots: []string{root}},
+			Integrity: adapter.IntegrityOptions{Mode: adapter.IntegrityEnforce},
+		},
+	}
+	for name, options := range tests {
+		t.Run(name, func(t *testing.T) {
+			service := NewPluginServiceFromAdapter(adapter.NewPluginAdapterWithOptions(t.TempDir(), options, nil), nil)
+
+			result, err := service.Validate(context.Background(), &plugin.ValidatePayload{
+				PluginPath:       pluginDir,
+				ConformanceLevel: "BASIC",
+			})
+
+			var forbidden *plugin.ForbiddenError
+			require.ErrorAs(t, err, &forbidden)
+			assert.Nil(t, result)
+		})
 	}
 }
 
+// TestValidate_InvalidOutputFormat tests that unknown output formats are rejected
+func TestValidate_InvalidOutputFormat(t *testing.T) {
+	service := NewPluginService("/tmp/plugins", nil)
+
+	format := "pdf"
+	result, err := service.Validate(context.Background(), &plugin.ValidatePayload{
+		PluginPath:   "/path/to/plugin",
+		OutputFormat: &format,
+	})
+
+	var validationErr *plugin.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "output_format", *validationErr.Field)
+	assert.Nil(t, result)
+}
+
+// TestValidate_InvalidFixturesPath tests that an unreadable fixtures directory is rejected
+func TestValidate_InvalidFixturesPath(t *testing.T) {
+	service := NewPluginService("/tmp/plugins", nil)
+
+	fixturesPath := filepath.Join(t.TempDir(), "missing")
+	result, err := service.Validate(context.Background(), &plugin.ValidatePayload{
+		PluginPath:   "/path/to/plugin",
+		FixturesPath: &fixturesPath,
+	})
+
+	var validationErr *plugin.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "fixtures_path", *validationErr.Field)
+	assert.Nil(t, result)
+}
+
 // TestValidate_InvalidPath tests validation with invalid path
 func TestValidate_InvalidPath(t *testing.T) {
 	service := NewPluginService("/tmp/plugins", nil)
@@ -155,6 +285,14 @@ func TestValidate_InvalidPath(t *testing.T) {
 
 	require.Error(t, err)
 	assert.Nil(t, result)
+
+	payload.PluginPath = "/path/to/missing-plugin"
+	result, err = service.Validate(ctx, payload)
+
+	var validationErr *plugin.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "plugin_path", *validationErr.Field)
+	assert.Nil(t, result)
 }
 
 // TestValidate_InvalidConformanceLevel tests validation with invalid conformance level
@@ -172,11 +310,27 @@ func TestValidate_InvalidConformanceLevel(t *testing.T) {
 	require.Error(t, err)
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "invalid conformance level")
+
+	var validationErr *plugin.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.NotNil(t, validationErr.Field)
+	assert.Equal(t, "conformance_level", *validationErr.Field)
 }
 
 // TestHealthCheck tests plugin health check
 func TestHealthCheck(t *testing.T) {
-	service := NewPluginService("/tmp/plugins", nil)
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "aws-cost-source"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "aws-cost-source", "plugin.json"), []byte(`{
+		"name": "aws-cost-source",
+		"version": "v1.0.0",
+		"providers": "aws",
+		"grpc_address": "localhost:50051",
+		"capabilities": {"supports_actual_cost": true},
+		"config_schema": {"type": "object", "required": ["region"]}
+	}`), 0644))
+
+	service := NewPluginService(pluginDir, nil)
 	ctx := context.Background()
 
 	payload := &plugin.HealthCheckPayload{
@@ -187,13 +341,10 @@ func TestHealthCheck(t *testing.T) {
 
 	require.NoError(t, err)
 	require.NotNil(t, result)
-	assert.NotEmpty(t, result.Status)
-	assert.Contains(t, []string{"healthy", "unhealthy", "degraded"}, result.Status)
-
-	if result.Status == "healthy" {
-		assert.NotNil(t, result.LatencyMs)
-		assert.Greater(t, *result.LatencyMs, int64(0))
-	}
+	assert.Equal(t, "unhealthy", result.Status)
+	require.NotNil(t, result.ErrorMessage)
+	assert.Contains(t, *result.ErrorMessage, "configuration.region: is required")
+	assert.NotNil(t, result.LastCheck)
 }
 
 // TestHealthCheck_NotFound tests health check for non-existent plugin
@@ -226,3 +377,39 @@ func TestHealthCheck_EmptyName(t *testing.T) {
 	require.Error(t, err)
 	assert.Nil(t, result)
 }
+
+// TestInstall_Disabled tests that plugin management requires an installer
+func TestInstall_Disabled(t *testing.T) {
+	service := NewPluginService(t.TempDir(), nil)
+
+	result, err := service.Install(context.Background(), &plugin.InstallPayload{ArchivePath: stringPtr("/tmp/plugin.tar.gz")})
+
+	require.Error(t, err)
+	assert.Nil(t, result)
+	var forbidden *plugin.ForbiddenError
+	assert.ErrorAs(t, err, &forbidden)
+}
+
+// TestRemove tests removing a plugin keeps it for rollback and maps missing plugins to not found
+func TestRemove(t *testing.T) {
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "aws-cost-source"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "aws-cost-source", "plugin.json"),
+		[]byte(`{"name": "aws-cost-source", "version": "v1.0.0"}`), 0644))
+
+	pluginAdapter := adapter.NewPluginAdapter(pluginDir, nil)
+	service := NewPluginServiceWithInstaller(pluginAdapter, adapter.NewPluginInstaller(pluginAdapter, "", nil), nil)
+	ctx := context.Background()
+
+	result, err := service.Remove(ctx, &plugin.RemovePayload{PluginName: "aws-cost-source"})
+	require.NoError(t, err)
+	assert.Equal(t, "removed", result.Action)
+	require.NotNil(t, result.PreviousVersion)
+	assert.Equal(t, "v1.0.0", *result.PreviousVersion)
+	require.NotNil(t, result.RollbackPath)
+	assert.DirExists(t, *result.RollbackPath)
+
+	_, err = service.Remove(ctx, &plugin.RemovePayload{PluginName: "aws-cost-source"})
+	var notFound *plugin.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
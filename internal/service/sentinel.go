@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/gen/analysis"
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"github.com/rshade/pulumicost-mcp/internal/notify"
+)
+
+// SentinelOptions configures the scheduled budget and anomaly evaluation
+type SentinelOptions struct {
+	Interval time.Duration
+	// AnomalyStacks are checked for anomalies over the last day on each run
+	AnomalyStacks      []string
+	AnomalySensitivity string
+}
+
+// Sentinel periodically checks the defined budgets and watched stacks and
+// dispatches an alert for every threshold crossed
+type Sentinel struct {
+	analysis   *AnalysisService
+	dispatcher *notify.Dispatcher
+	options    SentinelOptions
+	logger     *logging.Logger
+}
+
+// NewSentinel creates a sentinel that evaluates through the analysis service
+func NewSentinel(analysisService *AnalysisService, dispatcher *notify.Dispatcher, options SentinelOptions, logger *logging.Logger) *Sentinel {
+	if logger == nil {
+		logger = logging.Default()
+	}
+	return &Sentinel{
+		analysis:   analysisService,
+		dispatcher: dispatcher,
+		options:    options,
+		logger:     logger,
+	}
+}
+
+// Run evaluates on every interval until the context is canceled
+func (s *Sentinel) Run(ctx context.Context) {
+	s.logger.Info("cost sentinel started", "interval", s.options.Interval.String(), "anomaly_stacks", len(s.options.AnomalyStacks))
+
+	ticker := time.NewTicker(s.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Evaluate(ctx); err != nil {
+			s.logger.Warn("cost sentinel evaluation failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Evaluate checks budgets and anomalies once and returns the number of
+// alerts delivered. Alerts still in their cooldown are not counted.
+func (s *Sentinel) Evaluate(ctx context.Context) (int, error) {
+	var errs []error
+	alerts, err := s.budgetAlerts(ctx)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("check budgets: %w", err))
+	}
+	for _, stack := range s.options.AnomalyStacks {
+		stackAlerts, err := s.anomalyAlerts(ctx, stack)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("detect anomalies of %s: %w", stack, err))
+			continue
+		}
+		alerts = append(alerts, stackAlerts...)
+	}
+
+	delivered := 0
+	for _, alert := range alerts {
+		sent, err := s.dispatcher.Dispatch(ctx, alert)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if sent {
+			delivered++
+		}
+	}
+	return delivered, errors.Join(errs...)
+}
+
+// budgetAlerts raises one alert per budget for the highest threshold crossed.
+// Budgets that could not be evaluated are returned as errors alongside the
+// alerts of the others.
+func (s *Sentinel) budgetAlerts(ctx context.Context) ([]notify.Alert, error) {
+	result, err := s.analysis.CheckAllBudgets(ctx, &analysis.CheckAllBudgetsPayload{})
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []notify.Alert
+	var errs []error
+	for _, check := range result.Checks {
+		if check.Error != nil {
+			errs = append(errs, fmt.Errorf("budget %s: %s", check.Definition.Name, *check.Error))
+			continue
+		}
+		if check.Budget == nil || len(check.Budget.Alerts) == 0 {
+			continue
+		}
+		highest := check.Budget.Alerts[0]
+		for _, alert := range check.Budget.Alerts[1:] {
+			if alert.Threshold > highest.Threshold {
+				highest = alert
+			}
+		}
+		alerts = append(alerts, notify.Alert{
+			Kind:     notify.KindBudget,
+			Key:      fmt.Sprintf("%s/%s/%g", check.Definition.Name, check.Budget.PeriodWindow.Start, highest.Threshold),
+			Severity: highest.Severity,
+			Message:  fmt.Sprintf("Budget %s: %s", check.Definition.Name, highest.Message),
+			Data:     check,
+		})
+	}
+	return alerts, errors.Join(errs...)
+}
+
+// anomalyAlerts raises one alert per anomaly the stack had over the last day
+func (s *Sentinel) anomalyAlerts(ctx context.Context, stack string) ([]notify.Alert, error) {
+	now := time.Now().UTC()
+	result, err := s.analysis.DetectAnomalies(ctx, &analysis.DetectAnomaliesPayload{
+		StackName: stack,
+		TimeRange: &analysis.TimeRange{
+			Start: now.Add(-24 * time.Hour).Format(time.RFC3339),
+			End:   now.Format(time.RFC3339),
+		},
+		Sensitivity: s.options.AnomalySensitivity,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make([]notify.Alert, 0, len(result.Anomalies))
+	for _, anomaly := range result.Anomalies {
+		message := fmt.Sprintf("Cost anomaly in %s: %.2f against a %.2f baseline", stack, anomaly.CurrentCost, anomaly.BaselineCost)
+		if len(anomaly.PotentialCauses) > 0 {
+			message = fmt.Sprintf("Cost anomaly in %s: %s", stack, anomaly.PotentialCauses[0])
+		}
+		alerts = append(alerts, notify.Alert{
+			Kind:      notify.KindAnomaly,
+			Key:       stack + "/" + anomaly.ID,
+			Severity:  anomaly.Severity,
+			Message:   message,
+			StackName: stack,
+			Data:      anomaly,
+		})
+	}
+	return alerts, nil
+}
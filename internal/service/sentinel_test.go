@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/internal/adapter"
+	"github.com/rshade/pulumicost-mcp/internal/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// alertRecorder collects alerts delivered by a sentinel
+type alertRecorder struct {
+	mu     sync.Mutex
+	alerts []notify.Alert
+}
+
+func (r *alertRecorder) Deliver(_ context.Context, alert notify.Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+// TestSentinel_BudgetAlerts verifies crossed budget thresholds are pushed once per cooldown
+func TestSentinel_BudgetAlerts(t *testing.T) {
+	budgets, err := LoadBudgets(writeBudgetsFile(t, `
+budgets:
+  - name: payments
+    amount: 50
+    period: MONTHLY
+    alert_thresholds: [50, 75]
+    scope:
+      stacks: [shop-dev]
+      tags: {team: payments}
+  - name: search
+    amount: 1000
+    period: MONTHLY
+    alert_thresholds: [50]
+    scope:
+      stacks: [shop-dev]
+      tags: {team: search}
+`))
+	require.NoError(t, err)
+
+	mockAdapter := adapter.NewPulumiCostAdapter(writeCoreScript(t, teamSpend))
+	analysisService := NewAnalysisServiceWithBudgets(mockAdapter, nil, 1, budgets, nil)
+	recorder := &alertRecorder{}
+	sentinel := NewSentinel(analysisService, notify.NewDispatcher([]notify.Sink{recorder}, time.Hour, nil), SentinelOptions{
+		Interval: time.Minute,
+	}, nil)
+
+	delivered, err := sentinel.Evaluate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	require.Len(t, recorder.alerts, 1)
+	assert.Equal(t, notify.KindBudget, recorder.alerts[0].Kind)
+	assert.Contains(t, recorder.alerts[0].Key, "payments/")
+	assert.Contains(t, recorder.alerts[0].Key, "/75")
+	assert.Contains(t, recorder.alerts[0].Message, "Budget payments")
+
+	delivered, err = sentinel.Evaluate(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, delivered, "same alert within cooldown")
+}
+
+// TestSentinel_AnomalyAlerts verifies anomalies of watched stacks are pushed
+func TestSentinel_AnomalyAlerts(t *testing.T) {
+	now := time.Now().UTC().Truncate(24 * time.Hour)
+	output := `{"total_monthly": 0, "currency": "USD", "resources": [{
+  "urn": "urn:pulumi:dev::shop::aws:ec2/instance:Instance::web",
+  "name": "web",
+  "type": "aws:ec2/instance:Instance",
+  "monthly_cost": 0,
+  "data_points": [`
+	for i := 6; i >= 0; i-- {
+		cost := "10.00"
+		if i == 0 {
+			cost = "50.00"
+		}
+		if i != 6 {
+			output += ","
+		}
+		output += `{"timestamp": "` + now.AddDate(0, 0, -i).Format(time.RFC3339) + `", "cost": ` + cost + `}`
+	}
+	output += `]}]}`
+
+	analysisService := NewAnalysisService(adapter.NewPulumiCostAdapter(writeCoreScript(t, output)), nil)
+	recorder := &alertRecorder{}
+	sentinel := NewSentinel(analysisService, notify.NewDispatcher([]notify.Sink{recorder}, time.Hour, nil), SentinelOptions{
+		Interval:           time.Minute,
+		AnomalyStacks:      []string{"shop-dev"},
+		AnomalySensitivity: "MEDIUM",
+	}, nil)
+
+	delivered, err := sentinel.Evaluate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	require.Len(t, recorder.alerts, 1)
+	assert.Equal(t, notify.KindAnomaly, recorder.alerts[0].Kind)
+	assert.Equal(t, "shop-dev", recorder.alerts[0].StackName)
+	assert.Equal(t, "CRITICAL", recorder.alerts[0].Severity)
+	assert.Contains(t, recorder.alerts[0].Message, "Daily cost of web rose to 50.00")
+}
+
+// TestSentinel_BudgetFailure verifies a budget that cannot be evaluated fails the run
+func TestSentinel_BudgetFailure(t *testing.T) {
+	budgets, err := LoadBudgets(writeBudgetsFile(t, `
+budgets:
+  - name: payments
+    amount: 50
+    period: MONTHLY
+    alert_thresholds: [50]
+    scope:
+      stacks: [shop-dev]
+`))
+	require.NoError(t, err)
+
+	mockAdapter := adapter.NewPulumiCostAdapter(writeCoreScript(t, "not json"))
+	analysisService := NewAnalysisServiceWithBudgets(mockAdapter, nil, 1, budgets, nil)
+	recorder := &alertRecorder{}
+	sentinel := NewSentinel(analysisService, notify.NewDispatcher([]notify.Sink{recorder}, time.Hour, nil), SentinelOptions{
+		Interval: time.Minute,
+	}, nil)
+
+	delivered, err := sentinel.Evaluate(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "budget payments")
+	assert.Zero(t, delivered)
+	assert.Empty(t, recorder.alerts)
+}
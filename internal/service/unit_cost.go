@@ -0,0 +1,184 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/gen/analysis"
+	"github.com/rshade/pulumicost-mcp/internal/adapter"
+)
+
+// metricPoint is one parsed value of a business metric
+type metricPoint struct {
+	at    time.Time
+	value float64
+}
+
+// metricSeries parses the metric series given inline or as a CSV file in
+// metricsDir. Exactly one of the two must be set.
+func metricSeries(points []*analysis.MetricPoint, file *string, metricsDir string) ([]metricPoint, error) {
+	hasFile := file != nil && *file != ""
+	switch {
+	case len(points) > 0 && hasFile:
+		return nil, fmt.Errorf("%w: set metric_points or metric_file, not both", adapter.ErrInvalidInput)
+	case hasFile:
+		return loadMetricCSV(metricsDir, *file)
+	case len(points) == 0:
+		return nil, fmt.Errorf("%w: metric_points or metric_file is required", adapter.ErrInvalidInput)
+	}
+
+	series := make([]metricPoint, 0, len(points))
+	for i, point := range points {
+		parsed, err := parseMetricPoint(point.Timestamp, point.Value)
+		if err != nil {
+			return nil, fmt.Errorf("metric point %d: %w", i, err)
+		}
+		series = append(series, parsed)
+	}
+	return series, nil
+}
+
+// loadMetricCSV reads timestamp,value rows from a CSV file in dir. The path
+// may be relative to dir or absolute, but cannot leave dir, even through
+// symlinks. A first row whose value is not a number is taken as a header.
+// Errors name the line but never echo the file's contents.
+func loadMetricCSV(dir, path string) ([]metricPoint, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("%w: metric_file is disabled; set unit_cost.metrics_dir to read metric files", adapter.ErrInvalidInput)
+	}
+	rel := path
+	if filepath.IsAbs(path) {
+		var err error
+		if rel, err = filepath.Rel(dir, path); err != nil {
+			rel = path
+		}
+	}
+	if !filepath.IsLocal(rel) {
+		return nil, fmt.Errorf("%w: metric_file must be inside the metrics directory", adapter.ErrInvalidInput)
+	}
+
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, fmt.Errorf("open metrics directory: %w", err)
+	}
+	defer root.Close()
+
+	f, err := root.Open(rel)
+	if err != nil {
+		return nil, fmt.Errorf("%w: open metric file: %w", adapter.ErrInvalidInput, err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var series []metricPoint
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: read metric file: %w", adapter.ErrInvalidInput, err)
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("%w: metric file line %d: want timestamp,value", adapter.ErrInvalidInput, line)
+		}
+
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("%w: metric file line %d: value is not a number", adapter.ErrInvalidInput, line)
+		}
+		point, err := parseMetricPoint(record[0], value)
+		if err != nil {
+			return nil, fmt.Errorf("metric file line %d: %w", line, err)
+		}
+		series = append(series, point)
+	}
+
+	if len(series) == 0 {
+		return nil, fmt.Errorf("%w: metric file %s has no rows", adapter.ErrInvalidInput, rel)
+	}
+	return series, nil
+}
+
+// parseMetricPoint accepts an RFC 3339 timestamp or a date and a non-negative value
+func parseMetricPoint(timestamp string, value float64) (metricPoint, error) {
+	timestamp = strings.TrimSpace(timestamp)
+	at, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		at, err = time.Parse("2006-01-02", timestamp)
+	}
+	if err != nil {
+		return metricPoint{}, fmt.Errorf("%w: invalid timestamp, want RFC 3339 or YYYY-MM-DD", adapter.ErrInvalidInput)
+	}
+	if value < 0 {
+		return metricPoint{}, fmt.Errorf("%w: metric value cannot be negative", adapter.ErrInvalidInput)
+	}
+	return metricPoint{at: at.UTC(), value: value}, nil
+}
+
+// unitCostWindows sums the metric per daily, weekly or monthly window, oldest
+// first. Weeks start on Monday.
+func unitCostWindows(series []metricPoint, granularity string) ([]*analysis.UnitCostPoint, error) {
+	byStart := make(map[time.Time]*analysis.UnitCostPoint)
+	for _, point := range series {
+		start, end, err := budgetWindow(strings.ToUpper(granularity), point.at)
+		if err != nil {
+			return nil, err
+		}
+		window, ok := byStart[start]
+		if !ok {
+			window = &analysis.UnitCostPoint{
+				Window: &analysis.TimeRange{Start: start.Format(time.RFC3339), End: end.Format(time.RFC3339)},
+			}
+			byStart[start] = window
+		}
+		window.MetricValue += point.value
+	}
+
+	windows := make([]*analysis.UnitCostPoint, 0, len(byStart))
+	for _, window := range byStart {
+		windows = append(windows, window)
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].Window.Start < windows[j].Window.Start })
+	return windows, nil
+}
+
+// applyUnitCosts adds the daily actual costs to the window containing each
+// day and divides by the metric. Days outside every window are ignored.
+func applyUnitCosts(windows []*analysis.UnitCostPoint, costs *adapter.CostResult, per float64) {
+	days := dailySpend(costs)
+	for _, window := range windows {
+		start, _ := time.Parse(time.RFC3339, window.Window.Start)
+		end, _ := time.Parse(time.RFC3339, window.Window.End)
+		for _, d := range days {
+			if !d.day.Before(start) && d.day.Before(end) {
+				window.Cost += d.cost
+			}
+		}
+		window.Cost = roundCents(window.Cost)
+		window.UnitCost = unitCost(window.Cost, window.MetricValue, per)
+	}
+}
+
+// unitCost returns cost per the given number of metric units, or nil without any metric
+func unitCost(cost, metric, per float64) *float64 {
+	if metric <= 0 {
+		return nil
+	}
+	value := cost / metric * per
+	return &value
+}
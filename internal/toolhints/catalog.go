@@ -0,0 +1,150 @@
+// Package toolhints adapts MCP tool descriptions to the cost source plugins
+// that are actually installed, so AI clients know which providers each tool
+// covers through plugins and which fall back to pulumicost-core.
+package toolhints
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rshade/pulumicost-mcp/gen/plugin"
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+)
+
+// MCP tool names whose descriptions depend on plugin capabilities
+const (
+	ToolAnalyzeProjected = "analyze_projected_costs"
+	ToolGetActual        = "get_actual_costs"
+	ToolRecommendations  = "get_optimization_recommendations"
+)
+
+// Hint describes how a tool's advertised definition should be adjusted
+type Hint struct {
+	// Note is appended to the tool's generated description
+	Note string
+}
+
+// DiscoverFunc returns the currently installed plugins
+type DiscoverFunc func(ctx context.Context) ([]*plugin.Plugin, error)
+
+// Catalog holds tool hints derived from the most recent plugin discovery
+type Catalog struct {
+	discover DiscoverFunc
+	logger   *logging.Logger
+
+	mu    sync.RWMutex
+	hints map[string]Hint
+}
+
+// NewCatalog creates a catalog that derives hints from the given discovery function
+func NewCatalog(discover DiscoverFunc, logger *logging.Logger) *Catalog {
+	if logger == nil {
+		logger = logging.Default()
+	}
+	return &Catalog{
+		discover: discover,
+		logger:   logger,
+		hints:    make(map[string]Hint),
+	}
+}
+
+// Refresh re-runs plugin discovery and rebuilds all tool hints
+func (c *Catalog) Refresh(ctx context.Context) error {
+	plugins, err := c.discover(ctx)
+	if err != nil {
+		return fmt.Errorf("discover plugins: %w", err)
+	}
+
+	hints := Build(plugins)
+
+	c.mu.Lock()
+	c.hints = hints
+	c.mu.Unlock()
+
+	c.logger.Info("tool hints refreshed", "plugins", len(plugins))
+	return nil
+}
+
+// Hint returns the current hint for a tool, if any
+func (c *Catalog) Hint(tool string) (Hint, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	hint, ok := c.hints[tool]
+	return hint, ok
+}
+
+// Build derives tool hints purely from the capabilities each plugin declares.
+// pulumicost-core serves every tool for providers no plugin covers, so no
+// tool is ever hidden.
+func Build(plugins []*plugin.Plugin) map[string]Hint {
+	projected := coverage(plugins, func(c *plugin.PluginCapabilities) bool { return c.SupportsProjected })
+	actual := coverage(plugins, func(c *plugin.PluginCapabilities) bool { return c.SupportsActual })
+	optimization := coverage(plugins, func(c *plugin.PluginCapabilities) bool { return c.SupportsOptimization })
+
+	hints := make(map[string]Hint)
+
+	if len(projected) == 0 {
+		hints[ToolAnalyzeProjected] = Hint{Note: "No installed plugin provides projected costs; pulumicost-core prices all resources."}
+	} else {
+		hints[ToolAnalyzeProjected] = Hint{
+			Note: "Projected-cost coverage from installed plugins: " + formatCoverage(projected) +
+				". Other providers are priced by pulumicost-core.",
+		}
+	}
+
+	if len(actual) == 0 {
+		hints[ToolGetActual] = Hint{Note: "No installed plugin provides actual costs; pulumicost-core reports them for all resources."}
+	} else {
+		hints[ToolGetActual] = Hint{
+			Note: "Actual-cost coverage from installed plugins: " + formatCoverage(actual) +
+				". Other providers are reported by pulumicost-core.",
+		}
+	}
+
+	if len(optimization) > 0 {
+		hints[ToolRecommendations] = Hint{
+			Note: "Plugin-provided optimization data available for: " + formatCoverage(optimization) + ".",
+		}
+	}
+
+	return hints
+}
+
+// coverage maps each provider to the plugins that support a capability for it
+func coverage(plugins []*plugin.Plugin, supports func(*plugin.PluginCapabilities) bool) map[string][]string {
+	byProvider := make(map[string][]string)
+	for _, p := range plugins {
+		if p.Capabilities == nil || !supports(p.Capabilities) {
+			continue
+		}
+		// Incompatible plugins are not routed to, so they provide no coverage
+		if p.Compatibility != nil && p.Compatibility.Status == "incompatible" {
+			continue
+		}
+		for _, provider := range p.Capabilities.SupportsProviders {
+			byProvider[provider] = append(byProvider[provider], p.Name)
+		}
+	}
+	return byProvider
+}
+
+// formatCoverage renders coverage as "aws (aws-cur, infracost), kubernetes (kubecost)"
+func formatCoverage(byProvider map[string][]string) string {
+	providers := make([]string, 0, len(byProvider))
+	for provider := range byProvider {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	parts := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		names := append([]string(nil), byProvider[provider]...)
+		sort.Strings(names)
+		parts = append(parts, fmt.Sprintf("%s (%s)", provider, strings.Join(names, ", ")))
+	}
+	return strings.Join(parts, ", ")
+}
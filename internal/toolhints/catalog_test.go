@@ -0,0 +1,154 @@
+package toolhints
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rshade/pulumicost-mcp/gen/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPlugins() []*plugin.Plugin {
+	return []*plugin.Plugin{
+		{
+			Name: "kubecost",
+			Capabilities: &plugin.PluginCapabilities{
+				SupportsProjected: true,
+				SupportsActual:    true,
+				SupportsProviders: []string{"kubernetes"},
+			},
+		},
+		{
+			Name: "aws-cur",
+			Capabilities: &plugin.PluginCapabilities{
+				SupportsActual:       true,
+				SupportsOptimization: true,
+				SupportsProviders:    []string{"aws"},
+			},
+		},
+	}
+}
+
+// TestBuild_Coverage verifies hints describe per-provider coverage
+func TestBuild_Coverage(t *testing.T) {
+	hints := Build(testPlugins())
+
+	projected := hints[ToolAnalyzeProjected]
+	assert.Contains(t, projected.Note, "kubernetes (kubecost)")
+	assert.NotContains(t, projected.Note, "aws")
+	assert.Contains(t, projected.Note, "Other providers are priced by pulumicost-core")
+
+	actual := hints[ToolGetActual]
+	assert.Contains(t, actual.Note, "aws (aws-cur), kubernetes (kubecost)")
+
+	assert.Contains(t, hints[ToolRecommendations].Note, "aws (aws-cur)")
+}
+
+// TestBuild_NoPlugins verifies tools stay advertised as served by pulumicost-core
+// without a capable plugin
+func TestBuild_NoPlugins(t *testing.T) {
+	hints := Build(nil)
+
+	assert.Contains(t, hints[ToolAnalyzeProjected].Note, "pulumicost-core prices all resources")
+	assert.Contains(t, hints[ToolGetActual].Note, "pulumicost-core reports them for all resources")
+	_, ok := hints[ToolRecommendations]
+	assert.False(t, ok)
+}
+
+// TestBuild_SkipsIncompatible verifies incompatible plugins provide no coverage
+func TestBuild_SkipsIncompatible(t *testing.T) {
+	plugins := []*plugin.Plugin{{
+		Name: "infracost",
+		Capabilities: &plugin.PluginCapabilities{
+			SupportsProjected: true,
+			SupportsProviders: []string{"aws"},
+		},
+		Compatibility: &plugin.PluginCompatibility{Status: "incompatible", Reason: "spec mismatch"},
+	}}
+
+	assert.Contains(t, Build(plugins)[ToolAnalyzeProjected].Note, "No installed plugin provides projected costs")
+}
+
+// TestCatalog_RefreshError verifies discovery failures keep the previous hints
+func TestCatalog_RefreshError(t *testing.T) {
+	fail := false
+	catalog := NewCatalog(func(ctx context.Context) ([]*plugin.Plugin, error) {
+		if fail {
+			return nil, errors.New("boom")
+		}
+		return testPlugins(), nil
+	}, nil)
+
+	require.NoError(t, catalog.Refresh(context.Background()))
+
+	fail = true
+	assert.Error(t, catalog.Refresh(context.Background()))
+
+	hint, ok := catalog.Hint(ToolAnalyzeProjected)
+	require.True(t, ok)
+	assert.Contains(t, hint.Note, "kubernetes (kubecost)")
+}
+
+// TestMiddleware_RewritesToolsList verifies tools/list responses reflect hints
+func TestMiddleware_RewritesToolsList(t *testing.T) {
+	catalog := NewCatalog(func(ctx context.Context) ([]*plugin.Plugin, error) {
+		return nil, nil
+	}, nil)
+	require.NoError(t, catalog.Refresh(context.Background()))
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"tools":[
+			{"name":"analyze_projected_costs","description":"Projected costs."},
+			{"name":"get_actual_costs","description":"Actual costs."},
+			{"name":"list_plugins","description":"Lists plugins."}
+		]}}`))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	rec := httptest.NewRecorder()
+	catalog.Middleware(next).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Result struct {
+			Tools []struct {
+				Name        string `json:"name"`
+				Description string `json:"description"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+	require.Len(t, response.Result.Tools, 3, "no tool is hidden")
+	assert.Equal(t, "analyze_projected_costs", response.Result.Tools[0].Name)
+	assert.True(t, strings.HasPrefix(response.Result.Tools[0].Description, "Projected costs. No installed plugin"))
+	assert.True(t, strings.HasPrefix(response.Result.Tools[1].Description, "Actual costs. No installed plugin"))
+	assert.Equal(t, "Lists plugins.", response.Result.Tools[2].Description)
+}
+
+// TestMiddleware_PassThrough verifies other methods are not modified
+func TestMiddleware_PassThrough(t *testing.T) {
+	catalog := NewCatalog(func(ctx context.Context) ([]*plugin.Plugin, error) {
+		return nil, nil
+	}, nil)
+	require.NoError(t, catalog.Refresh(context.Background()))
+
+	body := `{"jsonrpc":"2.0","id":1,"result":{"tools":[{"name":"analyze_projected_costs"}]}}`
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call"}`))
+	rec := httptest.NewRecorder()
+	catalog.Middleware(next).ServeHTTP(rec, req)
+
+	assert.Equal(t, body, rec.Body.String())
+}
@@ -0,0 +1,118 @@
+package toolhints
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Middleware rewrites JSON-RPC tools/list responses so tool descriptions
+// reflect the catalog's current hints. All other requests pass through untouched.
+func (c *Catalog) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var request struct {
+			Method string `json:"method"`
+		}
+		if json.Unmarshal(body, &request) != nil || request.Method != "tools/list" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		payload := recorder.body.Bytes()
+		if rewritten, err := c.rewriteToolsList(payload); err == nil {
+			payload = rewritten
+		} else {
+			c.logger.Warn("leaving tools/list response unmodified", "error", err)
+		}
+
+		for key, values := range recorder.header {
+			w.Header()[key] = values
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+		w.WriteHeader(recorder.status)
+		_, _ = w.Write(payload)
+	})
+}
+
+// rewriteToolsList applies hints to every tool in a tools/list JSON-RPC response
+func (c *Catalog) rewriteToolsList(payload []byte) ([]byte, error) {
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &response); err != nil {
+		return nil, err
+	}
+
+	rawResult, ok := response["result"]
+	if !ok {
+		// Error responses are passed through as-is
+		return payload, nil
+	}
+
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(rawResult, &result); err != nil {
+		return nil, err
+	}
+
+	var tools []map[string]any
+	if err := json.Unmarshal(result["tools"], &tools); err != nil {
+		return nil, err
+	}
+
+	for _, tool := range tools {
+		name, _ := tool["name"].(string)
+		if hint, ok := c.Hint(name); ok && hint.Note != "" {
+			description, _ := tool["description"].(string)
+			tool["description"] = strings.TrimSpace(description + " " + hint.Note)
+		}
+	}
+
+	encodedTools, err := json.Marshal(tools)
+	if err != nil {
+		return nil, err
+	}
+	result["tools"] = encodedTools
+
+	encodedResult, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	response["result"] = encodedResult
+
+	return json.Marshal(response)
+}
+
+// bufferedResponse captures a handler's response so it can be rewritten
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
@@ -0,0 +1,84 @@
+package e2e
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rshade/pulumicost-mcp/internal/adapter"
+	"github.com/rshade/pulumicost-mcp/internal/logging"
+	"github.com/rshade/pulumicost-mcp/internal/notify"
+	"github.com/stretchr/testify/require"
+)
+
+// TestToolsListChangedNotification verifies a plugin installed after a client
+// subscribed reaches it as notifications/tools/list_changed, even once the
+// server's write timeout has passed
+func TestToolsListChangedNotification(t *testing.T) {
+	logger := logging.Default()
+	pluginDir := t.TempDir()
+
+	hub := notify.NewHub(logger)
+	pluginAdapter := adapter.NewPluginAdapter(pluginDir, logger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	watcher := adapter.NewPluginWatcher(pluginAdapter, 50*time.Millisecond, func([]adapter.PluginChange) {
+		if err := hub.Notify(notify.MethodToolsListChanged, nil); err != nil {
+			t.Errorf("notify: %v", err)
+		}
+	})
+	go watcher.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /rpc/notifications", hub)
+	server := httptest.NewUnstartedServer(mux)
+	server.Config.WriteTimeout = 200 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/rpc/notifications", http.NoBody)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Install a plugin well after the write timeout has expired
+	time.Sleep(3 * server.Config.WriteTimeout)
+	installPlugin(t, pluginDir, "kubecost")
+
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err, "stream closed before tools/list_changed arrived")
+		if strings.HasPrefix(line, "data: ") && strings.Contains(line, notify.MethodToolsListChanged) {
+			return
+		}
+	}
+}
+
+// installPlugin writes a minimal plugin into dir
+func installPlugin(t *testing.T, dir, name string) {
+	t.Helper()
+
+	pluginDir := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(pluginDir, 0755))
+
+	metadata := `{
+		"name": "` + name + `",
+		"version": "1.0.0",
+		"providers": "kubernetes",
+		"grpc_address": "localhost:50051",
+		"capabilities": {"supports_projected_cost": true}
+	}`
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "plugin.json"), []byte(metadata), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, name), []byte("#!/bin/bash\necho 'mock plugin'"), 0755))
+}